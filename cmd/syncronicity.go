@@ -2,24 +2,37 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/docopt/docopt-go"
 	"go.uber.org/zap"
 
 	"github.com/TFMV/syncronicity/internal/config"
+	"github.com/TFMV/syncronicity/internal/logging"
 	"github.com/TFMV/syncronicity/pkg/bigquery" // Assume this package exists and is similarly designed.
+	"github.com/TFMV/syncronicity/pkg/deadletter"
+	"github.com/TFMV/syncronicity/pkg/events"
+	"github.com/TFMV/syncronicity/pkg/gateway"
+	"github.com/TFMV/syncronicity/pkg/logctx"
+	"github.com/TFMV/syncronicity/pkg/pipeline"
 	"github.com/TFMV/syncronicity/pkg/snowflake"
 )
 
 const usage = `Synchronicity: BigQuery to Snowflake Arrow Data Transfer
 
 Usage:
-  synchronicity [--project=<project>] [--dataset=<dataset>] [--table=<table>] [--service_account=<path>] [--snowflake_dsn=<dsn>] [--config=<config>]
+  synchronicity [--project=<project>] [--dataset=<dataset>] [--table=<table>] [--service_account=<path>] [--service_account_json=<json>] [--snowflake_dsn=<dsn>] [--config=<config>] [--validate] [--print_sql] [--status_addr=<addr>] [--arrow_stdout] [--tables_from_query=<sql>] [--dry_run]
+  synchronicity describe [--project=<project>] [--dataset=<dataset>] [--table=<table>] [--service_account=<path>] [--service_account_json=<json>] [--config=<config>] [--format=<format>]
+  synchronicity ddl [--project=<project>] [--dataset=<dataset>] [--table=<table>] [--service_account=<path>] [--service_account_json=<json>] [--config=<config>]
+  synchronicity flightsql [--project=<project>] [--service_account=<path>] [--service_account_json=<json>] [--config=<config>] [--addr=<addr>]
   synchronicity -h | --help
 
 Options:
@@ -27,19 +40,27 @@ Options:
   --dataset=<dataset>         BigQuery Dataset Name (overrides config)
   --table=<table>             BigQuery Table Name (overrides config)
   --service_account=<path>    Path to service account JSON file (overrides config)
+  --service_account_json=<json>  Service account JSON contents (overrides config and SYNC_GOOGLE_CREDENTIALS)
   --snowflake_dsn=<dsn>       Snowflake DSN (overrides config)
   --config=<config>           Path to config.yaml
+  --validate                  Round-trip a single batch through COPY VALIDATION_MODE without loading data.
+  --print_sql                 Print the Snowflake SQL statements to stdout instead of executing them.
+  --status_addr=<addr>        Serve transfer status on this address (e.g. localhost:8080) at /status and /healthz.
+  --arrow_stdout              Write the records to stdout as an Arrow IPC stream instead of loading Snowflake.
+  --tables_from_query=<sql>   Transfer every table named in the first column of this BigQuery query's result.
+  --dry_run                   Print a preflight report of the schema, size, target table, and SQL as JSON without moving data.
+  --format=<format>           Report format for describe: markdown (default) or csv.
+  --addr=<addr>               Address for the flightsql gateway to listen on [default: :32010].
   -h --help                   Show this screen.
 `
 
 func main() {
-	// Initialize structured logging.
-	logger, err := zap.NewDevelopment()
+	// Initialize structured logging; it is rebuilt once the config is loaded.
+	logger, err := logging.New(time.UTC, logging.TimeRFC3339)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to initialize logger: %v\n", err)
 		os.Exit(1)
 	}
-	defer logger.Sync()
 	sugar := logger.Sugar()
 
 	// Parse CLI arguments using docopt.
@@ -54,7 +75,19 @@ func main() {
 	cliDataset, _ := args.String("--dataset")
 	cliTable, _ := args.String("--table")
 	cliServiceAccount, _ := args.String("--service_account")
+	cliServiceAccountJSON, _ := args.String("--service_account_json")
 	cliSnowflakeDSN, _ := args.String("--snowflake_dsn")
+	validate, _ := args.Bool("--validate")
+	printSQL, _ := args.Bool("--print_sql")
+	cliStatusAddr, _ := args.String("--status_addr")
+	arrowStdout, _ := args.Bool("--arrow_stdout")
+	cliTablesQuery, _ := args.String("--tables_from_query")
+	dryRun, _ := args.Bool("--dry_run")
+	describe, _ := args.Bool("describe")
+	describeFormat, _ := args.String("--format")
+	ddl, _ := args.Bool("ddl")
+	flightSQL, _ := args.Bool("flightsql")
+	flightSQLAddr, _ := args.String("--addr")
 
 	// Load configuration from file.
 	cfg, err := config.LoadConfig(configPath)
@@ -62,57 +95,493 @@ func main() {
 		sugar.Fatalf("Failed to load configuration: %v", err)
 	}
 
+	// Render log timestamps in the configured zone and format. The zone is
+	// also passed to the pipeline and Snowflake client below, so reports,
+	// events, and dead-letter rows use it too, whatever the host's.
+	loc, err := logging.ParseTimeZone(cfg.GetString("log_timezone"))
+	if err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	timeFormat, err := logging.ParseTimeFormat(cfg.GetString("log_time_format"))
+	if err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	if logger, err = logging.New(loc, timeFormat); err != nil {
+		sugar.Fatalf("Failed to initialize logger: %v", err)
+	}
+	defer logger.Sync()
+	sugar = logger.Sugar()
+
 	// Merge CLI overrides with config file values.
 	project := mergeConfig(cliProject, cfg.GetString("project_id"))
 	dataset := mergeConfig(cliDataset, cfg.GetString("dataset"))
 	table := mergeConfig(cliTable, cfg.GetString("table"))
 	serviceAccount := mergeConfig(cliServiceAccount, cfg.GetString("service_account"))
-	snowflakeDSN := mergeConfig(cliSnowflakeDSN, cfg.GetString("snowflake_dsn"))
+	// Inline credentials can come from SYNC_GOOGLE_CREDENTIALS; never log them.
+	serviceAccountJSON := mergeConfig(cliServiceAccountJSON, cfg.GetString("google_credentials"))
+	snowflakeDSN := cliSnowflakeDSN
+	if snowflakeDSN == "" {
+		if snowflakeDSN, err = config.BuildDSN(cfg); err != nil {
+			sugar.Fatalf("Invalid configuration: %v", err)
+		}
+	}
 	stagePath := cfg.GetString("snowflake_stage")
+	statusAddr := mergeConfig(cliStatusAddr, cfg.GetString("status_addr"))
+	// Usually set through SYNC_STATUS_TOKEN; never log it.
+	statusToken := cfg.GetString("status_token")
+	tablesQuery := mergeConfig(cliTablesQuery, cfg.GetString("tables_from_query"))
+	if tablesQuery != "" && (arrowStdout || validate) {
+		sugar.Fatalf("--tables_from_query can't be combined with --arrow_stdout or --validate")
+	}
+	if dryRun && (arrowStdout || validate) {
+		sugar.Fatalf("--dry_run can't be combined with --arrow_stdout or --validate")
+	}
+	partitionColumn := cfg.GetString("partition_column")
+	sourceQuery := cfg.GetString("source_query")
+	if sourceQuery != "" && (tablesQuery != "" || partitionColumn != "") {
+		sugar.Fatalf("source_query can't be combined with --tables_from_query or partition_column")
+	}
+	if partitionColumn != "" && (tablesQuery != "" || arrowStdout || validate || bigquery.IsWildcardTable(table)) {
+		sugar.Fatalf("partition_column can't be combined with --tables_from_query, --arrow_stdout, --validate, or a wildcard table")
+	}
+	manifestLocation := cfg.GetString("manifest")
+	if manifestLocation != "" && tablesQuery != "" {
+		sugar.Fatalf("manifest can't be combined with --tables_from_query")
+	}
+	// Each table selected by --tables_from_query is loaded into a target of
+	// its own, named by snowflake_table_template.
+	tableTemplate := cfg.GetString("snowflake_table_template")
+	if tablesQuery != "" && cfg.GetString("snowflake_table") != "" {
+		sugar.Fatalf("snowflake_table can't be combined with --tables_from_query; use snowflake_table_template")
+	}
+	if tableTemplate != "" && tablesQuery == "" {
+		sugar.Fatalf("snowflake_table_template requires --tables_from_query")
+	}
+	if tableTemplate == "" {
+		tableTemplate = "{table}"
+	}
 
-	// Set the service account environment variable if provided.
-	if serviceAccount != "" {
-		if err := os.Setenv("GOOGLE_APPLICATION_CREDENTIALS", strings.TrimSpace(serviceAccount)); err != nil {
-			sugar.Fatalf("Failed to set GOOGLE_APPLICATION_CREDENTIALS: %v", err)
-		}
-		sugar.Infof("Service account set from: %s", serviceAccount)
+	mappingFormat, err := snowflake.ParseMappingFormat(describeFormat)
+	if err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
 	}
 
+	leakPolicy, err := pipeline.ParseLeakPolicy(cfg.GetString("record_leak_policy"))
+	if err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+
+	// Credentials are passed to each Google client rather than through
+	// GOOGLE_APPLICATION_CREDENTIALS; without any, clients use ADC. Only where
+	// they came from is logged, never their contents.
+	var googleOpts bigquery.ClientOptions
+	if serviceAccountJSON != "" {
+		googleOpts.Credentials = serviceAccountJSON
+		sugar.Infof("Service account credentials provided inline")
+	} else if serviceAccount != "" {
+		googleOpts.Credentials = serviceAccount
+		sugar.Infof("Service account credentials loaded from: %s", serviceAccount)
+	}
+	clientOpts := googleOpts.ClientOptions()
+
+	// Tag every log line of this run with a correlation ID.
+	correlationID := logctx.NewCorrelationID()
+
 	logger.Info("Starting Synchronicity",
+		zap.String("correlation_id", correlationID),
 		zap.String("project", project),
 		zap.String("dataset", dataset),
 		zap.String("table", table),
-		zap.String("snowflake_dsn", snowflakeDSN))
+		zap.String("snowflake_dsn", logging.RedactDSN(snowflakeDSN)))
 
 	// Create a context with timeout.
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
 	defer cancel()
+	ctx = logctx.WithCorrelationID(ctx, correlationID)
 
 	// Initialize the BigQuery client.
-	bqClient, err := bigquery.NewBigQueryReadClient(ctx)
+	bqClient, err := bigquery.NewBigQueryReadClientWithOptions(ctx, googleOpts)
 	if err != nil {
 		sugar.Fatalf("Failed to create BigQuery client: %v", err)
 	}
+	defer bqClient.Close()
 
-	// Create a reader for the specified BigQuery table.
-	reader, err := bqClient.NewBigQueryReader(ctx, project, dataset, table, &bigquery.BigQueryReaderOptions{
-		MaxStreamCount: 1,
-	})
+	schemaChange, err := bigquery.ParseSchemaChangePolicy(cfg.GetString("on_schema_change"))
 	if err != nil {
-		sugar.Fatalf("Failed to create BigQuery reader: %v", err)
+		sugar.Fatalf("Invalid configuration: %v", err)
 	}
-	defer reader.Close()
-
-	// Read the Arrow record from BigQuery.
-	record, err := reader.Read()
+	dataFormat, err := bigquery.ParseDataFormat(cfg.GetString("data_format"))
 	if err != nil {
-		sugar.Fatalf("Error reading Arrow record from BigQuery: %v", err)
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	readerOpts := &bigquery.BigQueryReaderOptions{
+		MaxStreamCount:    int32(max(cfg.GetInt("read_streams"), 1)),
+		MaxConcurrency:    cfg.GetInt("read_concurrency"),
+		DecodeConcurrency: cfg.GetInt("decode_concurrency"),
+		MaxCellsPerRecord: cfg.GetInt64("max_cells_per_record"),
+		DataFormat:        dataFormat,
+		OnSchemaChange:    schemaChange,
+		QuotaUser:         cfg.GetString("quota_user"),
+		MaxStaleness:      cfg.GetDuration("max_staleness"),
+		MaxStreamReopens:  cfg.GetInt("stream_reopens"),
+		SelectedColumns:   cfg.GetStringSlice("selected_columns"),
+		RowRestriction:    cfg.GetString("row_restriction"),
+		BillingProject:    cfg.GetString("billing_project"),
+		Logger:            logger,
+	}
+	if readerOpts.QuotaUser != "" {
+		if err := bigquery.ValidateQuotaUser(readerOpts.QuotaUser); err != nil {
+			sugar.Fatalf("Invalid configuration: %v", err)
+		}
+	}
+
+	if at := cfg.GetString("snapshot_time"); at != "" {
+		if sourceQuery != "" {
+			sugar.Fatalf("snapshot_time can't be combined with source_query")
+		}
+		if readerOpts.SnapshotTime, err = time.Parse(time.RFC3339, at); err != nil {
+			sugar.Fatalf("Invalid configuration: snapshot_time: %v", err)
+		}
+	}
+
+	// Optionally bound the Arrow memory of every reader and writer in the run.
+	var alloc memory.Allocator
+	if limit := cfg.GetInt64("memory_limit"); limit > 0 {
+		alloc = pipeline.NewBoundedAllocator(nil, limit, cfg.GetBool("memory_limit_block"))
+		readerOpts.Allocator = alloc
+	}
+
+	// openWith creates a reader for a BigQuery table, or for every shard when
+	// the table is a wildcard pattern such as "events_*". With source_query
+	// set, it reads the query's result instead.
+	openWith := func(ctx context.Context, table string, readerOpts *bigquery.BigQueryReaderOptions) (pipeline.RecordSource, error) {
+		if sourceQuery != "" {
+			reader, err := bqClient.NewBigQueryReaderFromQuery(ctx, project, sourceQuery, readerOpts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read query results: %w", err)
+			}
+			return reader, nil
+		}
+		if bigquery.IsWildcardTable(table) {
+			shards, err := bqClient.ListTableShards(ctx, project, dataset, table)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve table shards: %w", err)
+			}
+			logger.Info("Reading sharded tables", zap.String("pattern", table), zap.Int("shards", len(shards)))
+			sharded, err := bqClient.NewShardedReader(ctx, project, dataset, shards, readerOpts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create BigQuery shard reader: %w", err)
+			}
+			sharded.AddShardColumn = cfg.GetBool("add_shard_column")
+			return sharded, nil
+		}
+		// Check the table exists before opening a session, and log its size.
+		info, err := bqClient.TableInfo(ctx, project, dataset, table)
+		var notFound *bigquery.ErrTableNotFound
+		switch {
+		case errors.As(err, &notFound):
+			return nil, err
+		case err != nil:
+			logger.Warn("Failed to read BigQuery table size", zap.Error(err))
+		case info.RowsKnown:
+			logger.Info("BigQuery table size",
+				zap.String("table", table),
+				zap.Uint64("rows", info.NumRows),
+				zap.Int64("bytes", info.NumBytes))
+		}
+		if cacheDir := cfg.GetString("cache_dir"); cacheDir != "" {
+			// The read cache is strictly opt-in and intended for development loops.
+			cache, err := bigquery.NewReadCache(cacheDir, cfg.GetDuration("cache_ttl"), cfg.GetInt64("cache_max_bytes"))
+			if err != nil {
+				return nil, fmt.Errorf("failed to initialize read cache: %w", err)
+			}
+			cached, err := bqClient.NewCachedReader(ctx, cache, project, dataset, table, readerOpts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create BigQuery reader: %w", err)
+			}
+			logger.Info("BigQuery read cache enabled", zap.String("dir", cacheDir), zap.Bool("hit", cached.Hit()))
+			return cached, nil
+		}
+		reader, err := bqClient.NewBigQueryReader(ctx, project, dataset, table, readerOpts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create BigQuery reader: %w", err)
+		}
+		return reader, nil
+	}
+	open := func(ctx context.Context, table string) (pipeline.RecordSource, error) {
+		return openWith(ctx, table, readerOpts)
+	}
+
+	// The describe subcommand prints how each column maps to a Snowflake type,
+	// from the read session's Arrow schema, without loading anything.
+	if describe {
+		intervals, err := snowflake.ParseIntervalFormat(cfg.GetString("snowflake_interval_format"))
+		if err != nil {
+			sugar.Fatalf("Invalid configuration: %v", err)
+		}
+		reader, err := bqClient.NewBigQueryReader(ctx, project, dataset, table, readerOpts)
+		if err != nil {
+			sugar.Fatalf("Failed to open BigQuery table: %v", err)
+		}
+		defer reader.Close()
+		schema, err := reader.Schema()
+		if err != nil {
+			sugar.Fatalf("Failed to read BigQuery schema: %v", err)
+		}
+		if err := snowflake.WriteSchemaMapping(os.Stdout, snowflake.SchemaMapping(schema, intervals), mappingFormat); err != nil {
+			sugar.Fatalf("Failed to write schema mapping: %v", err)
+		}
+		return
+	}
+
+	// The flightsql subcommand serves BigQuery to Flight SQL clients until
+	// stopped; queries run in the configured project.
+	if flightSQL {
+		srv, err := gateway.NewServer(bqClient, project, readerOpts, logger)
+		if err != nil {
+			sugar.Fatalf("Failed to create Flight SQL gateway: %v", err)
+		}
+		if err := srv.ListenAndServe(flightSQLAddr); err != nil {
+			sugar.Fatalf("Flight SQL gateway failed: %v", err)
+		}
+		return
+	}
+
+	// In Arrow stdout mode, act as a composable Arrow source for shell pipelines.
+	// Logs go to stderr, so stdout carries only the IPC stream.
+	if arrowStdout {
+		reader, err := open(ctx, table)
+		if err != nil {
+			sugar.Fatalf("Failed to open BigQuery table: %v", err)
+		}
+		defer reader.Close()
+		rows, err := pipeline.WriteIPCStream(os.Stdout, reader)
+		if err != nil {
+			sugar.Fatalf("Failed to write Arrow IPC stream: %v", err)
+		}
+		logger.Info("Arrow IPC stream written to stdout", zap.Int64("rows", rows))
+		return
 	}
-	defer record.Release()
-	logger.Info("Arrow record read from BigQuery", zap.Int("numRows", int(record.NumRows())))
 
 	// Initialize the Snowflake client.
 	sfClient := snowflake.NewClient(snowflakeDSN, logger)
+	if err := sfClient.Validate(); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	// The target defaults to the source table's name when there is a single one.
+	sfClient.TargetTable = cfg.GetString("snowflake_table")
+	if sfClient.TargetTable == "" && sourceQuery == "" && tablesQuery == "" && !bigquery.IsWildcardTable(table) {
+		sfClient.TargetTable = table
+	}
+	if sfClient.TargetTable == "" && tablesQuery == "" {
+		sugar.Fatalf("Invalid configuration: snowflake_table is required with source_query or a wildcard table")
+	}
+	sfClient.CreateStageIfMissing = cfg.GetBool("snowflake_create_stage")
+	sfClient.PrintSQLOnly = printSQL
+	sfClient.Allocator = alloc
+	sfClient.Location = loc
+	sfClient.WidenNumeric = cfg.GetBool("snowflake_widen_numeric")
+	if sfClient.TimestampCoercion, err = snowflake.ParseTimestampCoercion(cfg.GetString("snowflake_timestamp_unit")); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	if sfClient.Parquet.Compression, err = snowflake.ParseParquetCompression(cfg.GetString("parquet_compression")); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	if sfClient.Parquet.Version, err = snowflake.ParseParquetVersion(cfg.GetString("parquet_version")); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	sfClient.Parquet.BatchSize = cfg.GetInt64("parquet_batch_size")
+	sfClient.Parquet.DataPageSize = cfg.GetInt64("parquet_data_page_size")
+	sfClient.Parquet.MaxRowGroupLength = cfg.GetInt64("parquet_max_row_group_length")
+	sfClient.Parquet.DisableDictionary = cfg.GetBool("parquet_disable_dictionary")
+	if err := sfClient.Parquet.Validate(); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	if sfClient.IntervalFormat, err = snowflake.ParseIntervalFormat(cfg.GetString("snowflake_interval_format")); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	if sfClient.MissingColumns, err = snowflake.ParseMissingColumnPolicy(cfg.GetString("snowflake_missing_columns")); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	if sfClient.ExtraColumns, err = snowflake.ParseExtraColumnPolicy(cfg.GetString("snowflake_extra_columns")); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	sfClient.Copy.SizeLimit = cfg.GetInt64("snowflake_copy_size_limit")
+	sfClient.Copy.ReturnFailedOnly = cfg.GetBool("snowflake_copy_return_failed_only")
+	sfClient.Copy.Select = cfg.GetString("snowflake_copy_select")
+	sfClient.Copy.Columns = cfg.GetStringSlice("snowflake_copy_columns")
+	if cfg.IsSet("snowflake_copy_writer_concurrency") {
+		sfClient.Copy.WriterConcurrency = cfg.GetInt("snowflake_copy_writer_concurrency")
+	}
+	if cfg.IsSet("snowflake_copy_upload_concurrency") {
+		sfClient.Copy.UploadConcurrency = cfg.GetInt("snowflake_copy_upload_concurrency")
+	}
+	if sfClient.Copy.Mode, err = snowflake.ParseLoadMode(cfg.GetString("load_mode")); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	sfClient.Copy.MergeKeys = cfg.GetStringSlice("merge_keys")
+	sfClient.Copy.Timeout = cfg.GetDuration("snowflake_copy_timeout")
+	if sfClient.Copy.OnError, err = snowflake.ParseOnError(cfg.GetString("snowflake_copy_on_error")); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	sfClient.Copy.Purge = cfg.GetBool("snowflake_copy_purge")
+	if err := sfClient.Copy.Validate(); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	if sfClient.Copy.OnError != snowflake.OnErrorDefault && sfClient.Copy.OnError != snowflake.OnErrorContinue && cfg.GetString("dead_letter") != "" {
+		sugar.Fatalf("snowflake_copy_on_error can't be combined with dead_letter, which loads with ON_ERROR = CONTINUE")
+	}
+	if sfClient.Copy.Select != "" && (validate || cfg.GetString("dead_letter") != "") {
+		sugar.Fatalf("snowflake_copy_select can't be combined with --validate or dead_letter")
+	}
+	castTypes := cfg.GetBool("snowflake_copy_cast_types")
+	if castTypes && (sfClient.Copy.Select != "" || validate || cfg.GetString("dead_letter") != "" || tablesQuery != "" || partitionColumn != "") {
+		sugar.Fatalf("snowflake_copy_cast_types can't be combined with snowflake_copy_select, --validate, dead_letter, --tables_from_query, or partition_column")
+	}
+	// castCopyTypes makes COPY cast every column to its mapped type, from the
+	// schema of the source about to be transferred.
+	castCopyTypes := func(reader pipeline.RecordSource) {
+		if !castTypes {
+			return
+		}
+		src, ok := reader.(interface{ Schema() (*arrow.Schema, error) })
+		if !ok {
+			sugar.Fatalf("snowflake_copy_cast_types needs a source that reports its schema")
+		}
+		schema, err := src.Schema()
+		if err != nil {
+			sugar.Fatalf("Failed to read BigQuery schema: %v", err)
+		}
+		if sfClient.Copy.Select, sfClient.Copy.Columns, err = sfClient.CastingCopySelect(schema); err != nil {
+			sugar.Fatalf("Invalid configuration: %v", err)
+		}
+	}
+	if sfClient.Quoting, err = snowflake.ParseQuoteStrategy(cfg.GetString("snowflake_identifier_quoting")); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	if sfClient.TableKind, err = snowflake.ParseTableKind(cfg.GetString("snowflake_table_kind")); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	sfClient.Iceberg = snowflake.IcebergOptions{
+		ExternalVolume: cfg.GetString("snowflake_iceberg_external_volume"),
+		Catalog:        cfg.GetString("snowflake_iceberg_catalog"),
+		BaseLocation:   cfg.GetString("snowflake_iceberg_base_location"),
+	}
+	if sfClient.TableKind == snowflake.TableIceberg && sfClient.Iceberg.ExternalVolume == "" {
+		sugar.Fatalf("Invalid configuration: snowflake_table_kind iceberg requires snowflake_iceberg_external_volume")
+	}
+	// Carry the source table's column defaults over to a table we create. Query
+	// results, shards, and table lists have no single table to take them from.
+	if (cfg.GetBool("snowflake_create_table") || ddl) && sourceQuery == "" && tablesQuery == "" && !bigquery.IsWildcardTable(table) {
+		exprs, err := bqClient.ColumnDefaults(ctx, project, dataset, table)
+		if err != nil {
+			logger.Warn("Failed to read column defaults; creating the table without them", zap.Error(err))
+		}
+		var skipped []string
+		sfClient.ColumnDefaults, skipped = snowflake.TranslateDefaults(exprs)
+		for _, s := range skipped {
+			logger.Warn("Column default has no Snowflake equivalent; skipping it", zap.String("default", s))
+		}
+	}
+	// The ddl subcommand prints the target table's CREATE TABLE statement,
+	// built from the source table's BigQuery metadata, without running it.
+	if ddl {
+		if sourceQuery != "" || bigquery.IsWildcardTable(table) {
+			sugar.Fatalf("ddl needs a single source table, not source_query or a wildcard table")
+		}
+		records, err := snowflake.ParseRecordMapping(cfg.GetString("ddl_record_mapping"))
+		if err != nil {
+			sugar.Fatalf("Invalid configuration: %v", err)
+		}
+		md, err := bqClient.TableMetadata(ctx, project, dataset, table)
+		if err != nil {
+			sugar.Fatalf("Failed to read BigQuery table metadata: %v", err)
+		}
+		query, err := snowflake.CreateTableSQLFromBigQuery(sfClient.TargetTable, md, snowflake.BigQueryDDLOptions{
+			DDLOptions: snowflake.DDLOptions{
+				Kind:     sfClient.TableKind,
+				Quoting:  sfClient.Quoting,
+				Defaults: sfClient.ColumnDefaults,
+			},
+			Records:   records,
+			ClusterBy: cfg.GetBool("ddl_cluster_by"),
+		})
+		if err != nil {
+			sugar.Fatalf("Failed to generate DDL: %v", err)
+		}
+		fmt.Println(query + ";")
+		return
+	}
+
+	// COPY loads from the stage snowflake_stage names, listing the files
+	// staged under its path, if any.
+	stageName, _, _ := strings.Cut(strings.TrimLeft(stagePath, "@"), "/")
+	sfClient.Stage = snowflake.StageConfig{
+		Name:               stageName,
+		FileFormat:         cfg.GetString("snowflake_file_format"),
+		FormatOptions:      cfg.GetString("snowflake_file_format_options"),
+		External:           cfg.GetString("snowflake_external_stage_url"),
+		StorageIntegration: cfg.GetString("snowflake_storage_integration"),
+	}
+	sfClient.StorageOptions = clientOpts
+	if sfClient.Stage.MatchByColumnName, err = snowflake.ParseColumnMatch(cfg.GetString("snowflake_match_by_column_name")); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	if err := sfClient.Stage.Validate(); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	sfClient.Upload = snowflake.UploadOptions{
+		Parallelism:    cfg.GetInt("snowflake_upload_parallelism"),
+		MaxAttempts:    cfg.GetInt("snowflake_upload_attempts"),
+		InitialBackoff: cfg.GetDuration("snowflake_upload_initial_backoff"),
+		MaxBackoff:     cfg.GetDuration("snowflake_upload_max_backoff"),
+		Verify:         cfg.GetBool("snowflake_verify_upload"),
+		CleanupLocal:   cfg.GetBool("cleanup_local_files"),
+	}
+	if err := sfClient.Upload.Validate(); err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	if masked := cfg.GetStringSlice("mask_columns"); len(masked) > 0 {
+		sfClient.ColumnTransforms = make(map[string]snowflake.ColumnTransform, len(masked))
+		for _, col := range masked {
+			sfClient.ColumnTransforms[col] = snowflake.SHA256Transform
+		}
+	}
+	sfClient.Network = snowflake.NetworkConfig{
+		ProxyHost:     cfg.GetString("snowflake_proxy_host"),
+		ProxyPort:     cfg.GetInt("snowflake_proxy_port"),
+		ProxyUser:     cfg.GetString("snowflake_proxy_user"),
+		ProxyPassword: cfg.GetString("snowflake_proxy_password"),
+		Insecure:      cfg.GetBool("snowflake_insecure"),
+		AllowInsecure: cfg.GetBool("snowflake_allow_insecure"),
+	}
+	if cfg.IsSet("snowflake_ocsp_fail_open") {
+		failOpen := cfg.GetBool("snowflake_ocsp_fail_open")
+		sfClient.Network.OCSPFailOpen = &failOpen
+	}
+	if err := sfClient.Network.Validate(); err != nil {
+		sugar.Fatalf("Invalid Snowflake network configuration: %v", err)
+	}
+	// Temporary tables only live as long as the connection that created them,
+	// so the DDL and the COPY must share one.
+	defer sfClient.Close()
+	if cfg.GetBool("snowflake_reuse_connection") || sfClient.TableKind == snowflake.TableTemporary {
+		if err := sfClient.Connect(ctx); err != nil {
+			sugar.Fatalf("Failed to connect to Snowflake: %v", err)
+		}
+	}
+	// Fail fast on bad credentials or a missing warehouse rather than at the
+	// first COPY. Nothing connects in --print_sql mode.
+	if !printSQL {
+		if err := sfClient.Ping(ctx); err != nil {
+			sugar.Fatalf("Failed to check Snowflake: %v", err)
+		}
+	}
+	if location := cfg.GetString("dead_letter"); location != "" {
+		if sfClient.DeadLetter, err = deadletter.Open(ctx, location, sfClient, clientOpts...); err != nil {
+			sugar.Fatalf("Failed to open dead-letter sink: %v", err)
+		}
+	}
 
 	// Ensure data directory exists
 	dataDir := "data"
@@ -120,22 +589,231 @@ func main() {
 		sugar.Fatalf("Failed to create data directory: %v", err)
 	}
 
-	// Update the parquet file path to use the data directory
-	parquetFile := filepath.Join(dataDir, "arrow_record.parquet")
+	// In validate mode, check one batch end to end and stop without loading data.
+	if validate {
+		reader, err := open(ctx, table)
+		if err != nil {
+			sugar.Fatalf("Failed to open BigQuery table: %v", err)
+		}
+		defer reader.Close()
+		report, err := pipeline.ValidateRoundTrip(ctx, reader, sfClient, dataDir, stagePath)
+		if err != nil {
+			sugar.Fatalf("Validation failed: %v", err)
+		}
+		if !report.OK() {
+			for _, e := range report.Errors {
+				sugar.Errorf("Validation error: %s", e)
+			}
+			sugar.Fatalf("Validation found %d rejected rows in a batch of %d", len(report.Errors), report.Rows)
+		}
+		sugar.Infof("Validation succeeded for a batch of %d rows", report.Rows)
+		return
+	}
 
-	// Write the Arrow record to a Parquet file and upload it to the Snowflake stage.
-	if err := sfClient.ArrowToParquetStage(ctx, record, parquetFile, stagePath); err != nil {
-		sugar.Fatalf("Error processing Arrow record for Snowflake stage: %v", err)
+	dedupStrategy, err := pipeline.ParseDedupStrategy(cfg.GetString("dedup_strategy"))
+	if err != nil {
+		sugar.Fatalf("Invalid configuration: %v", err)
+	}
+	var publisher events.Publisher
+	if topic := cfg.GetString("pubsub_topic"); topic != "" {
+		if publisher, err = events.NewPubSubPublisher(ctx, topic, clientOpts...); err != nil {
+			sugar.Fatalf("Failed to create event publisher: %v", err)
+		}
 	}
 
-	// Load the data into Snowflake using a COPY command.
-	if err := sfClient.LoadArrowIntoSnowflake(ctx); err != nil {
-		sugar.Fatalf("Error loading data into Snowflake: %v", err)
+	var limiter *pipeline.AdaptiveLimiter
+	if maxConcurrency := cfg.GetInt("snowflake_max_concurrency"); maxConcurrency > 0 {
+		target := cfg.GetDuration("snowflake_copy_latency_target")
+		if target <= 0 {
+			target = time.Minute
+		}
+		limiter = pipeline.NewAdaptiveLimiter(cfg.GetInt("snowflake_min_concurrency"), maxConcurrency, target)
+	}
+
+	opts := pipeline.Options{
+		DataDir:             dataDir,
+		StagePath:           stagePath,
+		MaxRecordRows:       cfg.GetInt64("max_record_rows"),
+		LeakPolicy:          leakPolicy,
+		MaxRecordAge:        cfg.GetDuration("record_max_age"),
+		CreateTable:         cfg.GetBool("snowflake_create_table"),
+		WidenVarchar:        cfg.GetBool("snowflake_widen_varchar"),
+		ExplodeColumn:       cfg.GetString("explode_column"),
+		DedupKeys:           cfg.GetStringSlice("dedup_keys"),
+		DedupStrategy:       dedupStrategy,
+		Allocator:           alloc,
+		Location:            loc,
+		Events:              publisher,
+		FailOnPublishError:  cfg.GetBool("fail_on_publish_error"),
+		CommitPerBatch:      cfg.GetBool("commit_per_batch"),
+		MaxPendingFiles:     cfg.GetInt("max_pending_files"),
+		VerifyAfterWrite:    cfg.GetBool("verify_after_write"),
+		VerifyRowGroup:      cfg.GetBool("verify_row_group"),
+		Concurrency:         limiter,
+		CopyAttempts:        cfg.GetInt("copy_attempts"),
+		ReturnFailedOnly:    sfClient.Copy.ReturnFailedOnly,
+		PreLoadSQL:          cfg.GetStringSlice("pre_load_sql"),
+		PostLoadSQL:         cfg.GetStringSlice("post_load_sql"),
+		FailOnPostLoadError: cfg.GetBool("fail_on_post_load_error"),
+	}
+	if manifestLocation != "" {
+		if opts.Manifest, err = pipeline.OpenManifest(ctx, manifestLocation, clientOpts...); err != nil {
+			sugar.Fatalf("Failed to open manifest: %v", err)
+		}
+		opts.Source = pipeline.ManifestSource{
+			Table:           fmt.Sprintf("%s.%s.%s", project, dataset, table),
+			RowRestriction:  readerOpts.RowRestriction,
+			SelectedColumns: readerOpts.SelectedColumns,
+		}
+		if sourceQuery != "" {
+			opts.Source.Table = sourceQuery
+		}
+	}
+
+	// In dry-run mode, report what the transfer would do and stop. A table
+	// list is reported below, once it is known.
+	if dryRun && tablesQuery == "" {
+		reader, err := open(ctx, table)
+		if err != nil {
+			sugar.Fatalf("Failed to open BigQuery table: %v", err)
+		}
+		defer reader.Close()
+		castCopyTypes(reader)
+		opts.Table = fmt.Sprintf("%s.%s.%s", project, dataset, table)
+		report, err := pipeline.NewTransfer(reader, sfClient, logger, opts).Preflight(ctx)
+		if err != nil {
+			sugar.Fatalf("Preflight failed: %v", err)
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			sugar.Fatalf("Failed to write preflight report: %v", err)
+		}
+		return
+	}
+
+	// In query mode, the set of tables comes from a BigQuery query, e.g. over
+	// INFORMATION_SCHEMA, and each one is transferred in turn.
+	if tablesQuery != "" {
+		tables, err := bqClient.TablesFromQuery(ctx, project, tablesQuery)
+		if err != nil {
+			sugar.Fatalf("Failed to list tables: %v", err)
+		}
+		logger.Info("Tables selected by query", zap.Strings("tables", tables))
+
+		opts.Table = fmt.Sprintf("%s.%s", project, dataset)
+		target := func(table string) snowflake.Loader {
+			return sfClient.ForTable(strings.ReplaceAll(tableTemplate, "{table}", table))
+		}
+		multi := pipeline.NewMultiTransfer(tables, open, target, logger, opts)
+		multi.BatchDDL = cfg.GetBool("batch_ddl")
+		if dryRun {
+			reports, err := multi.Preflight(ctx)
+			if err != nil {
+				sugar.Fatalf("Preflight failed: %v", err)
+			}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if err := enc.Encode(reports); err != nil {
+				sugar.Fatalf("Failed to write preflight report: %v", err)
+			}
+			return
+		}
+		defer serveStatus(statusAddr, statusToken, multi, logger)()
+
+		reports, err := multi.Run(ctx)
+		for _, report := range reports {
+			logger.Info("Transfer finished",
+				zap.String("table", report.Table),
+				zap.String("correlation_id", report.CorrelationID),
+				zap.Int64("rows", report.RowsRead),
+				zap.Int("files", report.FilesStaged),
+				zap.Duration("elapsed", report.Elapsed()))
+		}
+		if err != nil {
+			sugar.Fatalf("Transfer failed: %v", err)
+		}
+		sugar.Infof("Data transfer of %d tables complete!", len(tables))
+		return
 	}
 
+	opts.Table = fmt.Sprintf("%s.%s.%s", project, dataset, table)
+	var transfer interface {
+		pipeline.Controllable
+		Run(context.Context) (*pipeline.TransferReport, error)
+	}
+
+	// With a partition column, the table is split into key ranges that are
+	// read and staged concurrently.
+	var ranges []bigquery.KeyRange
+	if partitionColumn != "" {
+		min, max, date, ok, err := bqClient.KeyBounds(ctx, project, dataset, table, partitionColumn)
+		if err != nil {
+			sugar.Fatalf("Failed to determine partition key bounds: %v", err)
+		}
+		if ok {
+			if ranges, err = bigquery.SplitKeyRange(partitionColumn, date, min, max, cfg.GetInt("partition_ranges")); err != nil {
+				sugar.Fatalf("Invalid configuration: %v", err)
+			}
+		} else {
+			logger.Info("Partition column has no values; transferring without ranges", zap.String("column", partitionColumn))
+		}
+	}
+	if len(ranges) > 0 {
+		restrictions := make([]string, len(ranges))
+		for i, r := range ranges {
+			restrictions[i] = r.Restriction()
+		}
+		logger.Info("Transferring key ranges", zap.String("column", partitionColumn), zap.Strings("ranges", restrictions))
+		openRange := func(ctx context.Context, restriction string) (pipeline.RecordSource, error) {
+			rangeOpts := *readerOpts
+			rangeOpts.RowRestriction = restriction
+			if readerOpts.RowRestriction != "" {
+				rangeOpts.RowRestriction = fmt.Sprintf("(%s) AND (%s)", readerOpts.RowRestriction, restriction)
+			}
+			return openWith(ctx, table, &rangeOpts)
+		}
+		transfer = pipeline.NewRangeTransfer(restrictions, openRange, sfClient, logger, opts)
+	} else {
+		reader, err := open(ctx, table)
+		if err != nil {
+			sugar.Fatalf("Failed to open BigQuery table: %v", err)
+		}
+		defer reader.Close()
+		castCopyTypes(reader)
+		transfer = pipeline.NewTransfer(reader, sfClient, logger, opts)
+	}
+	defer serveStatus(statusAddr, statusToken, transfer, logger)()
+
+	report, err := transfer.Run(ctx)
+	if err != nil {
+		sugar.Fatalf("Transfer failed: %v", err)
+	}
+	logger.Info("Transfer finished",
+		zap.String("correlation_id", report.CorrelationID),
+		zap.Int64("rows", report.RowsRead),
+		zap.Int("files", report.FilesStaged),
+		zap.Duration("elapsed", report.Elapsed()))
+
 	sugar.Infof("Data transfer complete!")
 }
 
+// serveStatus optionally exposes a transfer's live progress over HTTP while it
+// runs. It returns a function that shuts the server down.
+func serveStatus(addr, token string, t pipeline.Controllable, logger *zap.Logger) func() {
+	if addr == "" {
+		return func() {}
+	}
+	srv := pipeline.NewStatusServer(addr, token, t, logger)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Error("Status server failed", zap.Error(err))
+		}
+	}()
+	logger.Info("Status server listening", zap.String("addr", addr), zap.Bool("control_token", token != ""))
+	return func() { srv.Shutdown(context.Background()) }
+}
+
 // mergeConfig returns the CLI value if provided; otherwise, it falls back to the config value.
 func mergeConfig(cliValue, configValue string) string {
 	if cliValue != "" {
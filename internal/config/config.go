@@ -2,9 +2,11 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/fsnotify/fsnotify"
+	"github.com/snowflakedb/gosnowflake"
 	"github.com/spf13/viper"
 	"go.uber.org/zap"
 )
@@ -15,15 +17,25 @@ var (
 	mu       sync.RWMutex // Protects concurrent access to config.
 )
 
-// RequiredFields lists the mandatory configuration keys.
+// RequiredFields lists the mandatory configuration keys. The Snowflake
+// connection is also required, as snowflake_dsn or SnowflakeFields.
 var RequiredFields = []string{
 	"project_id",
 	"dataset",
 	"table",
-	"snowflake_dsn",
 	// You can add more required fields (e.g., "service_account", "snowflake_stage") as needed.
 }
 
+// SnowflakeFields lists the keys BuildDSN assembles a Snowflake DSN from when
+// snowflake_dsn isn't set. snowflake_warehouse and snowflake_role are optional.
+var SnowflakeFields = []string{
+	"snowflake_account",
+	"snowflake_user",
+	"snowflake_password",
+	"snowflake_database",
+	"snowflake_schema",
+}
+
 // LoadConfig initializes and loads configuration from a YAML file.
 // The provided configPath (if empty, defaults to "config.yaml") is used.
 func LoadConfig(configPath string) (*viper.Viper, error) {
@@ -66,12 +78,52 @@ func LoadConfig(configPath string) (*viper.Viper, error) {
 	return instance, err
 }
 
-// ValidateConfig ensures that all required configuration keys are set.
+// LoadConfigDefault loads configuration from "config.yaml".
+func LoadConfigDefault() (*viper.Viper, error) {
+	return LoadConfig("config.yaml")
+}
+
+// ValidateConfig ensures that all required configuration keys are set,
+// including either snowflake_dsn or every key in SnowflakeFields.
 func ValidateConfig(v *viper.Viper) error {
 	for _, key := range RequiredFields {
 		if !v.IsSet(key) {
 			return fmt.Errorf("missing required config key: %s", key)
 		}
 	}
+	if v.IsSet("snowflake_dsn") {
+		return nil
+	}
+	var missing []string
+	for _, key := range SnowflakeFields {
+		if v.GetString(key) == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required config key: snowflake_dsn, or %s", strings.Join(missing, ", "))
+	}
 	return nil
 }
+
+// BuildDSN returns snowflake_dsn if it is set, and otherwise assembles the
+// DSN from SnowflakeFields, snowflake_warehouse, and snowflake_role, escaping
+// the user and password as the driver expects.
+func BuildDSN(v *viper.Viper) (string, error) {
+	if dsn := v.GetString("snowflake_dsn"); dsn != "" {
+		return dsn, nil
+	}
+	dsn, err := gosnowflake.DSN(&gosnowflake.Config{
+		Account:   v.GetString("snowflake_account"),
+		User:      v.GetString("snowflake_user"),
+		Password:  v.GetString("snowflake_password"),
+		Database:  v.GetString("snowflake_database"),
+		Schema:    v.GetString("snowflake_schema"),
+		Warehouse: v.GetString("snowflake_warehouse"),
+		Role:      v.GetString("snowflake_role"),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to build Snowflake DSN: %w", err)
+	}
+	return dsn, nil
+}
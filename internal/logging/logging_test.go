@@ -0,0 +1,79 @@
+package logging
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParseTimeZone(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		want string
+	}{
+		{"", "UTC"},
+		{" UTC ", "UTC"},
+		{"Local", "Local"},
+		{"America/New_York", "America/New_York"},
+		{"Europe/Berlin", "Europe/Berlin"},
+	} {
+		loc, err := ParseTimeZone(tc.in)
+		if err != nil {
+			t.Errorf("ParseTimeZone(%q): %v", tc.in, err)
+			continue
+		}
+		if loc.String() != tc.want {
+			t.Errorf("ParseTimeZone(%q) = %s, want %s", tc.in, loc, tc.want)
+		}
+	}
+	if _, err := ParseTimeZone("Mars/Olympus_Mons"); err == nil {
+		t.Error("ParseTimeZone accepted an unknown zone")
+	}
+}
+
+func TestParseTimeFormat(t *testing.T) {
+	for in, want := range map[string]TimeFormat{"": TimeRFC3339, "rfc3339": TimeRFC3339, " EPOCH ": TimeEpoch} {
+		if got, err := ParseTimeFormat(in); err != nil || got != want {
+			t.Errorf("ParseTimeFormat(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseTimeFormat("unix"); err == nil {
+		t.Error("ParseTimeFormat accepted an unknown format")
+	}
+}
+
+func TestTimeEncoder(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Date(2024, 7, 1, 12, 30, 0, 500, time.UTC)
+	for _, tc := range []struct {
+		name   string
+		loc    *time.Location
+		format TimeFormat
+		want   any
+	}{
+		{"utc", time.UTC, TimeRFC3339, "2024-07-01T12:30:00.0000005Z"},
+		{"zone", newYork, TimeRFC3339, "2024-07-01T08:30:00.0000005-04:00"},
+		{"epoch ignores zone", newYork, TimeEpoch, float64(at.UnixNano()) / 1e9},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			enc := zapcore.NewJSONEncoder(zapcore.EncoderConfig{TimeKey: "ts", EncodeTime: timeEncoder(tc.loc, tc.format)})
+			buf, err := enc.EncodeEntry(zapcore.Entry{Time: at}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer buf.Free()
+			var line map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+				t.Fatal(err)
+			}
+			if line["ts"] != tc.want {
+				t.Errorf("ts = %v, want %v", line["ts"], tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package logging
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redacted replaces secrets in log output.
+const redacted = "REDACTED"
+
+// secretParams are DSN query parameters that carry credentials.
+var secretParams = []string{"password", "passcode", "token", "privatekey", "privatekeypath", "oauthclientsecret"}
+
+// RedactDSN returns dsn with its password and credential parameters replaced,
+// so the rest of it (user, account, database, warehouse) can be logged.
+func RedactDSN(dsn string) string {
+	// The password ends at the last "@" before the query, which may hold
+	// "@" characters of its own.
+	credentials, _, _ := strings.Cut(dsn, "?")
+	if at := strings.LastIndex(credentials, "@"); at >= 0 {
+		if user, _, ok := strings.Cut(dsn[:at], ":"); ok {
+			dsn = user + ":" + redacted + dsn[at:]
+		}
+	}
+	base, query, ok := strings.Cut(dsn, "?")
+	if !ok {
+		return dsn
+	}
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		// Don't risk logging a secret the parser couldn't find.
+		return base + "?" + redacted
+	}
+	for key := range params {
+		for _, secret := range secretParams {
+			if strings.EqualFold(key, secret) {
+				params.Set(key, redacted)
+			}
+		}
+	}
+	return base + "?" + params.Encode()
+}
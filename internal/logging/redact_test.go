@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedactDSN(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		dsn    string
+		want   string
+		secret string
+	}{
+		{
+			name:   "password",
+			dsn:    "loader:hunter2@acct/db/public?warehouse=wh",
+			want:   "loader:REDACTED@acct/db/public?warehouse=wh",
+			secret: "hunter2",
+		},
+		{
+			name:   "password with @",
+			dsn:    "loader:p@ss@w0rd@acct/db?warehouse=wh",
+			want:   "loader:REDACTED@acct/db?warehouse=wh",
+			secret: "p@ss",
+		},
+		{
+			name:   "token parameter",
+			dsn:    "loader@acct/db?authenticator=oauth&token=abc.def",
+			want:   "loader@acct/db?authenticator=oauth&token=REDACTED",
+			secret: "abc.def",
+		},
+		{
+			name:   "token with @ and no password",
+			dsn:    "loader@acct/db?token=a@b&warehouse=wh",
+			want:   "loader@acct/db?token=REDACTED&warehouse=wh",
+			secret: "a@b",
+		},
+		{
+			name:   "password and token with @",
+			dsn:    "loader:pw@acct/db?token=a@b&warehouse=wh",
+			want:   "loader:REDACTED@acct/db?token=REDACTED&warehouse=wh",
+			secret: "a@b",
+		},
+		{
+			name:   "password and case-insensitive parameters",
+			dsn:    "loader:pw@acct/db?Passcode=123456&privateKey=MIIEv",
+			want:   "loader:REDACTED@acct/db?Passcode=REDACTED&privateKey=REDACTED",
+			secret: "MIIEv",
+		},
+		{
+			name: "nothing secret",
+			dsn:  "loader@acct/db?warehouse=wh",
+			want: "loader@acct/db?warehouse=wh",
+		},
+		{
+			name:   "unparseable query",
+			dsn:    "loader@acct/db?token=%zz",
+			want:   "loader@acct/db?REDACTED",
+			secret: "%zz",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := RedactDSN(tc.dsn)
+			if got != tc.want {
+				t.Errorf("RedactDSN(%q) = %q, want %q", tc.dsn, got, tc.want)
+			}
+			if tc.secret != "" && strings.Contains(got, tc.secret) {
+				t.Errorf("RedactDSN(%q) = %q leaks %q", tc.dsn, got, tc.secret)
+			}
+		})
+	}
+}
@@ -0,0 +1,449 @@
+package bigquery
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+
+	storagepb "cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/decimal256"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// DataFormat selects the wire format of a read session.
+type DataFormat int
+
+const (
+	// DataFormatArrow reads Arrow record batches (the default).
+	DataFormatArrow DataFormat = iota
+	// DataFormatAvro reads Avro rows and converts them to Arrow records.
+	DataFormatAvro
+)
+
+// ParseDataFormat converts a config string ("arrow" or "avro") into a
+// DataFormat. An empty string selects DataFormatArrow.
+func ParseDataFormat(s string) (DataFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "arrow":
+		return DataFormatArrow, nil
+	case "avro":
+		return DataFormatAvro, nil
+	default:
+		return DataFormatArrow, fmt.Errorf("unknown data format %q (supported: arrow, avro)", s)
+	}
+}
+
+// avroType is a node of a parsed Avro schema, limited to the shapes BigQuery
+// produces: records, arrays, primitives with BigQuery's logical and SQL types,
+// and unions of null with one other type.
+type avroType struct {
+	kind      string // Avro primitive, "record", or "array".
+	logical   string // Avro logicalType, e.g. "timestamp-micros".
+	sqlType   string // BigQuery's sqlType annotation, e.g. "DATETIME".
+	precision int32
+	scale     int32
+	fields    []avroField
+	items     *avroType
+
+	nullable  bool
+	nullIndex int64 // Union branch holding null, when nullable.
+}
+
+type avroField struct {
+	name string
+	typ  *avroType
+}
+
+// parseAvroSchema parses the JSON Avro schema of a read session.
+func parseAvroSchema(schema string) (*avroType, error) {
+	t, err := parseAvroType(json.RawMessage(schema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Avro schema from BigQuery: %w", err)
+	}
+	if t.kind != "record" || t.nullable {
+		return nil, fmt.Errorf("failed to parse Avro schema from BigQuery: top level is %s, want a record", t.kind)
+	}
+	return t, nil
+}
+
+func parseAvroType(raw json.RawMessage) (*avroType, error) {
+	var name string
+	if err := json.Unmarshal(raw, &name); err == nil {
+		return &avroType{kind: name}, nil
+	}
+
+	var union []json.RawMessage
+	if err := json.Unmarshal(raw, &union); err == nil {
+		if len(union) != 2 {
+			return nil, fmt.Errorf("unsupported union of %d types", len(union))
+		}
+		for i, branch := range union {
+			var s string
+			if json.Unmarshal(branch, &s) == nil && s == "null" {
+				t, err := parseAvroType(union[1-i])
+				if err != nil {
+					return nil, err
+				}
+				t.nullable = true
+				t.nullIndex = int64(i)
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("unsupported union without null")
+	}
+
+	var obj struct {
+		Type        json.RawMessage `json:"type"`
+		LogicalType string          `json:"logicalType"`
+		SQLType     string          `json:"sqlType"`
+		Precision   int32           `json:"precision"`
+		Scale       int32           `json:"scale"`
+		Fields      []struct {
+			Name string          `json:"name"`
+			Type json.RawMessage `json:"type"`
+		} `json:"fields"`
+		Items json.RawMessage `json:"items"`
+	}
+	if err := json.Unmarshal(raw, &obj); err != nil {
+		return nil, err
+	}
+	var kind string
+	if err := json.Unmarshal(obj.Type, &kind); err != nil {
+		// A nested type definition, such as {"type": {"type": "long", ...}}.
+		return parseAvroType(obj.Type)
+	}
+	t := &avroType{kind: kind, logical: obj.LogicalType, sqlType: obj.SQLType, precision: obj.Precision, scale: obj.Scale}
+	switch kind {
+	case "record":
+		for _, f := range obj.Fields {
+			ft, err := parseAvroType(f.Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", f.Name, err)
+			}
+			t.fields = append(t.fields, avroField{name: f.Name, typ: ft})
+		}
+	case "array":
+		items, err := parseAvroType(obj.Items)
+		if err != nil {
+			return nil, fmt.Errorf("array items: %w", err)
+		}
+		t.items = items
+	}
+	return t, nil
+}
+
+// arrowType returns the Arrow type the Avro type is decoded into, matching the
+// types of BigQuery's Arrow format.
+func (t *avroType) arrowType() (arrow.DataType, error) {
+	switch t.kind {
+	case "boolean":
+		return arrow.FixedWidthTypes.Boolean, nil
+	case "int", "long":
+		switch t.logical {
+		case "date":
+			return arrow.FixedWidthTypes.Date32, nil
+		case "time-micros":
+			return arrow.FixedWidthTypes.Time64us, nil
+		case "timestamp-micros":
+			return &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, nil
+		}
+		return arrow.PrimitiveTypes.Int64, nil
+	case "float", "double":
+		return arrow.PrimitiveTypes.Float64, nil
+	case "string":
+		if t.logical == "datetime" || t.sqlType == "DATETIME" {
+			return &arrow.TimestampType{Unit: arrow.Microsecond}, nil
+		}
+		return arrow.BinaryTypes.String, nil
+	case "bytes":
+		if t.logical == "decimal" {
+			if t.precision <= 38 {
+				return &arrow.Decimal128Type{Precision: t.precision, Scale: t.scale}, nil
+			}
+			return &arrow.Decimal256Type{Precision: min(t.precision, 76), Scale: t.scale}, nil
+		}
+		return arrow.BinaryTypes.Binary, nil
+	case "record":
+		fields := make([]arrow.Field, len(t.fields))
+		for i, f := range t.fields {
+			dt, err := f.typ.arrowType()
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", f.name, err)
+			}
+			fields[i] = arrow.Field{Name: f.name, Type: dt, Nullable: f.typ.nullable}
+		}
+		return arrow.StructOf(fields...), nil
+	case "array":
+		dt, err := t.items.arrowType()
+		if err != nil {
+			return nil, err
+		}
+		return arrow.ListOf(dt), nil
+	default:
+		return nil, fmt.Errorf("unsupported Avro type %q", t.kind)
+	}
+}
+
+// avroDecoder converts the Avro rows of a read session into Arrow records. It
+// holds no per-read state, so streams read concurrently may share one.
+type avroDecoder struct {
+	root   *avroType
+	schema *arrow.Schema
+	mem    memory.Allocator
+}
+
+func newAvroDecoder(schema string, mem memory.Allocator) (*avroDecoder, error) {
+	root, err := parseAvroSchema(schema)
+	if err != nil {
+		return nil, err
+	}
+	dt, err := root.arrowType()
+	if err != nil {
+		return nil, err
+	}
+	return &avroDecoder{root: root, schema: arrow.NewSchema(dt.(*arrow.StructType).Fields(), nil), mem: mem}, nil
+}
+
+// decode converts a ReadRows response's serialized Avro rows, of which there
+// are rows, into a record.
+func (d *avroDecoder) decode(data []byte, rows int64) (arrow.Record, error) {
+	b := array.NewRecordBuilder(d.mem, d.schema)
+	defer b.Release()
+	r := &avroBuf{data: data}
+	for n := int64(0); n < rows; n++ {
+		for i, f := range d.root.fields {
+			if err := r.decodeValue(f.typ, b.Field(i)); err != nil {
+				return nil, fmt.Errorf("failed to decode Avro row %d, column %s: %w", n, f.name, err)
+			}
+		}
+	}
+	if len(r.data) > 0 {
+		return nil, fmt.Errorf("failed to decode Avro rows: %d bytes left after %d rows", len(r.data), rows)
+	}
+	return b.NewRecord(), nil
+}
+
+// avroBuf reads Avro binary encoding from a byte slice.
+type avroBuf struct {
+	data []byte
+}
+
+var errAvroShort = errors.New("unexpected end of Avro data")
+
+func (r *avroBuf) long() (int64, error) {
+	v, n := binary.Varint(r.data)
+	if n <= 0 {
+		return 0, errAvroShort
+	}
+	r.data = r.data[n:]
+	return v, nil
+}
+
+func (r *avroBuf) bytes() ([]byte, error) {
+	n, err := r.long()
+	if err != nil {
+		return nil, err
+	}
+	if n < 0 || int64(len(r.data)) < n {
+		return nil, errAvroShort
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b, nil
+}
+
+func (r *avroBuf) fixed(n int) ([]byte, error) {
+	if len(r.data) < n {
+		return nil, errAvroShort
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b, nil
+}
+
+// decodeValue decodes one value of type t and appends it to b, which was
+// built for t.arrowType().
+func (r *avroBuf) decodeValue(t *avroType, b array.Builder) error {
+	if t.nullable {
+		branch, err := r.long()
+		if err != nil {
+			return err
+		}
+		if branch != 0 && branch != 1 {
+			return fmt.Errorf("invalid union branch %d", branch)
+		}
+		if branch == t.nullIndex {
+			b.AppendNull()
+			return nil
+		}
+	}
+
+	switch t.kind {
+	case "boolean":
+		v, err := r.fixed(1)
+		if err != nil {
+			return err
+		}
+		b.(*array.BooleanBuilder).Append(v[0] != 0)
+	case "int", "long":
+		v, err := r.long()
+		if err != nil {
+			return err
+		}
+		switch b := b.(type) {
+		case *array.Date32Builder:
+			b.Append(arrow.Date32(v))
+		case *array.Time64Builder:
+			b.Append(arrow.Time64(v))
+		case *array.TimestampBuilder:
+			b.Append(arrow.Timestamp(v))
+		default:
+			b.(*array.Int64Builder).Append(v)
+		}
+	case "float":
+		v, err := r.fixed(4)
+		if err != nil {
+			return err
+		}
+		b.(*array.Float64Builder).Append(float64(math.Float32frombits(binary.LittleEndian.Uint32(v))))
+	case "double":
+		v, err := r.fixed(8)
+		if err != nil {
+			return err
+		}
+		b.(*array.Float64Builder).Append(math.Float64frombits(binary.LittleEndian.Uint64(v)))
+	case "string":
+		v, err := r.bytes()
+		if err != nil {
+			return err
+		}
+		if tb, ok := b.(*array.TimestampBuilder); ok {
+			ts, err := time.Parse("2006-01-02T15:04:05.999999", string(v))
+			if err != nil {
+				return fmt.Errorf("invalid DATETIME %q: %w", v, err)
+			}
+			tb.Append(arrow.Timestamp(ts.UnixMicro()))
+			return nil
+		}
+		b.(*array.StringBuilder).Append(string(v))
+	case "bytes":
+		v, err := r.bytes()
+		if err != nil {
+			return err
+		}
+		switch b := b.(type) {
+		case *array.Decimal128Builder:
+			b.Append(decimal128.FromBigInt(twosComplement(v)))
+		case *array.Decimal256Builder:
+			b.Append(decimal256.FromBigInt(twosComplement(v)))
+		default:
+			b.(*array.BinaryBuilder).Append(v)
+		}
+	case "record":
+		sb := b.(*array.StructBuilder)
+		sb.Append(true)
+		for i, f := range t.fields {
+			if err := r.decodeValue(f.typ, sb.FieldBuilder(i)); err != nil {
+				return fmt.Errorf("field %s: %w", f.name, err)
+			}
+		}
+	case "array":
+		lb := b.(*array.ListBuilder)
+		lb.Append(true)
+		for {
+			count, err := r.long()
+			if err != nil {
+				return err
+			}
+			if count == 0 {
+				break
+			}
+			if count < 0 {
+				// A negative count is followed by the block's size in bytes.
+				count = -count
+				if _, err := r.long(); err != nil {
+					return err
+				}
+			}
+			for ; count > 0; count-- {
+				if err := r.decodeValue(t.items, lb.ValueBuilder()); err != nil {
+					return err
+				}
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported Avro type %q", t.kind)
+	}
+	return nil
+}
+
+// twosComplement converts a big-endian two's complement integer, as Avro
+// encodes decimals, into a big.Int.
+func twosComplement(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if len(b) > 0 && b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(len(b))*8))
+	}
+	return v
+}
+
+// newAvroReader creates a reader for a read session using the Avro format.
+func (c *BigQueryReadClient) newAvroReader(ctx context.Context, project, dataset, table string, session *storagepb.ReadSession, readOptions *storagepb.ReadSession_TableReadOptions, opts *BigQueryReaderOptions, alloc memory.Allocator) (*BigQueryReader, error) {
+	if session.GetAvroSchema().GetSchema() == "" {
+		return nil, fmt.Errorf("could not retrieve Avro schema from BigQuery: %w", ErrNoSchema)
+	}
+	dec, err := newAvroDecoder(session.GetAvroSchema().GetSchema(), alloc)
+	if err != nil {
+		return nil, err
+	}
+	readCtx, stop := context.WithCancel(ctx)
+	r := &BigQueryReader{
+		ctx:            readCtx,
+		stop:           stop,
+		client:         c.client,
+		callOptions:    c.callOptions,
+		onSchemaChange: opts.OnSchemaChange,
+		streams:        session.GetStreams(),
+		estimatedRows:  session.GetEstimatedRowCount(),
+		estimatedBytes: session.GetEstimatedTotalBytesScanned(),
+		maxConcurrency: opts.MaxConcurrency,
+		maxCells:       opts.MaxCellsPerRecord,
+		reopener:       streamReopener{max: opts.MaxStreamReopens},
+		mem:            alloc,
+		avro:           dec,
+		schema:         dec.schema,
+		stats:          newReadStats(session.GetEstimatedRowCount(), len(session.GetStreams()), opts.Metrics),
+	}
+	// See NewBigQueryReader.
+	if len(r.streams) == 0 && readOptions.GetRowRestriction() == "" {
+		if r.fallback, err = c.newTabledataReader(readCtx, project, dataset, snapshotDecorator(table, opts.SnapshotTime), dec.schema, alloc); err != nil {
+			stop()
+			return nil, fmt.Errorf("%w: %w", ErrNoStreams, err)
+		}
+	}
+	return r, nil
+}
+
+// readAvro returns the next record decoded from the stream's Avro rows.
+func (r *BigQueryReader) readAvro() (arrow.Record, error) {
+	for {
+		resp, err := r.readNextResponse()
+		if err != nil {
+			return nil, err
+		}
+		rows := resp.GetAvroRows().GetSerializedBinaryRows()
+		if len(rows) == 0 || resp.GetRowCount() == 0 {
+			continue
+		}
+		return r.avro.decode(rows, resp.GetRowCount())
+	}
+}
@@ -0,0 +1,442 @@
+package bigquery
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// avroEnc builds Avro binary encoding for the decoder tests.
+type avroEnc []byte
+
+// long appends a zigzag varint, as Avro encodes int and long.
+func (e avroEnc) long(v int64) avroEnc { return binary.AppendVarint(e, v) }
+
+func (e avroEnc) bytes(b []byte) avroEnc { return append(e.long(int64(len(b))), b...) }
+
+func (e avroEnc) str(s string) avroEnc { return e.bytes([]byte(s)) }
+
+func (e avroEnc) boolean(v bool) avroEnc {
+	if v {
+		return append(e, 1)
+	}
+	return append(e, 0)
+}
+
+func (e avroEnc) float(v float32) avroEnc {
+	return binary.LittleEndian.AppendUint32(e, math.Float32bits(v))
+}
+
+func (e avroEnc) double(v float64) avroEnc {
+	return binary.LittleEndian.AppendUint64(e, math.Float64bits(v))
+}
+
+// decimal appends the unscaled value s as big-endian two's complement bytes.
+func (e avroEnc) decimal(t *testing.T, s string) avroEnc {
+	t.Helper()
+	v, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		t.Fatalf("bad unscaled decimal %q", s)
+	}
+	if v.Sign() >= 0 {
+		b := v.Bytes()
+		if len(b) == 0 || b[0]&0x80 != 0 {
+			b = append([]byte{0}, b...)
+		}
+		return e.bytes(b)
+	}
+	n := len(new(big.Int).Not(v).Bytes()) + 1
+	b := new(big.Int).Add(v, new(big.Int).Lsh(big.NewInt(1), uint(n)*8)).Bytes()
+	return e.bytes(b)
+}
+
+// avroColumnSchema is a BigQuery-style Avro schema with one column v of the
+// given type.
+func avroColumnSchema(typ string) string {
+	return `{"type": "record", "name": "__root__", "fields": [{"name": "v", "type": ` + typ + `}]}`
+}
+
+func TestAvroDecodeTypes(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 123456000, time.UTC)
+	day := at.Unix() / 86400
+	tod := int64(3*3600+4*60+5)*1e6 + 123456
+
+	for _, tc := range []struct {
+		name     string
+		typ      string
+		rows     func(t *testing.T) avroEnc
+		n        int64
+		want     arrow.DataType
+		nullable bool
+		json     string // Expected column, as array.FromJSON input.
+	}{
+		{
+			name: "long",
+			typ:  `"long"`,
+			rows: func(*testing.T) avroEnc {
+				return avroEnc{}.long(0).long(-1).long(1 << 40).long(math.MinInt64).long(1 << 62)
+			},
+			n:    5,
+			want: arrow.PrimitiveTypes.Int64,
+			json: `[0, -1, 1099511627776, -9223372036854775808, 4611686018427387904]`,
+		},
+		{
+			name: "int",
+			typ:  `"int"`,
+			rows: func(*testing.T) avroEnc { return avroEnc{}.long(7).long(-7) },
+			n:    2,
+			want: arrow.PrimitiveTypes.Int64,
+			json: `[7, -7]`,
+		},
+		{
+			name:     "null first union",
+			typ:      `["null", "long"]`,
+			rows:     func(*testing.T) avroEnc { return avroEnc{}.long(0).long(1).long(5).long(0) },
+			n:        3,
+			want:     arrow.PrimitiveTypes.Int64,
+			nullable: true,
+			json:     `[null, 5, null]`,
+		},
+		{
+			name:     "null second union",
+			typ:      `["long", "null"]`,
+			rows:     func(*testing.T) avroEnc { return avroEnc{}.long(1).long(0).long(5).long(1) },
+			n:        3,
+			want:     arrow.PrimitiveTypes.Int64,
+			nullable: true,
+			json:     `[null, 5, null]`,
+		},
+		{
+			name:     "boolean",
+			typ:      `["null", "boolean"]`,
+			rows:     func(*testing.T) avroEnc { return avroEnc{}.long(1).boolean(true).long(1).boolean(false).long(0) },
+			n:        3,
+			want:     arrow.FixedWidthTypes.Boolean,
+			nullable: true,
+			json:     `[true, false, null]`,
+		},
+		{
+			name: "double",
+			typ:  `"double"`,
+			rows: func(*testing.T) avroEnc { return avroEnc{}.double(1.5).double(-0.25) },
+			n:    2,
+			want: arrow.PrimitiveTypes.Float64,
+			json: `[1.5, -0.25]`,
+		},
+		{
+			name: "float",
+			typ:  `"float"`,
+			rows: func(*testing.T) avroEnc { return avroEnc{}.float(2.5) },
+			n:    1,
+			want: arrow.PrimitiveTypes.Float64,
+			json: `[2.5]`,
+		},
+		{
+			name:     "string",
+			typ:      `["null", "string"]`,
+			rows:     func(*testing.T) avroEnc { return avroEnc{}.long(1).str("héllo").long(1).str("").long(0) },
+			n:        3,
+			want:     arrow.BinaryTypes.String,
+			nullable: true,
+			json:     `["héllo", "", null]`,
+		},
+		{
+			name: "geography and json stay strings",
+			typ:  `{"type": "string", "sqlType": "GEOGRAPHY"}`,
+			rows: func(*testing.T) avroEnc { return avroEnc{}.str("POINT(1 2)") },
+			n:    1,
+			want: arrow.BinaryTypes.String,
+			json: `["POINT(1 2)"]`,
+		},
+		{
+			name: "bytes",
+			typ:  `"bytes"`,
+			rows: func(*testing.T) avroEnc { return avroEnc{}.bytes([]byte{0, 1, 0xff}).bytes(nil) },
+			n:    2,
+			want: arrow.BinaryTypes.Binary,
+			json: `["AAH/", ""]`,
+		},
+		{
+			name: "numeric",
+			typ:  `["null", {"type": "bytes", "logicalType": "decimal", "precision": 38, "scale": 9}]`,
+			rows: func(t *testing.T) avroEnc {
+				return avroEnc{}.
+					long(1).decimal(t, "123450000000").
+					long(1).decimal(t, "-1").
+					long(1).decimal(t, "0").
+					long(1).decimal(t, "128"). // High bit set: needs a leading zero byte.
+					long(1).decimal(t, "-99999999999999999999999999999999999999").
+					long(0)
+			},
+			n:        6,
+			want:     &arrow.Decimal128Type{Precision: 38, Scale: 9},
+			nullable: true,
+			json:     `["123.45", "-0.000000001", "0", "0.000000128", "-99999999999999999999999999999.999999999", null]`,
+		},
+		{
+			name: "numeric without zero byte",
+			typ:  `{"type": "bytes", "logicalType": "decimal", "precision": 10, "scale": 2}`,
+			rows: func(*testing.T) avroEnc { return avroEnc{}.bytes(nil).bytes([]byte{0xcf, 0xc7}) },
+			n:    2,
+			want: &arrow.Decimal128Type{Precision: 10, Scale: 2},
+			json: `["0", "-123.45"]`,
+		},
+		{
+			name: "bignumeric",
+			typ:  `["null", {"type": "bytes", "logicalType": "decimal", "precision": 77, "scale": 38}]`,
+			rows: func(t *testing.T) avroEnc {
+				return avroEnc{}.
+					long(1).decimal(t, "-1234567890123456789012345678901234567890123456789012345678").
+					long(1).decimal(t, "1"+strings.Repeat("0", 38)).
+					long(0)
+			},
+			n:        3,
+			want:     &arrow.Decimal256Type{Precision: 76, Scale: 38},
+			nullable: true,
+			json:     `["-12345678901234567890.12345678901234567890123456789012345678", "1", null]`,
+		},
+		{
+			name:     "date",
+			typ:      `["null", {"type": "int", "logicalType": "date"}]`,
+			rows:     func(*testing.T) avroEnc { return avroEnc{}.long(1).long(day).long(1).long(-1).long(0) },
+			n:        3,
+			want:     arrow.FixedWidthTypes.Date32,
+			nullable: true,
+			json:     `["2024-01-02", "1969-12-31", null]`,
+		},
+		{
+			name:     "time",
+			typ:      `["null", {"type": "long", "logicalType": "time-micros"}]`,
+			rows:     func(*testing.T) avroEnc { return avroEnc{}.long(1).long(tod).long(0) },
+			n:        2,
+			want:     arrow.FixedWidthTypes.Time64us,
+			nullable: true,
+			json:     `["03:04:05.123456", null]`,
+		},
+		{
+			name:     "timestamp",
+			typ:      `["null", {"type": "long", "logicalType": "timestamp-micros"}]`,
+			rows:     func(*testing.T) avroEnc { return avroEnc{}.long(1).long(at.UnixMicro()).long(1).long(-1).long(0) },
+			n:        3,
+			want:     &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"},
+			nullable: true,
+			json:     `["2024-01-02T03:04:05.123456Z", "1969-12-31T23:59:59.999999Z", null]`,
+		},
+		{
+			name: "nested type definition",
+			typ:  `{"type": {"type": "long", "logicalType": "timestamp-micros"}}`,
+			rows: func(*testing.T) avroEnc { return avroEnc{}.long(at.UnixMicro()) },
+			n:    1,
+			want: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"},
+			json: `["2024-01-02T03:04:05.123456Z"]`,
+		},
+		{
+			name: "datetime",
+			typ:  `["null", {"type": "string", "sqlType": "DATETIME"}]`,
+			rows: func(*testing.T) avroEnc {
+				return avroEnc{}.long(1).str("2024-01-02T03:04:05.123456").long(1).str("2024-01-02T03:04:05").long(0)
+			},
+			n:        3,
+			want:     &arrow.TimestampType{Unit: arrow.Microsecond},
+			nullable: true,
+			json:     `["2024-01-02T03:04:05.123456", "2024-01-02T03:04:05", null]`,
+		},
+		{
+			name: "datetime logical type",
+			typ:  `{"type": "string", "logicalType": "datetime"}`,
+			rows: func(*testing.T) avroEnc { return avroEnc{}.str("2024-01-02T03:04:05.5") },
+			n:    1,
+			want: &arrow.TimestampType{Unit: arrow.Microsecond},
+			json: `["2024-01-02T03:04:05.5"]`,
+		},
+		{
+			name: "array",
+			typ:  `{"type": "array", "items": "long"}`,
+			rows: func(*testing.T) avroEnc {
+				// A block of two items, a block of one preceded by its byte
+				// size, and the end marker; then an empty array.
+				row1 := avroEnc{}.long(2).long(1).long(2).long(-1).long(1).long(3).long(0)
+				row2 := avroEnc{}.long(0)
+				return append(row1, row2...)
+			},
+			n:    2,
+			want: arrow.ListOf(arrow.PrimitiveTypes.Int64),
+			json: `[[1, 2, 3], []]`,
+		},
+		{
+			name: "array of nullable items",
+			typ:  `{"type": "array", "items": ["null", "string"]}`,
+			rows: func(*testing.T) avroEnc { return avroEnc{}.long(2).long(1).str("a").long(0).long(0) },
+			n:    1,
+			want: arrow.ListOf(arrow.BinaryTypes.String),
+			json: `[["a", null]]`,
+		},
+		{
+			name: "record",
+			typ: `["null", {"type": "record", "name": "addr", "fields": [
+				{"name": "city", "type": ["null", "string"]},
+				{"name": "zip", "type": "long"},
+				{"name": "lines", "type": {"type": "array", "items": "string"}}]}]`,
+			rows: func(*testing.T) avroEnc {
+				return avroEnc{}.
+					long(1).long(1).str("Oslo").long(150).long(1).str("Main St").long(0).
+					long(1).long(0).long(0).long(0).
+					long(0)
+			},
+			n: 3,
+			want: arrow.StructOf(
+				arrow.Field{Name: "city", Type: arrow.BinaryTypes.String, Nullable: true},
+				arrow.Field{Name: "zip", Type: arrow.PrimitiveTypes.Int64},
+				arrow.Field{Name: "lines", Type: arrow.ListOf(arrow.BinaryTypes.String)},
+			),
+			nullable: true,
+			json:     `[{"city": "Oslo", "zip": 150, "lines": ["Main St"]}, {"city": null, "zip": 0, "lines": []}, null]`,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+			defer mem.AssertSize(t, 0)
+
+			dec, err := newAvroDecoder(avroColumnSchema(tc.typ), mem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := arrow.NewSchema([]arrow.Field{{Name: "v", Type: tc.want, Nullable: tc.nullable}}, nil)
+			if !dec.schema.Equal(want) {
+				t.Fatalf("schema = %s, want %s", dec.schema, want)
+			}
+
+			rec, err := dec.decode(tc.rows(t), tc.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rec.Release()
+			expected, _, err := array.FromJSON(mem, tc.want, strings.NewReader(tc.json))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer expected.Release()
+			if rec.NumRows() != tc.n || !array.Equal(rec.Column(0), expected) {
+				t.Errorf("decoded %s\nwant %s", rec.Column(0), expected)
+			}
+		})
+	}
+}
+
+func TestAvroDecodeRows(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+	defer mem.AssertSize(t, 0)
+
+	dec, err := newAvroDecoder(`{"type": "record", "name": "__root__", "fields": [
+		{"name": "id", "type": "long"},
+		{"name": "name", "type": ["null", "string"]}]}`, mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows := avroEnc{}.long(1).long(1).str("a").long(2).long(0).long(3).long(1).str("c")
+	rec, err := dec.decode(rows, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rec.Release()
+	if got := rec.Column(0).(*array.Int64).Int64Values(); len(got) != 3 || got[0] != 1 || got[1] != 2 || got[2] != 3 {
+		t.Errorf("ids = %v, want [1 2 3]", got)
+	}
+	names := rec.Column(1).(*array.String)
+	if names.Value(0) != "a" || !names.IsNull(1) || names.Value(2) != "c" {
+		t.Errorf("names = %s, want [a (null) c]", names)
+	}
+}
+
+func TestAvroDecodeErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		typ  string
+		rows avroEnc
+		n    int64
+		want string
+	}{
+		{"truncated long", `"long"`, avroEnc{0x80}, 1, "unexpected end"},
+		{"missing row", `"long"`, avroEnc{}.long(1), 2, "unexpected end"},
+		{"truncated string", `"string"`, avroEnc{}.long(5).str("ab")[:3], 1, "unexpected end"},
+		{"negative length", `"bytes"`, avroEnc{}.long(-2), 1, "unexpected end"},
+		{"truncated double", `"double"`, avroEnc{0, 0, 0}, 1, "unexpected end"},
+		{"trailing bytes", `"long"`, avroEnc{}.long(1).long(2), 1, "bytes left"},
+		{"invalid union branch", `["null", "long"]`, avroEnc{}.long(2).long(1), 1, "invalid union branch 2"},
+		{"invalid datetime", `{"type": "string", "sqlType": "DATETIME"}`, avroEnc{}.str("yesterday"), 1, "invalid DATETIME"},
+		{"truncated array", `{"type": "array", "items": "long"}`, avroEnc{}.long(2).long(1), 1, "unexpected end"},
+		{"truncated record field", `{"type": "record", "name": "r", "fields": [{"name": "a", "type": "long"}, {"name": "b", "type": "long"}]}`, avroEnc{}.long(1), 1, "field b"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.DefaultAllocator)
+			defer mem.AssertSize(t, 0)
+
+			dec, err := newAvroDecoder(avroColumnSchema(tc.typ), mem)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rec, err := dec.decode(tc.rows, tc.n)
+			if err == nil {
+				rec.Release()
+				t.Fatal("decode succeeded")
+			}
+			if !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tc.want)
+			}
+			if tc.want == "unexpected end" && !errors.Is(err, errAvroShort) {
+				t.Errorf("err = %v, want errAvroShort", err)
+			}
+		})
+	}
+}
+
+func TestAvroSchemaErrors(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		schema string
+		want   string
+	}{
+		{"not json", `{`, "failed to parse Avro schema"},
+		{"top level primitive", `"long"`, "want a record"},
+		{"nullable top level", `["null", {"type": "record", "name": "r", "fields": []}]`, "want a record"},
+		{"union of three", avroColumnSchema(`["null", "long", "string"]`), "union of 3 types"},
+		{"union without null", avroColumnSchema(`["long", "string"]`), "union without null"},
+		{"map", avroColumnSchema(`{"type": "map", "values": "long"}`), `field v: unsupported Avro type "map"`},
+		{"nested unsupported", avroColumnSchema(`{"type": "array", "items": {"type": "enum", "name": "e", "symbols": ["A"]}}`), `unsupported Avro type "enum"`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newAvroDecoder(tc.schema, memory.DefaultAllocator)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tc.want)
+			}
+		})
+	}
+}
+
+func TestTwosComplement(t *testing.T) {
+	for _, tc := range []struct {
+		in   []byte
+		want int64
+	}{
+		{nil, 0},
+		{[]byte{0}, 0},
+		{[]byte{0x7f}, 127},
+		{[]byte{0x00, 0x80}, 128},
+		{[]byte{0x80}, -128},
+		{[]byte{0xff}, -1},
+		{[]byte{0xff, 0xff, 0xff}, -1},
+		{[]byte{0xcf, 0xc7}, -12345},
+	} {
+		if got := twosComplement(tc.in); got.Int64() != tc.want {
+			t.Errorf("twosComplement(%x) = %s, want %d", tc.in, got, tc.want)
+		}
+	}
+}
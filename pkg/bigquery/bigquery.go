@@ -5,6 +5,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"slices"
+	"strings"
 	"time"
 
 	bqStorage "cloud.google.com/go/bigquery/storage/apiv1"
@@ -13,15 +15,29 @@ import (
 	"github.com/apache/arrow-go/v18/arrow/ipc"
 	"github.com/apache/arrow-go/v18/arrow/memory"
 	"github.com/googleapis/gax-go/v2"
+	"go.uber.org/zap"
 	"google.golang.org/api/option"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/TFMV/syncronicity/pkg/logctx"
+	"github.com/TFMV/syncronicity/pkg/metrics"
 )
 
 // BigQueryReadClient wraps a BigQuery Storage client for reading Arrow-serialized data
 // from BigQuery tables.
+//
+// A BigQueryReadClient is safe for concurrent use: one client (and its underlying
+// gRPC connection pool) can back any number of readers created from multiple
+// goroutines. Its fields are never modified after construction.
 type BigQueryReadClient struct {
 	client      *bqStorage.BigQueryReadClient
 	callOptions *BigQueryReadCallOptions
+
+	// clientOpts are kept so metadata lookups can build a BigQuery API client
+	// with the same credentials as the Storage client.
+	clientOpts []option.ClientOption
 }
 
 // BigQueryReadCallOptions stores gax.CallOption slices for CreateReadSession and ReadRows RPCs.
@@ -72,39 +88,285 @@ func NewBigQueryReadClient(ctx context.Context, opts ...option.ClientOption) (*B
 	return &BigQueryReadClient{
 		client:      client,
 		callOptions: defaultBigQueryReadCallOptions(),
+		clientOpts:  opts,
 	}, nil
 }
 
+// ClientOptions configures a BigQueryReadClient created with
+// NewBigQueryReadClientWithOptions.
+type ClientOptions struct {
+	// Credentials is the path of a service account key file, or the key's
+	// JSON itself. It is passed to the client rather than set in
+	// GOOGLE_APPLICATION_CREDENTIALS, so clients with different credentials
+	// can share a process. Empty uses Application Default Credentials.
+	Credentials string
+
+	// Options are further client options, such as scopes or an endpoint.
+	Options []option.ClientOption
+}
+
+// ClientOptions returns the Google API client options for o, including its
+// credentials, for use with other Google clients such as Cloud Storage.
+func (o ClientOptions) ClientOptions() []option.ClientOption {
+	opts := slices.Clone(o.Options)
+	creds := strings.TrimSpace(o.Credentials)
+	switch {
+	case creds == "":
+	case strings.HasPrefix(creds, "{"):
+		opts = append(opts, option.WithCredentialsJSON([]byte(creds)))
+	default:
+		opts = append(opts, option.WithCredentialsFile(creds))
+	}
+	return opts
+}
+
+// NewBigQueryReadClientWithOptions constructs a BigQuery Storage client with
+// the credentials and options in o.
+func NewBigQueryReadClientWithOptions(ctx context.Context, o ClientOptions) (*BigQueryReadClient, error) {
+	return NewBigQueryReadClient(ctx, o.ClientOptions()...)
+}
+
+// Close closes the underlying Storage client. Readers created from the client
+// must not be used afterwards.
+func (c *BigQueryReadClient) Close() error {
+	return c.client.Close()
+}
+
 type BigQueryReaderOptions struct {
 	MaxStreamCount   int32
 	TableReadOptions *storagepb.ReadSession_TableReadOptions
+
+	// DataFormat selects the wire format BigQuery sends rows in. Arrow (the
+	// default) is decoded with little work. Avro is meant for comparing
+	// against Avro-based tooling: its rows are decoded value by value and
+	// copied into Arrow builders, which costs noticeably more CPU and
+	// memory per row, and DecodeConcurrency doesn't apply to it.
+	DataFormat DataFormat
+
+	// SelectedColumns and RowRestriction project and filter the rows read
+	// without building TableReadOptions: e.g. []string{"id", "name"} and
+	// "id > 100". RowRestriction is a SQL WHERE predicate evaluated by
+	// BigQuery. When either is set, the pair replaces TableReadOptions, and a
+	// warning is logged if that was set too.
+	SelectedColumns []string
+	RowRestriction  string
+
+	// Logger receives warnings about the options. Nil discards them.
+	Logger *zap.Logger
+
+	// MaxConcurrency caps how many of the session's streams are read at once
+	// when BigQuery returns more than one; each is read by its own goroutine
+	// with its own ReadRows call. Zero reads every stream at once. Records
+	// from one stream stay in order, but records from different streams are
+	// interleaved.
+	MaxConcurrency int
+
+	// DecodeConcurrency, if greater than one, decodes each stream's Arrow
+	// batches on that many goroutines, for when batches arrive faster than a
+	// single core decodes them. Records still come out in stream order.
+	// Zero or one decodes on the reading goroutine.
+	DecodeConcurrency int
+
+	// MaxCellsPerRecord, if positive, slices records so that none holds more
+	// than this many cells (rows times top-level columns), keeping records
+	// from very wide tables small enough to write. The Storage API has no
+	// batch size setting, so each batch is still decoded whole: slices share
+	// its buffers, which are freed once every slice has been released.
+	MaxCellsPerRecord int64
+
+	// OnSchemaChange controls what happens if BigQuery reports a schema that
+	// differs from the session schema partway through a read.
+	OnSchemaChange SchemaChangePolicy
+
+	// Allocator backs every record the reader decodes, including each batch
+	// parsed by processRecordBatch. Sharing one allocator, such as a bounded
+	// one, across readers caps their combined memory, and a
+	// memory.CheckedAllocator can confirm nothing leaks once records are
+	// released and the reader is closed. Nil uses a new Go allocator per
+	// reader.
+	Allocator memory.Allocator
+
+	// QuotaUser, if set, attributes the reader's Storage API quota to this
+	// user within the project, for per-operator tracking in shared projects.
+	// It is sent as the x-goog-quota-user header on CreateReadSession and
+	// ReadRows calls; see ValidateQuotaUser. It doesn't apply to BigQuery API
+	// calls such as metadata lookups and queries. To charge quota to another
+	// project, create the client with option.WithQuotaProject instead.
+	QuotaUser string
+
+	// MaxStaleness, if positive, is how stale the data read may be. BigQuery
+	// has no per-read staleness setting; instead, tables (with change data
+	// capture) and materialized views may set a max_staleness option, and are
+	// then read from their cached state, which is cheaper and faster. The
+	// reader checks that option when it is created: sources without it are
+	// read fresh, and sources allowing more staleness than this fail with
+	// ErrStalenessExceeded.
+	MaxStaleness time.Duration
+
+	// SnapshotTime, if set, reads the table as it was at that time, so a
+	// table written to concurrently is read consistently. It must not be in
+	// the future, and must be within the dataset's time travel window (7
+	// days unless the dataset sets max_time_travel_hours).
+	SnapshotTime time.Time
+
+	// MaxStreamReopens is how many times in a row a ReadRows stream that
+	// breaks with a retryable error (Unavailable, Internal, or
+	// ResourceExhausted) is reopened at the offset already read, instead of
+	// failing the read. The count restarts whenever the stream delivers
+	// rows, so long reads survive occasional drops. Zero allows 5 reopens;
+	// a negative value disables reopening.
+	MaxStreamReopens int
+
+	// Metrics, if set, receives the rows and bytes read as batches arrive.
+	Metrics metrics.Metrics
+
+	// BillingProject, if set, is the project read sessions are created in
+	// and billed to, while the table is still read from its own project. It
+	// needs the bigquery.readsessions.create permission there. Empty bills
+	// the table's project.
+	BillingProject string
+}
+
+// maxQuotaUserLength is the longest quota user Google APIs accept.
+const maxQuotaUserLength = 40
+
+// ValidateQuotaUser checks that user is a usable quota user: 1 to 40
+// printable ASCII characters.
+func ValidateQuotaUser(user string) error {
+	if user == "" || len(user) > maxQuotaUserLength {
+		return fmt.Errorf("quota user must be 1 to %d characters, got %d", maxQuotaUserLength, len(user))
+	}
+	for _, c := range user {
+		if c < '!' || c > '~' {
+			return fmt.Errorf("quota user %q contains %q; only printable ASCII without spaces is allowed", user, c)
+		}
+	}
+	return nil
+}
+
+// SchemaChangePolicy selects how a reader handles a mid-stream schema change.
+type SchemaChangePolicy int
+
+const (
+	// SchemaChangeError fails the read with ErrSchemaChangedMidStream (the default).
+	SchemaChangeError SchemaChangePolicy = iota
+	// SchemaChangeAdopt switches to the new schema and decodes subsequent batches
+	// with it. Records before and after the change will have different schemas.
+	SchemaChangeAdopt
+)
+
+// ParseSchemaChangePolicy converts a config string ("error" or "adopt") into a
+// SchemaChangePolicy. An empty string selects SchemaChangeError.
+func ParseSchemaChangePolicy(s string) (SchemaChangePolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "error":
+		return SchemaChangeError, nil
+	case "adopt":
+		return SchemaChangeAdopt, nil
+	default:
+		return SchemaChangeError, fmt.Errorf("unknown schema change policy %q (supported: error, adopt)", s)
+	}
+}
+
+// allocator returns the configured allocator or a new Go allocator.
+func (o *BigQueryReaderOptions) allocator() memory.Allocator {
+	if o != nil && o.Allocator != nil {
+		return o.Allocator
+	}
+	return memory.NewGoAllocator()
+}
+
+// readOptions returns the session read options: those built from
+// SelectedColumns and RowRestriction if either is set, else TableReadOptions.
+func (o *BigQueryReaderOptions) readOptions() *storagepb.ReadSession_TableReadOptions {
+	if len(o.SelectedColumns) == 0 && o.RowRestriction == "" {
+		return o.TableReadOptions
+	}
+	return &storagepb.ReadSession_TableReadOptions{
+		SelectedFields: o.SelectedColumns,
+		RowRestriction: o.RowRestriction,
+	}
+}
+
+// billingProject returns BillingProject, or project if it is unset.
+func (o *BigQueryReaderOptions) billingProject(project string) string {
+	if o.BillingProject != "" {
+		return o.BillingProject
+	}
+	return project
+}
+
+// logger returns the configured logger or a no-op one.
+func (o *BigQueryReaderOptions) logger(ctx context.Context) *zap.Logger {
+	if o.Logger == nil {
+		return zap.NewNop()
+	}
+	return logctx.Logger(ctx, o.Logger)
 }
 
 // NewBigQueryReader creates a new reader for the specified table.
 // If opts is nil, default options will be used.
 func (c *BigQueryReadClient) NewBigQueryReader(ctx context.Context, project, dataset, table string, opts *BigQueryReaderOptions) (*BigQueryReader, error) {
+	if opts.QuotaUser != "" {
+		if err := ValidateQuotaUser(opts.QuotaUser); err != nil {
+			return nil, err
+		}
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-goog-quota-user", opts.QuotaUser)
+	}
+	if opts.MaxStaleness > 0 {
+		if err := c.checkStaleness(ctx, project, dataset, table, opts.MaxStaleness); err != nil {
+			return nil, err
+		}
+	}
+	if !opts.SnapshotTime.IsZero() {
+		if err := c.checkSnapshotTime(ctx, project, dataset, opts.SnapshotTime); err != nil {
+			return nil, err
+		}
+	}
+	readOptions := opts.readOptions()
+	if opts.TableReadOptions != nil && readOptions != opts.TableReadOptions {
+		opts.logger(ctx).Warn("SelectedColumns and RowRestriction override TableReadOptions",
+			zap.Strings("selected_columns", opts.SelectedColumns), zap.String("row_restriction", opts.RowRestriction))
+	}
+	format := storagepb.DataFormat_ARROW
+	if opts.DataFormat == DataFormatAvro {
+		format = storagepb.DataFormat_AVRO
+	}
 	req := &storagepb.CreateReadSessionRequest{
-		Parent: fmt.Sprintf("projects/%s", project),
+		Parent: fmt.Sprintf("projects/%s", opts.billingProject(project)),
 		ReadSession: &storagepb.ReadSession{
 			Table:       fmt.Sprintf("projects/%s/datasets/%s/tables/%s", project, dataset, table),
-			DataFormat:  storagepb.DataFormat_ARROW,
-			ReadOptions: opts.TableReadOptions,
+			DataFormat:  format,
+			ReadOptions: readOptions,
 		},
 		MaxStreamCount: opts.MaxStreamCount,
 	}
 
+	// A session reads the table as of its creation, which the API doesn't
+	// report, so note the time it was requested.
+	snapshot := time.Now()
+	if !opts.SnapshotTime.IsZero() {
+		snapshot = opts.SnapshotTime
+		req.ReadSession.TableModifiers = &storagepb.ReadSession_TableModifiers{
+			SnapshotTime: timestamppb.New(opts.SnapshotTime),
+		}
+	}
 	session, err := c.client.CreateReadSession(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create read session: %w", err)
 	}
-	if len(session.GetStreams()) == 0 {
-		return nil, fmt.Errorf("no streams available in session for table %s", table)
+	alloc := opts.allocator()
+	if opts.DataFormat == DataFormatAvro {
+		r, err := c.newAvroReader(ctx, project, dataset, table, session, readOptions, opts, alloc)
+		if err != nil {
+			return nil, err
+		}
+		r.snapshotTime = snapshot
+		return r, nil
 	}
-
-	alloc := memory.NewGoAllocator()
 	schemaBytes := session.GetArrowSchema().GetSerializedSchema()
 	if len(schemaBytes) == 0 {
-		return nil, fmt.Errorf("could not retrieve Arrow schema from BigQuery")
+		return nil, fmt.Errorf("could not retrieve Arrow schema from BigQuery: %w", ErrNoSchema)
 	}
 
 	// Initialize an IPC reader solely to parse the schema
@@ -114,15 +376,42 @@ func (c *BigQueryReadClient) NewBigQueryReader(ctx context.Context, project, dat
 		return nil, fmt.Errorf("failed to parse Arrow schema from BigQuery: %w", err)
 	}
 
+	// The reader's own context lets Close tear down its ReadRows calls.
+	readCtx, stop := context.WithCancel(ctx)
 	r := &BigQueryReader{
-		ctx:         ctx,
-		client:      c.client,
-		callOptions: c.callOptions,
-		schemaBytes: schemaBytes,
-		streams:     session.GetStreams(),
-		mem:         alloc,
-		buf:         bytes.NewBuffer(nil),
-		r:           ipcReader,
+		ctx:               readCtx,
+		stop:              stop,
+		client:            c.client,
+		callOptions:       c.callOptions,
+		onSchemaChange:    opts.OnSchemaChange,
+		schemaBytes:       schemaBytes,
+		schema:            ipcReader.Schema(),
+		batchSchema:       ipcReader.Schema(),
+		streams:           session.GetStreams(),
+		estimatedRows:     session.GetEstimatedRowCount(),
+		estimatedBytes:    session.GetEstimatedTotalBytesScanned(),
+		snapshotTime:      snapshot,
+		maxConcurrency:    opts.MaxConcurrency,
+		decodeConcurrency: opts.DecodeConcurrency,
+		maxCells:          opts.MaxCellsPerRecord,
+		reopener:          streamReopener{max: opts.MaxStreamReopens},
+		mem:               alloc,
+		buf:               bytes.NewBuffer(nil),
+		r:                 ipcReader,
+		stats:             newReadStats(session.GetEstimatedRowCount(), len(session.GetStreams()), opts.Metrics),
+	}
+
+	// BigQuery may return no streams for a very small table even though it
+	// has rows, so read those through tabledata.list instead. With a row
+	// restriction, no streams just means no rows match; tabledata.list can't
+	// filter, so that read is left empty.
+	if len(r.streams) == 0 && readOptions.GetRowRestriction() == "" {
+		r.fallback, err = c.newTabledataReader(readCtx, project, dataset, snapshotDecorator(table, opts.SnapshotTime), ipcReader.Schema(), alloc)
+		if err != nil {
+			stop()
+			ipcReader.Release()
+			return nil, fmt.Errorf("%w: %w", ErrNoStreams, err)
+		}
 	}
 
 	return r, nil
@@ -130,26 +419,90 @@ func (c *BigQueryReadClient) NewBigQueryReader(ctx context.Context, project, dat
 
 // BigQueryReader reads Arrow records from a BigQuery Storage read session.
 // Use Read() to iterate over rows. Close() when done to free resources.
+//
+// A BigQueryReader is not safe for concurrent use; give each goroutine its own
+// reader created from a shared BigQueryReadClient.
 type BigQueryReader struct {
-	ctx         context.Context
-	client      *bqStorage.BigQueryReadClient
-	callOptions *BigQueryReadCallOptions
-	schemaBytes []byte
-	streams     []*storagepb.ReadStream
+	ctx               context.Context
+	stop              context.CancelFunc // Cancels ctx; nil for stream readers.
+	closed            bool
+	client            *bqStorage.BigQueryReadClient
+	callOptions       *BigQueryReadCallOptions
+	onSchemaChange    SchemaChangePolicy
+	schemaBytes       []byte
+	schema            *arrow.Schema // The session's schema; never changes.
+	batchSchema       *arrow.Schema // Batches are decoded with; see checkSchema.
+	streams           []*storagepb.ReadStream
+	estimatedRows     int64
+	estimatedBytes    int64
+	snapshotTime      time.Time
+	maxConcurrency    int
+	decodeConcurrency int
+	maxCells          int64
+
+	// results carries records from the stream readers once a multi-stream
+	// read has started; cancel stops them.
+	results chan streamResult
+	cancel  context.CancelFunc
+
+	// decoded queues the result slots of batches being decoded by the worker
+	// pool, in stream order; stopDecoders stops the pool.
+	decoded      chan chan streamResult
+	stopDecoders context.CancelFunc
 
 	// For reading data
-	mem    memory.Allocator
-	stream storagepb.BigQueryRead_ReadRowsClient
-	offset int64
+	mem      memory.Allocator
+	stream   storagepb.BigQueryRead_ReadRowsClient
+	offset   int64
+	reopener streamReopener
 
 	// Reusable buffers
 	r   *ipc.Reader
 	buf *bytes.Buffer
+
+	// avro decodes rows when the session uses the Avro format; r is nil then.
+	avro *avroDecoder
+
+	// fallback reads the table when the session has no streams.
+	fallback *tabledataReader
+
+	// stats is shared with the readers of individual streams.
+	stats *readStats
+
+	// sliced is the rest of a record being handed out in slices of maxCells.
+	sliced arrow.Record
 }
 
 // Read fetches the next Arrow record from BigQuery. Returns io.EOF if there are
 // no more records. Each record must be released after usage to avoid memory leaks.
 func (r *BigQueryReader) Read() (arrow.Record, error) {
+	if r.closed {
+		return nil, ErrReaderClosed
+	}
+	if r.maxCells > 0 {
+		return r.readSliced()
+	}
+	return r.read()
+}
+
+// read returns the next record as decoded.
+func (r *BigQueryReader) read() (arrow.Record, error) {
+	if r.fallback != nil {
+		rec, err := r.fallback.Read()
+		if rec != nil {
+			r.stats.addRecord(rec.NumRows())
+		}
+		return rec, err
+	}
+	if r.avro != nil && len(r.streams) <= 1 {
+		return r.readAvro()
+	}
+	if len(r.streams) > 1 {
+		return r.readMerged()
+	}
+	if r.decodeConcurrency > 1 {
+		return r.readDecoded()
+	}
 	for {
 		// If there's a current IPC reader with unconsumed records
 		if r.r != nil && r.r.Next() {
@@ -164,6 +517,14 @@ func (r *BigQueryReader) Read() (arrow.Record, error) {
 			return nil, err
 		}
 
+		// The first response of each ReadRows call repeats the schema; make sure
+		// it still matches the one the batches are decoded with.
+		if s := resp.GetArrowSchema().GetSerializedSchema(); len(s) > 0 {
+			if err := r.checkSchema(s); err != nil {
+				return nil, err
+			}
+		}
+
 		batch := resp.GetArrowRecordBatch().GetSerializedRecordBatch()
 		if len(batch) == 0 {
 			// This batch is empty, or no data => keep going
@@ -175,6 +536,12 @@ func (r *BigQueryReader) Read() (arrow.Record, error) {
 			return nil, err
 		}
 		if rec != nil {
+			// A row count that disagrees with the response means the batch was
+			// decoded with the wrong layout.
+			if n := resp.GetRowCount(); n > 0 && rec.NumRows() != n {
+				rec.Release()
+				return nil, fmt.Errorf("%w: decoded %d rows but the batch holds %d", ErrSchemaChangedMidStream, rec.NumRows(), n)
+			}
 			return rec, nil
 		}
 		// else loop for next response
@@ -205,12 +572,45 @@ func (r *BigQueryReader) readNextResponse() (*storagepb.ReadRowsResponse, error)
 		return nil, io.EOF
 	}
 	if err != nil {
+		// Rows up to r.offset have been handed out, so a reopened stream
+		// picks up right after them.
+		r.stream = nil
+		if r.reopener.reopen(r.ctx, err) {
+			return r.readNextResponse()
+		}
 		return nil, fmt.Errorf("error receiving BigQuery stream data: %w", err)
 	}
+	r.reopener.reset()
 	r.offset += response.GetRowCount()
+	r.stats.addResponse(response)
 	return response, nil
 }
 
+// checkSchema compares a schema sent with a ReadRows response against the
+// schema batches are decoded with, applying the reader's SchemaChangePolicy on
+// a mismatch. Adopting a schema changes batchSchema and schemaBytes but not
+// the session schema Schema reports.
+func (r *BigQueryReader) checkSchema(schemaBytes []byte) error {
+	if bytes.Equal(schemaBytes, r.schemaBytes) {
+		return nil
+	}
+	ipcReader, err := ipc.NewReader(bytes.NewReader(schemaBytes), ipc.WithAllocator(r.mem))
+	if err != nil {
+		return fmt.Errorf("failed to parse Arrow schema from ReadRows response: %w", err)
+	}
+	defer ipcReader.Release()
+	if ipcReader.Schema().Equal(r.batchSchema) {
+		return nil
+	}
+	if r.onSchemaChange != SchemaChangeAdopt {
+		return fmt.Errorf("%w: got %s, session has %s", ErrSchemaChangedMidStream, ipcReader.Schema(), r.batchSchema)
+	}
+
+	r.batchSchema = ipcReader.Schema()
+	r.schemaBytes = schemaBytes
+	return nil
+}
+
 // processRecordBatch merges schema + batch, reinitializes the IPC reader to parse it.
 func (r *BigQueryReader) processRecordBatch(data []byte) (arrow.Record, error) {
 	// Wipe old buffer, re-inject schema + batch
@@ -218,10 +618,14 @@ func (r *BigQueryReader) processRecordBatch(data []byte) (arrow.Record, error) {
 	r.buf.Write(r.schemaBytes)
 	r.buf.Write(data)
 
-	// We reuse the existing schema from r.r.Schema() if needed:
-	schema := r.r.Schema()
+	// The previous batch's reader is exhausted; records it returned were
+	// retained and outlive it.
+	if r.r != nil {
+		r.r.Release()
+		r.r = nil
+	}
 	var err error
-	r.r, err = ipc.NewReader(r.buf, ipc.WithAllocator(r.mem), ipc.WithSchema(schema))
+	r.r, err = ipc.NewReader(r.buf, ipc.WithAllocator(r.mem), ipc.WithSchema(r.batchSchema))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create new IPC reader for batch: %w", err)
 	}
@@ -238,22 +642,76 @@ func (r *BigQueryReader) processRecordBatch(data []byte) (arrow.Record, error) {
 	return nil, nil
 }
 
-// Schema retrieves the Arrow schema from the BQ read session. Must be called
-// after initialization. Returns an error if the schema is not ready.
+// Schema returns the read session's Arrow schema, parsed when the reader was
+// created. It is available before the first Read and stays the same however
+// much has been read, even after Close. With SchemaChangeAdopt, records after
+// a mid-stream schema change don't match it.
 func (r *BigQueryReader) Schema() (*arrow.Schema, error) {
-	if r.r == nil {
-		return nil, fmt.Errorf("no schema or IPC reader available")
+	if r.schema == nil {
+		return nil, ErrNoSchema
 	}
-	return r.r.Schema(), nil
+	return r.schema, nil
+}
+
+// Estimate returns BigQuery's estimate of the rows and bytes the read session
+// will return, made when the session was created.
+func (r *BigQueryReader) Estimate() (rows, bytes int64) {
+	return r.estimatedRows, r.estimatedBytes
+}
+
+// SnapshotTime returns the time the table is read as of: the SnapshotTime
+// option if set, else when the read session was requested. Without the
+// option, BigQuery reads the table as it was when the session was created,
+// moments later.
+func (r *BigQueryReader) SnapshotTime() time.Time {
+	return r.snapshotTime
+}
+
+// Stats returns the reader's progress so far. Unlike the rest of the reader,
+// it is safe to call from another goroutine, e.g. to report progress
+// periodically while the transfer reads.
+func (r *BigQueryReader) Stats() ReadStats {
+	return r.stats.snapshot()
 }
 
-// Close cleans up resources used by the BigQueryReader. Safe to call multiple times.
+// Close cleans up resources used by the BigQueryReader, canceling any ReadRows
+// call in progress. Safe to call multiple times; Read fails with
+// ErrReaderClosed afterwards.
 func (r *BigQueryReader) Close() error {
+	r.closed = true
+	if r.stop != nil {
+		r.stop()
+	}
+	if r.cancel != nil {
+		r.cancel()
+		for res := range r.results {
+			if res.rec != nil {
+				res.rec.Release()
+			}
+		}
+		r.cancel = nil
+	}
+	if r.stopDecoders != nil {
+		r.stopDecoders()
+		for out := range r.decoded {
+			if res := <-out; res.rec != nil {
+				res.rec.Release()
+			}
+		}
+		r.stopDecoders = nil
+	}
 	if r.r != nil {
 		r.r.Release()
 		r.r = nil
 	}
-	// We don't explicitly close the gRPC stream. No official method in generated stubs.
-	// It's sufficient to discard the client or let the context expire.
+	if r.sliced != nil {
+		r.sliced.Release()
+		r.sliced = nil
+	}
+	if r.fallback != nil {
+		r.fallback.Close()
+		r.fallback = nil
+	}
+	r.stream = nil
 	return nil
 }
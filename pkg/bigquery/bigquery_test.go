@@ -0,0 +1,111 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestParseSchemaChangePolicy(t *testing.T) {
+	for s, want := range map[string]SchemaChangePolicy{
+		"":        SchemaChangeError,
+		"error":   SchemaChangeError,
+		" Adopt ": SchemaChangeAdopt,
+	} {
+		got, err := ParseSchemaChangePolicy(s)
+		if err != nil || got != want {
+			t.Errorf("ParseSchemaChangePolicy(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseSchemaChangePolicy("ignore"); err == nil {
+		t.Error("ParseSchemaChangePolicy(ignore) succeeded, want an error")
+	}
+}
+
+// widerSchema adds a column to int64Schema, as if the table gained one
+// partway through a read.
+var widerSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+}, nil)
+
+// widerResponse returns a response that announces widerSchema and carries
+// one row of it.
+func widerResponse(t *testing.T) *storagepb.ReadRowsResponse {
+	t.Helper()
+	b := array.NewRecordBuilder(memory.DefaultAllocator, widerSchema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).Append(100)
+	b.Field(1).(*array.StringBuilder).Append("new")
+	rec := b.NewRecord()
+	defer rec.Release()
+	resp := arrowResponse(t, rec)
+	resp.Schema = &storagepb.ReadRowsResponse_ArrowSchema{ArrowSchema: &storagepb.ArrowSchema{SerializedSchema: serializeSchema(t, widerSchema)}}
+	return resp
+}
+
+func TestReaderSchemaChangeMidStream(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		policy  SchemaChangePolicy
+		wantErr error
+		wantIDs []int64
+	}{
+		{"error", SchemaChangeError, ErrSchemaChangedMidStream, []int64{0, 1}},
+		{"adopt", SchemaChangeAdopt, io.EOF, []int64{0, 1, 100}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			client := newFakeReadClient(t, &fakeReadServer{
+				schema:  serializeSchema(t, int64Schema),
+				streams: [][]*storagepb.ReadRowsResponse{append(int64Responses(t, 0, 2), widerResponse(t))},
+			})
+			r, err := client.NewBigQueryReader(context.Background(), "p", "d", "t", &BigQueryReaderOptions{OnSchemaChange: tc.policy})
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer r.Close()
+
+			ids, err := readIDs(t, r)
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tc.wantErr)
+			}
+			if !slices.Equal(ids, tc.wantIDs) {
+				t.Errorf("read ids %v, want %v", ids, tc.wantIDs)
+			}
+			// Schema keeps reporting the session's schema.
+			if schema, _ := r.Schema(); !schema.Equal(int64Schema) {
+				t.Errorf("Schema() = %s, want the session schema", schema)
+			}
+		})
+	}
+}
+
+func TestReaderSchemaRepeatedUnchanged(t *testing.T) {
+	resps := int64Responses(t, 0, 2, 3)
+	// Every ReadRows call's first response repeats the session schema.
+	resps[0].Schema = &storagepb.ReadRowsResponse_ArrowSchema{ArrowSchema: &storagepb.ArrowSchema{SerializedSchema: serializeSchema(t, int64Schema)}}
+	client := newFakeReadClient(t, &fakeReadServer{
+		schema:  serializeSchema(t, int64Schema),
+		streams: [][]*storagepb.ReadRowsResponse{resps},
+	})
+	r, err := client.NewBigQueryReader(context.Background(), "p", "d", "t", &BigQueryReaderOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ids, err := readIDs(t, r)
+	if err != io.EOF {
+		t.Fatalf("err = %v, want io.EOF", err)
+	}
+	if want := []int64{0, 1, 2, 3, 4}; !slices.Equal(ids, want) {
+		t.Errorf("read ids %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,283 @@
+package bigquery
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// cacheFileExt is the extension used for cached Arrow IPC streams.
+const cacheFileExt = ".arrows"
+
+// ReadCache stores completed BigQuery reads on local disk as Arrow IPC streams so
+// repeated reads of the same data skip the Storage API. It is meant for development
+// loops only: nothing is cached unless a ReadCache is explicitly passed in.
+//
+// A ReadCache may be shared by concurrent readers. Entries are written to a
+// temporary file and renamed into place, so readers never see a partial entry.
+type ReadCache struct {
+	Dir      string
+	TTL      time.Duration // Entries older than this are ignored and removed. Zero means no expiry.
+	MaxBytes int64         // Oldest entries are evicted once the cache exceeds this size. Zero means unbounded.
+}
+
+// NewReadCache creates a cache rooted at dir, creating the directory if needed.
+func NewReadCache(dir string, ttl time.Duration, maxBytes int64) (*ReadCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	return &ReadCache{Dir: dir, TTL: ttl, MaxBytes: maxBytes}, nil
+}
+
+// CacheKey identifies a cached read. Two reads share an entry only if they target
+// the same table with the same columns, row restriction, and snapshot time.
+type CacheKey struct {
+	Table           string
+	SelectedColumns []string
+	RowRestriction  string
+	SnapshotTime    time.Time
+}
+
+// cacheKey returns the key of a read of the table with these options.
+func (o *BigQueryReaderOptions) cacheKey(project, dataset, table string) CacheKey {
+	key := CacheKey{
+		Table:        fmt.Sprintf("projects/%s/datasets/%s/tables/%s", project, dataset, table),
+		SnapshotTime: o.SnapshotTime,
+	}
+	if ro := o.readOptions(); ro != nil {
+		key.SelectedColumns = ro.GetSelectedFields()
+		key.RowRestriction = ro.GetRowRestriction()
+	}
+	return key
+}
+
+// path returns the cache file for the key.
+func (c *ReadCache) path(key CacheKey) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%d", key.Table, strings.Join(key.SelectedColumns, ","), key.RowRestriction, key.SnapshotTime.UnixNano())
+	return filepath.Join(c.Dir, hex.EncodeToString(h.Sum(nil))+cacheFileExt)
+}
+
+// lookup returns the cache file for key if a fresh entry exists.
+func (c *ReadCache) lookup(key CacheKey) (string, bool) {
+	p := c.path(key)
+	info, err := os.Stat(p)
+	if err != nil {
+		return "", false
+	}
+	if c.TTL > 0 && time.Since(info.ModTime()) > c.TTL {
+		os.Remove(p)
+		return "", false
+	}
+	return p, true
+}
+
+// evict removes expired entries and then the oldest entries until the cache fits MaxBytes.
+func (c *ReadCache) evict() error {
+	entries, err := os.ReadDir(c.Dir)
+	if err != nil {
+		return fmt.Errorf("failed to list cache directory: %w", err)
+	}
+
+	type cacheFile struct {
+		path    string
+		size    int64
+		modTime time.Time
+	}
+	var files []cacheFile
+	var total int64
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != cacheFileExt {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		p := filepath.Join(c.Dir, e.Name())
+		if c.TTL > 0 && time.Since(info.ModTime()) > c.TTL {
+			os.Remove(p)
+			continue
+		}
+		files = append(files, cacheFile{path: p, size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	if c.MaxBytes <= 0 {
+		return nil
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+	for _, f := range files {
+		if total <= c.MaxBytes {
+			break
+		}
+		if err := os.Remove(f.path); err == nil {
+			total -= f.size
+		}
+	}
+	return nil
+}
+
+// CachedReader serves records from the read cache on a hit, and otherwise reads
+// from BigQuery while writing the records into the cache. A cache entry is only
+// committed once the underlying read reaches io.EOF, so partial reads are never cached.
+type CachedReader struct {
+	cache *ReadCache
+	final string
+
+	// Set on a cache hit.
+	file *os.File
+	ipcR *ipc.Reader
+
+	// Set on a cache miss.
+	src  *BigQueryReader
+	tmp  *os.File
+	ipcW *ipc.Writer
+	mem  memory.Allocator
+
+	closed bool
+}
+
+// NewCachedReader returns a reader for the table that is served from cache when a
+// fresh entry exists. On a miss a regular BigQueryReader is opened and its records
+// are written through to the cache.
+func (c *BigQueryReadClient) NewCachedReader(ctx context.Context, cache *ReadCache, project, dataset, table string, opts *BigQueryReaderOptions) (*CachedReader, error) {
+	key := opts.cacheKey(project, dataset, table)
+	mem := opts.allocator()
+	if p, ok := cache.lookup(key); ok {
+		f, err := os.Open(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open cache entry: %w", err)
+		}
+		r, err := ipc.NewReader(f, ipc.WithAllocator(mem))
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to read cache entry: %w", err)
+		}
+		return &CachedReader{cache: cache, final: p, file: f, ipcR: r, mem: mem}, nil
+	}
+
+	src, err := c.NewBigQueryReader(ctx, project, dataset, table, opts)
+	if err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(cache.Dir, "partial-*")
+	if err != nil {
+		src.Close()
+		return nil, fmt.Errorf("failed to create cache file: %w", err)
+	}
+	return &CachedReader{cache: cache, final: cache.path(key), src: src, tmp: tmp, mem: mem}, nil
+}
+
+// Hit reports whether the reader is being served from the cache.
+func (r *CachedReader) Hit() bool {
+	return r.ipcR != nil
+}
+
+// Read returns the next record, or io.EOF when the data is exhausted. Each record
+// must be released by the caller.
+func (r *CachedReader) Read() (arrow.Record, error) {
+	if r.closed {
+		return nil, ErrReaderClosed
+	}
+	if r.ipcR != nil {
+		if r.ipcR.Next() {
+			rec := r.ipcR.Record()
+			rec.Retain()
+			return rec, nil
+		}
+		if err := r.ipcR.Err(); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("failed to read cache entry: %w", err)
+		}
+		return nil, io.EOF
+	}
+	if r.src == nil {
+		return nil, io.EOF
+	}
+
+	rec, err := r.src.Read()
+	if err == io.EOF {
+		if err := r.commit(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if r.ipcW == nil {
+		r.ipcW = ipc.NewWriter(r.tmp, ipc.WithSchema(rec.Schema()), ipc.WithAllocator(r.mem))
+	}
+	if err := r.ipcW.Write(rec); err != nil {
+		rec.Release()
+		return nil, fmt.Errorf("failed to write record to cache: %w", err)
+	}
+	return rec, nil
+}
+
+// commit finalizes the cache entry after a complete read and applies eviction.
+func (r *CachedReader) commit() error {
+	if r.ipcW == nil {
+		// Nothing was read; don't cache an empty stream without a schema.
+		r.discard()
+		return nil
+	}
+	if err := r.ipcW.Close(); err != nil {
+		r.discard()
+		return fmt.Errorf("failed to finalize cache entry: %w", err)
+	}
+	r.ipcW = nil
+	if err := r.tmp.Close(); err != nil {
+		r.discard()
+		return fmt.Errorf("failed to close cache file: %w", err)
+	}
+	if err := os.Rename(r.tmp.Name(), r.final); err != nil {
+		r.discard()
+		return fmt.Errorf("failed to commit cache entry: %w", err)
+	}
+	r.tmp = nil
+	return r.cache.evict()
+}
+
+// discard removes a partially written cache file.
+func (r *CachedReader) discard() {
+	if r.ipcW != nil {
+		r.ipcW.Close()
+		r.ipcW = nil
+	}
+	if r.tmp != nil {
+		r.tmp.Close()
+		os.Remove(r.tmp.Name())
+		r.tmp = nil
+	}
+}
+
+// Close releases the reader. An incomplete write-through entry is discarded.
+// Safe to call multiple times; Read fails with ErrReaderClosed afterwards.
+func (r *CachedReader) Close() error {
+	r.closed = true
+	if r.ipcR != nil {
+		r.ipcR.Release()
+		r.ipcR = nil
+		r.file.Close()
+	}
+	r.discard()
+	if r.src != nil {
+		err := r.src.Close()
+		r.src = nil
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package bigquery
+
+import (
+	"context"
+	"io"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+)
+
+// writeCacheEntry commits a cache entry for key holding the ids first to
+// first+n-1.
+func writeCacheEntry(t *testing.T, cache *ReadCache, key CacheKey, first int64, n int) {
+	t.Helper()
+	f, err := os.Create(cache.path(key))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rec := int64Record(first, n)
+	defer rec.Release()
+	w := ipc.NewWriter(f, ipc.WithSchema(rec.Schema()))
+	if err := w.Write(rec); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCachedReaderKeysSnapshotTime(t *testing.T) {
+	cache, err := NewReadCache(t.TempDir(), 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newFakeReadClient(t, &fakeReadServer{
+		schema:  serializeSchema(t, int64Schema),
+		streams: [][]*storagepb.ReadRowsResponse{int64Responses(t, 0, 2)},
+	})
+	ctx := context.Background()
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	snapshotA := &BigQueryReaderOptions{SnapshotTime: at}
+	snapshotB := &BigQueryReaderOptions{SnapshotTime: at.Add(time.Hour)}
+	live := &BigQueryReaderOptions{}
+	writeCacheEntry(t, cache, snapshotA.cacheKey("p", "d", "t"), 100, 3)
+
+	if _, ok := cache.lookup(snapshotB.cacheKey("p", "d", "t")); ok {
+		t.Error("a read at another snapshot time hit the cache entry")
+	}
+
+	// A live read misses the snapshot's entry and writes its own.
+	r, err := client.NewCachedReader(ctx, cache, "p", "d", "t", live)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Hit() {
+		t.Error("live read hit the snapshot's cache entry")
+	}
+	ids, err := readIDs(t, r)
+	r.Close()
+	if err != io.EOF || !slices.Equal(ids, []int64{0, 1}) {
+		t.Fatalf("live read = %v, %v; want [0 1], io.EOF", ids, err)
+	}
+	if _, ok := cache.lookup(live.cacheKey("p", "d", "t")); !ok {
+		t.Error("live read wasn't cached")
+	}
+
+	// The snapshot's entry is still served to reads at that time.
+	r, err = client.NewCachedReader(ctx, cache, "p", "d", "t", snapshotA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if !r.Hit() {
+		t.Fatal("read at the cached snapshot time missed")
+	}
+	if ids, err := readIDs(t, r); err != io.EOF || !slices.Equal(ids, []int64{100, 101, 102}) {
+		t.Errorf("snapshot read = %v, %v; want [100 101 102], io.EOF", ids, err)
+	}
+}
@@ -0,0 +1,148 @@
+package bigquery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// decodeJob is a serialized record batch waiting to be decoded, with the
+// schema it was sent under. The result goes to out.
+type decodeJob struct {
+	data        []byte
+	schemaBytes []byte
+	schema      *arrow.Schema
+	rows        int64
+	out         chan streamResult
+}
+
+// readDecoded returns the next record decoded by the worker pool, starting
+// the pool on the first call.
+func (r *BigQueryReader) readDecoded() (arrow.Record, error) {
+	if r.decoded == nil {
+		r.startDecoders()
+	}
+	for {
+		out, ok := <-r.decoded
+		if !ok {
+			return nil, io.EOF
+		}
+		res := <-out
+		if res.err != nil || res.rec != nil {
+			return res.rec, res.err
+		}
+		// The batch held no record; move on to the next one.
+	}
+}
+
+// startDecoders receives batches on one goroutine and decodes them on
+// decodeConcurrency workers. Each batch's result slot is queued in arrival
+// order, so records come out in stream order however the decoding
+// interleaves; the queue holds one slot per worker, which bounds the batches
+// in flight.
+func (r *BigQueryReader) startDecoders() {
+	workers := r.decodeConcurrency
+	ctx, cancel := context.WithCancel(r.ctx)
+	r.ctx = ctx
+	r.stopDecoders = cancel
+	r.decoded = make(chan chan streamResult, workers)
+
+	jobs := make(chan decodeJob)
+	for range workers {
+		go func() {
+			for job := range jobs {
+				rec, err := decodeBatch(job, r.mem)
+				job.out <- streamResult{rec: rec, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		defer close(r.decoded)
+		defer close(jobs)
+
+		fail := func(err error) {
+			out := make(chan streamResult, 1)
+			out <- streamResult{err: err}
+			select {
+			case r.decoded <- out:
+			case <-ctx.Done():
+			}
+		}
+		for {
+			resp, err := r.readNextResponse()
+			if err == io.EOF {
+				return
+			}
+			if err != nil {
+				fail(err)
+				return
+			}
+			if s := resp.GetArrowSchema().GetSerializedSchema(); len(s) > 0 {
+				if err := r.checkSchema(s); err != nil {
+					fail(err)
+					return
+				}
+			}
+			batch := resp.GetArrowRecordBatch().GetSerializedRecordBatch()
+			if len(batch) == 0 {
+				continue
+			}
+
+			job := decodeJob{
+				data:        batch,
+				schemaBytes: r.schemaBytes,
+				schema:      r.batchSchema,
+				rows:        resp.GetRowCount(),
+				out:         make(chan streamResult, 1),
+			}
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				return
+			}
+			select {
+			case r.decoded <- job.out:
+			case <-ctx.Done():
+				// Nobody will read this result; release it once decoded,
+				// before r.decoded is closed and Close returns.
+				if res := <-job.out; res.rec != nil {
+					res.rec.Release()
+				}
+				return
+			}
+		}
+	}()
+}
+
+// decodeBatch decodes one serialized record batch with its own IPC reader.
+func decodeBatch(job decodeJob, mem memory.Allocator) (arrow.Record, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, len(job.schemaBytes)+len(job.data)))
+	buf.Write(job.schemaBytes)
+	buf.Write(job.data)
+
+	rdr, err := ipc.NewReader(buf, ipc.WithAllocator(mem), ipc.WithSchema(job.schema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create new IPC reader for batch: %w", err)
+	}
+	defer rdr.Release()
+	if !rdr.Next() {
+		if e := rdr.Err(); e != nil && e != io.EOF {
+			return nil, fmt.Errorf("arrow IPC read error: %w", e)
+		}
+		return nil, nil
+	}
+	rec := rdr.Record()
+	// A row count that disagrees with the response means the batch was
+	// decoded with the wrong layout.
+	if job.rows > 0 && rec.NumRows() != job.rows {
+		return nil, fmt.Errorf("%w: decoded %d rows but the batch holds %d", ErrSchemaChangedMidStream, rec.NumRows(), job.rows)
+	}
+	rec.Retain()
+	return rec, nil
+}
@@ -0,0 +1,155 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestDecodeConcurrencyKeepsStreamOrder(t *testing.T) {
+	sizes := make([]int, 50)
+	var want int64
+	for i := range sizes {
+		sizes[i] = 1 + i%7
+		want += int64(sizes[i])
+	}
+	client := newFakeReadClient(t, &fakeReadServer{
+		schema:  serializeSchema(t, int64Schema),
+		streams: [][]*storagepb.ReadRowsResponse{int64Responses(t, 0, sizes...)},
+	})
+	r, err := client.NewBigQueryReader(context.Background(), "p", "d", "t", &BigQueryReaderOptions{DecodeConcurrency: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	ids, err := readIDs(t, r)
+	if err != io.EOF {
+		t.Fatalf("err = %v, want io.EOF", err)
+	}
+	if int64(len(ids)) != want {
+		t.Fatalf("read %d rows, want %d", len(ids), want)
+	}
+	for i, id := range ids {
+		if id != int64(i) {
+			t.Fatalf("row %d has id %d; records came out of stream order", i, id)
+		}
+	}
+}
+
+// BenchmarkDecodeConcurrency reads 200 batches of 10,000 rows from a fake
+// stream with a growing decoder pool.
+func BenchmarkDecodeConcurrency(b *testing.B) {
+	sizes := make([]int, 200)
+	for i := range sizes {
+		sizes[i] = 10_000
+	}
+	client := newFakeReadClient(b, &fakeReadServer{
+		schema:  serializeSchema(b, int64Schema),
+		streams: [][]*storagepb.ReadRowsResponse{int64Responses(b, 0, sizes...)},
+	})
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run(fmt.Sprintf("workers=%d", workers), func(b *testing.B) {
+			b.SetBytes(200 * 10_000 * 8)
+			for range b.N {
+				r, err := client.NewBigQueryReader(context.Background(), "p", "d", "t", &BigQueryReaderOptions{DecodeConcurrency: workers})
+				if err != nil {
+					b.Fatal(err)
+				}
+				for {
+					rec, err := r.Read()
+					if err == io.EOF {
+						break
+					}
+					if err != nil {
+						b.Fatal(err)
+					}
+					rec.Release()
+				}
+				r.Close()
+			}
+		})
+	}
+}
+
+// TestReaderReleasesBatches reads every batch, including one sent after an
+// adopted schema change, and checks that nothing the reader allocated
+// outlives Close.
+func TestReaderReleasesBatches(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		streams int
+		decode  int
+		sliced  int64
+	}{
+		{name: "one stream"},
+		{name: "decoder pool", decode: 3},
+		{name: "streams", streams: 3},
+		{name: "sliced", sliced: 2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &fakeReadServer{schema: serializeSchema(t, int64Schema)}
+			for i := range max(tc.streams, 1) {
+				server.streams = append(server.streams, append(int64Responses(t, int64(i*100), 3, 5, 2), widerResponse(t)))
+			}
+			client := newFakeReadClient(t, server)
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			r, err := client.NewBigQueryReader(context.Background(), "p", "d", "t", &BigQueryReaderOptions{
+				Allocator:         mem,
+				DecodeConcurrency: tc.decode,
+				MaxCellsPerRecord: tc.sliced,
+				OnSchemaChange:    SchemaChangeAdopt,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			ids, err := readIDs(t, r)
+			if err != io.EOF {
+				t.Fatalf("err = %v, want io.EOF", err)
+			}
+			if want := 11 * max(tc.streams, 1); len(ids) != want {
+				t.Errorf("read %d rows, want %d", len(ids), want)
+			}
+			r.Close()
+		})
+	}
+}
+
+func TestReaderCloseMidReadReleasesBatches(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		streams int
+		decode  int
+	}{
+		{name: "one stream"},
+		{name: "decoder pool", decode: 3},
+		{name: "streams", streams: 3},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			server := &fakeReadServer{schema: serializeSchema(t, int64Schema)}
+			for i := range max(tc.streams, 1) {
+				server.streams = append(server.streams, int64Responses(t, int64(i*100), 3, 3, 3, 3, 3, 3))
+			}
+			client := newFakeReadClient(t, server)
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+
+			r, err := client.NewBigQueryReader(context.Background(), "p", "d", "t", &BigQueryReaderOptions{Allocator: mem, DecodeConcurrency: tc.decode})
+			if err != nil {
+				t.Fatal(err)
+			}
+			rec, err := r.Read()
+			if err != nil {
+				t.Fatal(err)
+			}
+			rec.Release()
+			r.Close()
+		})
+	}
+}
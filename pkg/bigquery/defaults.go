@@ -0,0 +1,72 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+
+	bq "cloud.google.com/go/bigquery"
+)
+
+// TableMetadata returns a table's metadata from the BigQuery API, including
+// its full schema with modes and nested fields, and its partitioning and
+// clustering, which the Storage API doesn't expose. A missing table is
+// reported as *ErrTableNotFound.
+func (c *BigQueryReadClient) TableMetadata(ctx context.Context, project, dataset, table string) (*bq.TableMetadata, error) {
+	client, err := bq.NewClient(ctx, project, c.clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	defer client.Close()
+
+	md, err := client.Dataset(dataset).Table(table).Metadata(ctx)
+	if isNotFound(err) {
+		return nil, &ErrTableNotFound{Table: TableRef{Project: project, Dataset: dataset, Table: table}, Err: err}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metadata of %s: %w", table, err)
+	}
+	return md, nil
+}
+
+// TableInfo is the size of a table as reported by its metadata.
+type TableInfo struct {
+	Type      bq.TableType
+	NumRows   uint64
+	NumBytes  int64
+	RowsKnown bool // False for views and external tables, which report no size.
+}
+
+// TableInfo confirms a table exists and returns its row count and size
+// without opening a read session, so callers can log an estimate and size
+// the read before starting it. A missing table is reported as
+// *ErrTableNotFound. The counts exclude rows in the streaming buffer.
+func (c *BigQueryReadClient) TableInfo(ctx context.Context, project, dataset, table string) (TableInfo, error) {
+	md, err := c.TableMetadata(ctx, project, dataset, table)
+	if err != nil {
+		return TableInfo{}, err
+	}
+	return TableInfo{
+		Type:      md.Type,
+		NumRows:   md.NumRows,
+		NumBytes:  md.NumBytes,
+		RowsKnown: md.Type == bq.RegularTable || md.Type == bq.Snapshot,
+	}, nil
+}
+
+// ColumnDefaults returns the default value expressions of a table's top-level
+// columns, keyed by column name, as BigQuery SQL. Columns without a default
+// are left out. The Storage API doesn't expose defaults, so they are read from
+// the table's metadata.
+func (c *BigQueryReadClient) ColumnDefaults(ctx context.Context, project, dataset, table string) (map[string]string, error) {
+	md, err := c.TableMetadata(ctx, project, dataset, table)
+	if err != nil {
+		return nil, err
+	}
+	defaults := make(map[string]string)
+	for _, f := range md.Schema {
+		if f.DefaultValueExpression != "" {
+			defaults[f.Name] = f.DefaultValueExpression
+		}
+	}
+	return defaults, nil
+}
@@ -0,0 +1,54 @@
+package bigquery
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/googleapi"
+)
+
+// ErrSchemaChangedMidStream is returned when BigQuery sends record batches whose
+// schema no longer matches the read session's schema. Decoding such batches with
+// the session schema would silently produce corrupt records.
+var ErrSchemaChangedMidStream = errors.New("arrow schema changed mid-stream")
+
+// ErrStalenessExceeded is returned when a table or materialized view may serve
+// data staler than the reader's MaxStaleness allows.
+var ErrStalenessExceeded = errors.New("source max_staleness exceeds the tolerated staleness")
+
+// ErrNoQueryDestination is returned when a query read produces no result
+// table to read, as for DDL, DML, and scripts.
+var ErrNoQueryDestination = errors.New("query produced no destination table")
+
+// ErrNoSchema is returned when a read session carries no schema to decode its
+// rows with.
+var ErrNoSchema = errors.New("no schema available")
+
+// ErrNoStreams is returned when a read session has no streams and its rows
+// can't be read through tabledata.list instead.
+var ErrNoStreams = errors.New("read session has no streams")
+
+// ErrReaderClosed is returned by Read after the reader has been closed.
+var ErrReaderClosed = errors.New("reader closed")
+
+// ErrTableNotFound is returned when the BigQuery API reports that a table
+// does not exist, so callers can tell a missing table from a transient error.
+type ErrTableNotFound struct {
+	Table TableRef
+	Err   error
+}
+
+func (e *ErrTableNotFound) Error() string {
+	return fmt.Sprintf("bigquery table %s not found: %v", e.Table, e.Err)
+}
+
+func (e *ErrTableNotFound) Unwrap() error {
+	return e.Err
+}
+
+// isNotFound reports whether err is a 404 from the BigQuery API.
+func isNotFound(err error) bool {
+	var apiErr *googleapi.Error
+	return errors.As(err, &apiErr) && apiErr.Code == http.StatusNotFound
+}
@@ -0,0 +1,147 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// TableRef names a BigQuery table.
+type TableRef struct {
+	Project string
+	Dataset string
+	Table   string
+}
+
+// String returns the table's name as "project.dataset.table".
+func (t TableRef) String() string {
+	return fmt.Sprintf("%s.%s.%s", t.Project, t.Dataset, t.Table)
+}
+
+// TableStats holds the read progress of one table of a MultiTableReader.
+type TableStats struct {
+	Table TableRef
+	ReadStats
+}
+
+// MultiTableReader reads a list of tables one after another through a single
+// BigQueryReadClient, reporting which table each record came from so it can be
+// routed to its own target. Unlike ShardedReader, the tables may have
+// unrelated schemas, so records from different tables differ in schema.
+// Like BigQueryReader, it is not safe for concurrent use, except for Stats.
+type MultiTableReader struct {
+	ctx    context.Context
+	client *BigQueryReadClient
+	tables []TableRef
+	opts   *BigQueryReaderOptions
+
+	current *BigQueryReader
+	next    int
+	closed  bool
+
+	mu    sync.Mutex
+	stats []TableStats // Of the tables opened so far.
+}
+
+// NewMultiTableReader creates a reader over tables, read in order with opts.
+// Tables are opened lazily, so only one read session is active at a time.
+func (c *BigQueryReadClient) NewMultiTableReader(ctx context.Context, tables []TableRef, opts *BigQueryReaderOptions) (*MultiTableReader, error) {
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("no tables to read")
+	}
+	return &MultiTableReader{
+		ctx:    ctx,
+		client: c,
+		tables: tables,
+		opts:   opts,
+	}, nil
+}
+
+// ReadTable returns the next record and the table it came from. When a table
+// is exhausted, its session is closed and the next table opened; io.EOF is
+// returned once every table has been read. Each record must be released by
+// the caller.
+func (m *MultiTableReader) ReadTable() (TableRef, arrow.Record, error) {
+	if m.closed {
+		return TableRef{}, nil, ErrReaderClosed
+	}
+	for {
+		if m.current == nil {
+			if m.next >= len(m.tables) {
+				return TableRef{}, nil, io.EOF
+			}
+			t := m.tables[m.next]
+			r, err := m.client.NewBigQueryReader(m.ctx, t.Project, t.Dataset, t.Table, m.opts)
+			if err != nil {
+				return t, nil, fmt.Errorf("failed to open %s: %w", t, err)
+			}
+			m.mu.Lock()
+			m.current = r
+			m.stats = append(m.stats, TableStats{Table: t})
+			m.mu.Unlock()
+			m.next++
+		}
+
+		t := m.tables[m.next-1]
+		rec, err := m.current.Read()
+		if errors.Is(err, io.EOF) {
+			m.finishTable()
+			continue
+		}
+		if err != nil {
+			return t, nil, fmt.Errorf("failed to read %s: %w", t, err)
+		}
+		return t, rec, nil
+	}
+}
+
+// Read returns the next record across all tables, or io.EOF once every table
+// is exhausted. Use ReadTable or Table to tell which table it came from.
+func (m *MultiTableReader) Read() (arrow.Record, error) {
+	_, rec, err := m.ReadTable()
+	return rec, err
+}
+
+// Table returns the table being read: the one the last record came from.
+func (m *MultiTableReader) Table() TableRef {
+	if m.next == 0 {
+		return TableRef{}
+	}
+	return m.tables[m.next-1]
+}
+
+// Stats returns the progress of each table opened so far, in order. It is
+// safe to call from another goroutine.
+func (m *MultiTableReader) Stats() []TableStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stats := append([]TableStats(nil), m.stats...)
+	if m.current != nil {
+		stats[len(stats)-1].ReadStats = m.current.Stats()
+	}
+	return stats
+}
+
+// finishTable records the final stats of the current table and closes it.
+func (m *MultiTableReader) finishTable() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stats[len(m.stats)-1].ReadStats = m.current.Stats()
+	err := m.current.Close()
+	m.current = nil
+	return err
+}
+
+// Close closes the table currently being read. Safe to call multiple times;
+// ReadTable fails with ErrReaderClosed afterwards.
+func (m *MultiTableReader) Close() error {
+	m.closed = true
+	if m.current == nil {
+		return nil
+	}
+	return m.finishTable()
+}
@@ -0,0 +1,175 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"unicode/utf8"
+
+	bq "cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// tableNamePattern matches a BigQuery table ID: letters, marks, numbers,
+// connectors (such as underscores), dashes, and spaces. IDs are at most
+// maxTableNameLength characters, which exceeds regexp's repeat limit.
+var tableNamePattern = regexp.MustCompile(`^[\p{L}\p{M}\p{N}\p{Pc}\p{Pd} ]+$`)
+
+const maxTableNameLength = 1024
+
+// ValidateTableName reports whether name is a valid, unqualified BigQuery table ID.
+func ValidateTableName(name string) error {
+	if !tableNamePattern.MatchString(name) || utf8.RuneCountInString(name) > maxTableNameLength {
+		return fmt.Errorf("invalid BigQuery table name %q", name)
+	}
+	return nil
+}
+
+// TablesFromQuery runs a SQL query in project and returns the table names in
+// the first column of its result, e.g. from an INFORMATION_SCHEMA.TABLES query
+// selecting tables modified since yesterday. Every name is validated, nulls
+// and duplicates are rejected, and an empty result is an error.
+func (c *BigQueryReadClient) TablesFromQuery(ctx context.Context, project, sql string) ([]string, error) {
+	client, err := bq.NewClient(ctx, project, c.clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	defer client.Close()
+
+	it, err := client.Query(sql).Read(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run table query: %w", err)
+	}
+
+	var tables []string
+	seen := make(map[string]bool)
+	for {
+		var row []bq.Value
+		err := it.Next(&row)
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read table query results: %w", err)
+		}
+		if len(row) == 0 {
+			return nil, fmt.Errorf("table query returned no columns")
+		}
+		name, ok := row[0].(string)
+		if !ok {
+			return nil, fmt.Errorf("table query returned %T in its first column, want a string", row[0])
+		}
+		if err := ValidateTableName(name); err != nil {
+			return nil, err
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("table query returned %q more than once", name)
+		}
+		seen[name] = true
+		tables = append(tables, name)
+	}
+	if len(tables) == 0 {
+		return nil, fmt.Errorf("table query returned no tables")
+	}
+	return tables, nil
+}
+
+// NewBigQueryReaderFromQuery runs a SQL query in project and returns a reader
+// over its result. BigQuery materializes query results in an anonymous table,
+// which the reader opens a read session on like any other table; that table is
+// owned by BigQuery and expires after about 24 hours, so it isn't deleted on
+// Close. Statements that produce no result table, such as DDL, DML, and
+// scripts, fail with ErrNoQueryDestination.
+func (c *BigQueryReadClient) NewBigQueryReaderFromQuery(ctx context.Context, project, sql string, opts *BigQueryReaderOptions) (*BigQueryReader, error) {
+	dst, err := c.QueryDestination(ctx, project, sql)
+	if err != nil {
+		return nil, err
+	}
+	return c.NewBigQueryReader(ctx, dst.ProjectID, dst.DatasetID, dst.TableID, opts)
+}
+
+// QueryDestination runs a SQL query in project and returns the table holding
+// its result; see NewBigQueryReaderFromQuery.
+func (c *BigQueryReadClient) QueryDestination(ctx context.Context, project, sql string) (*bq.Table, error) {
+	client, err := bq.NewClient(ctx, project, c.clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	defer client.Close()
+
+	job, err := client.Query(sql).Run(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run query: %w", err)
+	}
+	status, err := job.Wait(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wait for query job %s: %w", job.ID(), err)
+	}
+	if err := status.Err(); err != nil {
+		return nil, fmt.Errorf("query job %s failed: %w", job.ID(), err)
+	}
+
+	if stats, ok := status.Statistics.Details.(*bq.QueryStatistics); ok && stats.StatementType != "" && stats.StatementType != "SELECT" {
+		return nil, fmt.Errorf("%w: job %s ran a %s statement", ErrNoQueryDestination, job.ID(), stats.StatementType)
+	}
+	config, err := job.Config()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read query job %s configuration: %w", job.ID(), err)
+	}
+	qc, ok := config.(*bq.QueryConfig)
+	if !ok || qc.Dst == nil {
+		return nil, fmt.Errorf("%w: job %s", ErrNoQueryDestination, job.ID())
+	}
+	return qc.Dst, nil
+}
+
+// ListDatasets returns the IDs of the datasets in project, sorted.
+func (c *BigQueryReadClient) ListDatasets(ctx context.Context, project string) ([]string, error) {
+	client, err := bq.NewClient(ctx, project, c.clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	defer client.Close()
+
+	var datasets []string
+	it := client.Datasets(ctx)
+	for {
+		ds, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list datasets in %s: %w", project, err)
+		}
+		datasets = append(datasets, ds.DatasetID)
+	}
+	sort.Strings(datasets)
+	return datasets, nil
+}
+
+// ListTables returns the IDs of the tables and views in project.dataset,
+// sorted.
+func (c *BigQueryReadClient) ListTables(ctx context.Context, project, dataset string) ([]string, error) {
+	client, err := bq.NewClient(ctx, project, c.clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	defer client.Close()
+
+	var tables []string
+	it := client.Dataset(dataset).Tables(ctx)
+	for {
+		t, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tables in %s.%s: %w", project, dataset, err)
+		}
+		tables = append(tables, t.TableID)
+	}
+	sort.Strings(tables)
+	return tables, nil
+}
@@ -0,0 +1,163 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"time"
+
+	bq "cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+	"google.golang.org/api/iterator"
+)
+
+// columnNamePattern matches an unquoted BigQuery column name.
+var columnNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]{0,299}$`)
+
+// KeyRange is the half-open range [Lo, Hi) of an INT64 or DATE partition key.
+// DATE bounds are days since the Unix epoch.
+type KeyRange struct {
+	Column string
+	Date   bool
+	Lo, Hi int64
+
+	// First marks the range that also takes rows whose key is NULL.
+	First bool
+}
+
+// Restriction returns the range as a Storage API row restriction.
+func (r KeyRange) Restriction() string {
+	cond := fmt.Sprintf("%s >= %s AND %s < %s", r.Column, r.literal(r.Lo), r.Column, r.literal(r.Hi))
+	if r.First {
+		return fmt.Sprintf("%s IS NULL OR (%s)", r.Column, cond)
+	}
+	return cond
+}
+
+func (r KeyRange) String() string {
+	return fmt.Sprintf("[%s, %s)", r.literal(r.Lo), r.literal(r.Hi))
+}
+
+// literal renders a bound as a SQL literal.
+func (r KeyRange) literal(v int64) string {
+	if r.Date {
+		return fmt.Sprintf("DATE '%s'", time.Unix(v*86400, 0).UTC().Format("2006-01-02"))
+	}
+	return fmt.Sprintf("%d", v)
+}
+
+// SplitKeyRange divides the key domain [min, max] into n contiguous ranges of
+// near-equal width. Fewer ranges are returned when the domain has fewer than n
+// values.
+func SplitKeyRange(column string, date bool, min, max int64, n int) ([]KeyRange, error) {
+	if !columnNamePattern.MatchString(column) {
+		return nil, fmt.Errorf("invalid partition column name %q", column)
+	}
+	if n < 1 {
+		return nil, fmt.Errorf("range count must be at least 1, got %d", n)
+	}
+	if min > max {
+		return nil, fmt.Errorf("empty key domain [%d, %d]", min, max)
+	}
+	if max == math.MaxInt64 {
+		return nil, fmt.Errorf("key domain ends at the largest INT64 and can't be split into half-open ranges")
+	}
+
+	span := uint64(max-min) + 1
+	if uint64(n) > span {
+		n = int(span)
+	}
+	step, rem := span/uint64(n), span%uint64(n)
+
+	ranges := make([]KeyRange, 0, n)
+	lo := min
+	for i := 0; i < n; i++ {
+		width := step
+		if uint64(i) < rem {
+			width++
+		}
+		hi := lo + int64(width)
+		ranges = append(ranges, KeyRange{Column: column, Date: date, Lo: lo, Hi: hi, First: i == 0})
+		lo = hi
+	}
+	if err := ValidateKeyRanges(ranges, min, max); err != nil {
+		return nil, err
+	}
+	return ranges, nil
+}
+
+// ValidateKeyRanges checks that ranges cover [min, max] exactly: each range is
+// non-empty, starts where the previous one ended, and together they span the
+// domain with no gaps or overlaps. Exactly the first range takes NULL keys.
+func ValidateKeyRanges(ranges []KeyRange, min, max int64) error {
+	if len(ranges) == 0 {
+		return fmt.Errorf("no key ranges")
+	}
+	if ranges[0].Lo != min {
+		return fmt.Errorf("key ranges start at %d, not at the domain minimum %d", ranges[0].Lo, min)
+	}
+	for i, r := range ranges {
+		if r.Lo >= r.Hi {
+			return fmt.Errorf("key range %d %s is empty", i, r)
+		}
+		if r.First != (i == 0) {
+			return fmt.Errorf("key range %d %s: only the first range may take NULL keys", i, r)
+		}
+		if i > 0 && r.Lo != ranges[i-1].Hi {
+			if r.Lo > ranges[i-1].Hi {
+				return fmt.Errorf("gap between key ranges %s and %s", ranges[i-1], r)
+			}
+			return fmt.Errorf("key ranges %s and %s overlap", ranges[i-1], r)
+		}
+	}
+	if last := ranges[len(ranges)-1]; last.Hi-1 != max {
+		return fmt.Errorf("key ranges end at %d, not at the domain maximum %d", last.Hi-1, max)
+	}
+	return nil
+}
+
+// KeyBounds returns the smallest and largest value of an INT64 or DATE column,
+// with date reporting whether the column is a DATE. ok is false when the table
+// has no non-NULL keys.
+func (c *BigQueryReadClient) KeyBounds(ctx context.Context, project, dataset, table, column string) (min, max int64, date, ok bool, err error) {
+	if !columnNamePattern.MatchString(column) {
+		return 0, 0, false, false, fmt.Errorf("invalid partition column name %q", column)
+	}
+	client, err := bq.NewClient(ctx, project, c.clientOpts...)
+	if err != nil {
+		return 0, 0, false, false, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	defer client.Close()
+
+	sql := fmt.Sprintf("SELECT MIN(%s), MAX(%s) FROM `%s.%s.%s`", column, column, project, dataset, table)
+	it, err := client.Query(sql).Read(ctx)
+	if err != nil {
+		return 0, 0, false, false, fmt.Errorf("failed to query key bounds: %w", err)
+	}
+	var row []bq.Value
+	if err := it.Next(&row); err != nil {
+		if errors.Is(err, iterator.Done) {
+			err = fmt.Errorf("key bounds query returned no rows")
+		}
+		return 0, 0, false, false, err
+	}
+	if row[0] == nil || row[1] == nil {
+		return 0, 0, false, false, nil
+	}
+
+	switch lo := row[0].(type) {
+	case int64:
+		return lo, row[1].(int64), false, true, nil
+	case civil.Date:
+		return epochDays(lo), epochDays(row[1].(civil.Date)), true, true, nil
+	default:
+		return 0, 0, false, false, fmt.Errorf("partition column %s is %T; only INT64 and DATE keys are supported", column, row[0])
+	}
+}
+
+// epochDays returns the number of days between the Unix epoch and d.
+func epochDays(d civil.Date) int64 {
+	return d.In(time.UTC).Unix() / 86400
+}
@@ -0,0 +1,93 @@
+package bigquery
+
+import (
+	"errors"
+	"io"
+	"sync/atomic"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// recordReader adapts a BigQueryReader to array.RecordReader.
+type recordReader struct {
+	refs   atomic.Int64
+	src    *BigQueryReader
+	schema *arrow.Schema
+	cur    arrow.Record
+	done   bool
+	err    error
+}
+
+// NewRecordReader wraps r as an array.RecordReader, for Arrow utilities such
+// as array.NewTableFromRecordReader. The reader takes ownership of r: once its
+// last reference is released, r is closed.
+//
+// As with other Arrow record readers, the record returned by Record is only
+// valid until the next call to Next or Release; Retain it to keep it longer.
+// With SchemaChangeAdopt, records after a mid-stream schema change don't match
+// Schema.
+func NewRecordReader(r *BigQueryReader) (array.RecordReader, error) {
+	schema, err := r.Schema()
+	if err != nil {
+		return nil, err
+	}
+	rr := &recordReader{src: r, schema: schema}
+	rr.refs.Store(1)
+	return rr, nil
+}
+
+// Retain increases the reference count by 1.
+func (rr *recordReader) Retain() {
+	rr.refs.Add(1)
+}
+
+// Release decreases the reference count by 1, releasing the current record and
+// closing the BigQueryReader when it reaches zero.
+func (rr *recordReader) Release() {
+	if rr.refs.Add(-1) != 0 {
+		return
+	}
+	if rr.cur != nil {
+		rr.cur.Release()
+		rr.cur = nil
+	}
+	rr.src.Close()
+}
+
+// Schema returns the read session's schema.
+func (rr *recordReader) Schema() *arrow.Schema {
+	return rr.schema
+}
+
+// Next reads the next record, releasing the previous one. It returns false at
+// the end of the read or on an error, which Err then reports.
+func (rr *recordReader) Next() bool {
+	if rr.cur != nil {
+		rr.cur.Release()
+		rr.cur = nil
+	}
+	if rr.done {
+		return false
+	}
+	rec, err := rr.src.Read()
+	if err != nil {
+		rr.done = true
+		if !errors.Is(err, io.EOF) {
+			rr.err = err
+		}
+		return false
+	}
+	rr.cur = rec
+	return true
+}
+
+// Record returns the current record.
+func (rr *recordReader) Record() arrow.Record {
+	return rr.cur
+}
+
+// Err returns the error that ended the read, if any.
+func (rr *recordReader) Err() error {
+	return rr.err
+}
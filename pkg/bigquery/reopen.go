@@ -0,0 +1,70 @@
+package bigquery
+
+import (
+	"context"
+	"time"
+
+	"github.com/googleapis/gax-go/v2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultStreamReopens is how many times in a row a broken ReadRows stream
+// is reopened when BigQueryReaderOptions.MaxStreamReopens is zero.
+const defaultStreamReopens = 5
+
+// reopenBackoff paces the reopening of broken streams.
+var reopenBackoff = gax.Backoff{
+	Initial:    500 * time.Millisecond,
+	Max:        30 * time.Second,
+	Multiplier: 2,
+}
+
+// reopenable reports whether a ReadRows stream that failed with err may be
+// reopened at its current offset: the server dropped it or is overloaded,
+// rather than rejecting the request.
+func reopenable(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.Internal, codes.ResourceExhausted:
+		return true
+	default:
+		return false
+	}
+}
+
+// streamReopener counts the consecutive reopen attempts of one stream.
+type streamReopener struct {
+	max      int // Negative disables reopening; zero uses defaultStreamReopens.
+	attempts int
+	backoff  gax.Backoff
+}
+
+// reopen waits before the next attempt and reports whether one is allowed
+// after a stream failed with err. It returns false once the attempts are used
+// up, err isn't retryable, or ctx is done.
+func (s *streamReopener) reopen(ctx context.Context, err error) bool {
+	limit := s.max
+	if limit == 0 {
+		limit = defaultStreamReopens
+	}
+	if s.attempts >= limit || !reopenable(err) {
+		return false
+	}
+	if s.attempts == 0 {
+		s.backoff = reopenBackoff
+	}
+	s.attempts++
+	t := time.NewTimer(s.backoff.Pause())
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-t.C:
+		return true
+	}
+}
+
+// reset starts counting again after the stream delivered data.
+func (s *streamReopener) reset() {
+	s.attempts = 0
+}
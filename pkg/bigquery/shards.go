@@ -0,0 +1,206 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	bq "cloud.google.com/go/bigquery"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"google.golang.org/api/iterator"
+)
+
+// ShardColumn is the name of the column ShardedReader appends when AddShardColumn is set.
+const ShardColumn = "_shard"
+
+// TableShard is one table of a date-sharded (or otherwise suffixed) table family.
+type TableShard struct {
+	Table  string // Full table name, e.g. "events_20240101".
+	Suffix string // The part matched by the wildcard, e.g. "20240101".
+}
+
+// IsWildcardTable reports whether table is a wildcard pattern such as "events_*".
+func IsWildcardTable(table string) bool {
+	return strings.HasSuffix(table, "*")
+}
+
+// ListTableShards enumerates the tables in project.dataset that match a wildcard
+// pattern such as "events_*" and verifies they share a compatible schema.
+// Shards are returned sorted by name.
+func (c *BigQueryReadClient) ListTableShards(ctx context.Context, project, dataset, pattern string) ([]TableShard, error) {
+	if !IsWildcardTable(pattern) {
+		return nil, fmt.Errorf("table pattern %q must end with '*'", pattern)
+	}
+	prefix := strings.TrimSuffix(pattern, "*")
+
+	client, err := bq.NewClient(ctx, project, c.clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	defer client.Close()
+
+	var shards []TableShard
+	it := client.Dataset(dataset).Tables(ctx)
+	for {
+		t, err := it.Next()
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list tables in %s.%s: %w", project, dataset, err)
+		}
+		if strings.HasPrefix(t.TableID, prefix) {
+			shards = append(shards, TableShard{Table: t.TableID, Suffix: strings.TrimPrefix(t.TableID, prefix)})
+		}
+	}
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("no tables in %s.%s match %q", project, dataset, pattern)
+	}
+	sort.Slice(shards, func(i, j int) bool { return shards[i].Table < shards[j].Table })
+
+	// Validate every shard against the first one before any data moves.
+	var base bq.Schema
+	for i, shard := range shards {
+		md, err := client.Dataset(dataset).Table(shard.Table).Metadata(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get metadata for %s: %w", shard.Table, err)
+		}
+		if i == 0 {
+			base = md.Schema
+			continue
+		}
+		if err := compatibleSchemas(base, md.Schema); err != nil {
+			return nil, fmt.Errorf("shard %s is incompatible with %s: %w", shard.Table, shards[0].Table, err)
+		}
+	}
+	return shards, nil
+}
+
+// compatibleSchemas checks that two BigQuery schemas have the same columns in the
+// same order with the same types. Differences in nullability are allowed.
+func compatibleSchemas(a, b bq.Schema) error {
+	if len(a) != len(b) {
+		return fmt.Errorf("column count differs (%d vs %d)", len(a), len(b))
+	}
+	for i := range a {
+		if !strings.EqualFold(a[i].Name, b[i].Name) {
+			return fmt.Errorf("column %d is %q vs %q", i, a[i].Name, b[i].Name)
+		}
+		if a[i].Type != b[i].Type || a[i].Repeated != b[i].Repeated {
+			return fmt.Errorf("column %q has type %s vs %s", a[i].Name, a[i].Type, b[i].Type)
+		}
+		if err := compatibleSchemas(a[i].Schema, b[i].Schema); err != nil {
+			return fmt.Errorf("column %q: %w", a[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// ShardedReader reads a list of table shards one after another, presenting them
+// as a single stream of Arrow records.
+// Like BigQueryReader, it is not safe for concurrent use.
+type ShardedReader struct {
+	ctx     context.Context
+	client  *BigQueryReadClient
+	project string
+	dataset string
+	shards  []TableShard
+	opts    *BigQueryReaderOptions
+
+	// AddShardColumn appends a ShardColumn string column holding each row's shard suffix.
+	AddShardColumn bool
+
+	mem     memory.Allocator
+	current *BigQueryReader
+	next    int
+	closed  bool
+}
+
+// NewShardedReader creates a reader over the given shards. Shards are opened lazily,
+// so only one read session is active at a time.
+func (c *BigQueryReadClient) NewShardedReader(ctx context.Context, project, dataset string, shards []TableShard, opts *BigQueryReaderOptions) (*ShardedReader, error) {
+	if len(shards) == 0 {
+		return nil, fmt.Errorf("no shards to read")
+	}
+	return &ShardedReader{
+		ctx:     ctx,
+		client:  c,
+		project: project,
+		dataset: dataset,
+		shards:  shards,
+		opts:    opts,
+		mem:     opts.allocator(),
+	}, nil
+}
+
+// Read returns the next record across all shards, or io.EOF once every shard is exhausted.
+// Each record must be released by the caller.
+func (s *ShardedReader) Read() (arrow.Record, error) {
+	if s.closed {
+		return nil, ErrReaderClosed
+	}
+	for {
+		if s.current == nil {
+			if s.next >= len(s.shards) {
+				return nil, io.EOF
+			}
+			r, err := s.client.NewBigQueryReader(s.ctx, s.project, s.dataset, s.shards[s.next].Table, s.opts)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open shard %s: %w", s.shards[s.next].Table, err)
+			}
+			s.current = r
+			s.next++
+		}
+
+		rec, err := s.current.Read()
+		if err == io.EOF {
+			s.current.Close()
+			s.current = nil
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !s.AddShardColumn {
+			return rec, nil
+		}
+		return s.withShardColumn(rec, s.shards[s.next-1].Suffix), nil
+	}
+}
+
+// withShardColumn returns a new record with the shard suffix appended as a column.
+// The input record is released.
+func (s *ShardedReader) withShardColumn(rec arrow.Record, suffix string) arrow.Record {
+	defer rec.Release()
+
+	b := array.NewStringBuilder(s.mem)
+	defer b.Release()
+	b.Reserve(int(rec.NumRows()))
+	for i := int64(0); i < rec.NumRows(); i++ {
+		b.Append(suffix)
+	}
+	shardCol := b.NewArray()
+	defer shardCol.Release()
+
+	fields := append(append([]arrow.Field{}, rec.Schema().Fields()...), arrow.Field{Name: ShardColumn, Type: arrow.BinaryTypes.String})
+	md := rec.Schema().Metadata()
+	cols := append(append([]arrow.Array{}, rec.Columns()...), shardCol)
+	return array.NewRecord(arrow.NewSchema(fields, &md), cols, rec.NumRows())
+}
+
+// Close closes the shard currently being read. Safe to call multiple times;
+// Read fails with ErrReaderClosed afterwards.
+func (s *ShardedReader) Close() error {
+	s.closed = true
+	if s.current != nil {
+		err := s.current.Close()
+		s.current = nil
+		return err
+	}
+	return nil
+}
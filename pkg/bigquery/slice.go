@@ -0,0 +1,26 @@
+package bigquery
+
+import "github.com/apache/arrow-go/v18/arrow"
+
+// readSliced returns the next slice of at most maxCells cells, reading a new
+// record once the previous one has been handed out.
+func (r *BigQueryReader) readSliced() (arrow.Record, error) {
+	if r.sliced == nil {
+		rec, err := r.read()
+		if err != nil {
+			return nil, err
+		}
+		r.sliced = rec
+	}
+	rows := max(r.maxCells/max(r.sliced.NumCols(), 1), 1)
+	if r.sliced.NumRows() <= rows {
+		rec := r.sliced
+		r.sliced = nil
+		return rec, nil
+	}
+	rec := r.sliced.NewSlice(0, rows)
+	rest := r.sliced.NewSlice(rows, r.sliced.NumRows())
+	r.sliced.Release()
+	r.sliced = rest
+	return rec, nil
+}
@@ -0,0 +1,52 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	bq "cloud.google.com/go/bigquery"
+)
+
+// defaultTimeTravel is BigQuery's time travel window for datasets that don't
+// set max_time_travel_hours.
+const defaultTimeTravel = 7 * 24 * time.Hour
+
+// checkSnapshotTime checks that a table can be read as of at: not in the
+// future, and within its dataset's time travel window.
+func (c *BigQueryReadClient) checkSnapshotTime(ctx context.Context, project, dataset string, at time.Time) error {
+	now := time.Now()
+	if at.After(now) {
+		return fmt.Errorf("snapshot time %s is in the future", at.Format(time.RFC3339))
+	}
+
+	client, err := bq.NewClient(ctx, project, c.clientOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	defer client.Close()
+
+	md, err := client.Dataset(dataset).Metadata(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read metadata of dataset %s: %w", dataset, err)
+	}
+	window := md.MaxTimeTravel
+	if window <= 0 {
+		window = defaultTimeTravel
+	}
+	if now.Sub(at) > window {
+		return fmt.Errorf("snapshot time %s is older than the %s time travel window of dataset %s",
+			at.Format(time.RFC3339), window, dataset)
+	}
+	return nil
+}
+
+// snapshotDecorator returns table with a snapshot decorator reading it as of
+// at, for APIs without table modifiers such as tabledata.list. A zero at
+// returns table unchanged.
+func snapshotDecorator(table string, at time.Time) string {
+	if at.IsZero() {
+		return table
+	}
+	return fmt.Sprintf("%s@%d", table, at.UnixMilli())
+}
@@ -0,0 +1,37 @@
+package bigquery
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	bq "cloud.google.com/go/bigquery"
+)
+
+// checkStaleness compares a table's max_staleness option with the staleness a
+// reader tolerates. The Storage API has no per-session staleness setting:
+// tables with change data capture and materialized views that set
+// max_staleness are served from their last applied state within that bound,
+// which is cheaper than merging pending changes. A source without the option
+// is read fresh, so it always passes; one whose bound is larger than tolerated
+// fails with ErrStalenessExceeded.
+func (c *BigQueryReadClient) checkStaleness(ctx context.Context, project, dataset, table string, tolerated time.Duration) error {
+	client, err := bq.NewClient(ctx, project, c.clientOpts...)
+	if err != nil {
+		return fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	defer client.Close()
+
+	md, err := client.Dataset(dataset).Table(table).Metadata(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read metadata of %s: %w", table, err)
+	}
+	if md.MaxStaleness == nil {
+		return nil
+	}
+	if staleness := md.MaxStaleness.ToDuration(); staleness > tolerated {
+		return fmt.Errorf("%w: %s %s allows data up to %s old, more than the %s tolerated",
+			ErrStalenessExceeded, md.Type, table, staleness, tolerated)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package bigquery
+
+import (
+	"math"
+	"sync/atomic"
+
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+
+	"github.com/TFMV/syncronicity/pkg/metrics"
+)
+
+// ReadStats reports a BigQueryReader's progress.
+type ReadStats struct {
+	// RowsRead and BatchesRead count the rows and non-empty batches received
+	// from the Storage API (or tabledata.list for a session without streams).
+	RowsRead    int64
+	BatchesRead int64
+	// BytesProcessed is the size of the serialized batches received.
+	BytesProcessed int64
+	// EstimatedRowsTotal is BigQuery's estimate of the rows the read will
+	// return: the session's estimate, or for a single-stream read without
+	// one, the rows read so far scaled by the stream's reported progress. It
+	// is 0 when unknown.
+	EstimatedRowsTotal int64
+}
+
+// readStats accumulates ReadStats. It is shared by a reader and the readers of
+// its streams and is safe for concurrent use.
+type readStats struct {
+	rows, batches, bytes atomic.Int64
+	estimatedRows        int64 // From the session; fixed.
+	trackProgress        bool  // Whether progress is from a single stream.
+	progress             atomic.Uint64
+	metrics              metrics.Metrics // Also receives rows and bytes read.
+}
+
+// newReadStats creates the stats of a session with the given estimate and
+// number of streams, reporting to m if it isn't nil. Stream progress is only
+// meaningful for the whole read when there is a single stream.
+func newReadStats(estimatedRows int64, streams int, m metrics.Metrics) *readStats {
+	return &readStats{estimatedRows: estimatedRows, trackProgress: streams <= 1, metrics: metrics.Or(m)}
+}
+
+// addResponse records a ReadRows response.
+func (s *readStats) addResponse(resp *storagepb.ReadRowsResponse) {
+	if n := resp.GetRowCount(); n > 0 {
+		s.rows.Add(n)
+		s.batches.Add(1)
+		s.metrics.RecordRowsRead(n)
+	}
+	size := int64(len(resp.GetArrowRecordBatch().GetSerializedRecordBatch()) + len(resp.GetAvroRows().GetSerializedBinaryRows()))
+	s.bytes.Add(size)
+	s.metrics.RecordBytesRead(size)
+	if p := resp.GetStats().GetProgress().GetAtResponseEnd(); s.trackProgress && p > 0 {
+		s.progress.Store(math.Float64bits(p))
+	}
+}
+
+// addRecord records a record read without a ReadRows response.
+func (s *readStats) addRecord(rows int64) {
+	if rows > 0 {
+		s.rows.Add(rows)
+		s.batches.Add(1)
+		s.metrics.RecordRowsRead(rows)
+	}
+}
+
+// snapshot returns the current stats.
+func (s *readStats) snapshot() ReadStats {
+	stats := ReadStats{
+		RowsRead:           s.rows.Load(),
+		BatchesRead:        s.batches.Load(),
+		BytesProcessed:     s.bytes.Load(),
+		EstimatedRowsTotal: s.estimatedRows,
+	}
+	if stats.EstimatedRowsTotal <= 0 {
+		stats.EstimatedRowsTotal = 0
+		if p := math.Float64frombits(s.progress.Load()); p > 0 {
+			stats.EstimatedRowsTotal = int64(float64(stats.RowsRead) / p)
+		}
+	}
+	return stats
+}
@@ -0,0 +1,153 @@
+package bigquery
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net"
+	"testing"
+
+	"cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeReadServer is an in-memory BigQuery Storage Read API serving one read
+// session whose streams replay fixed responses.
+type fakeReadServer struct {
+	storagepb.UnimplementedBigQueryReadServer
+
+	schema  []byte
+	streams [][]*storagepb.ReadRowsResponse
+}
+
+func (s *fakeReadServer) CreateReadSession(ctx context.Context, req *storagepb.CreateReadSessionRequest) (*storagepb.ReadSession, error) {
+	session := &storagepb.ReadSession{
+		Name:   "session",
+		Schema: &storagepb.ReadSession_ArrowSchema{ArrowSchema: &storagepb.ArrowSchema{SerializedSchema: s.schema}},
+	}
+	for i := range s.streams {
+		session.Streams = append(session.Streams, &storagepb.ReadStream{Name: fmt.Sprintf("stream-%d", i)})
+	}
+	return session, nil
+}
+
+func (s *fakeReadServer) ReadRows(req *storagepb.ReadRowsRequest, srv storagepb.BigQueryRead_ReadRowsServer) error {
+	var i int
+	if _, err := fmt.Sscanf(req.GetReadStream(), "stream-%d", &i); err != nil || i >= len(s.streams) {
+		return fmt.Errorf("unknown stream %q", req.GetReadStream())
+	}
+	var offset int64
+	for _, resp := range s.streams[i] {
+		if offset >= req.GetOffset() {
+			if err := srv.Send(resp); err != nil {
+				return err
+			}
+		}
+		offset += resp.GetRowCount()
+	}
+	return nil
+}
+
+// newFakeReadClient starts server and returns a client connected to it.
+func newFakeReadClient(t testing.TB, server *fakeReadServer) *BigQueryReadClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	storagepb.RegisterBigQueryReadServer(srv, server)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := NewBigQueryReadClient(context.Background(), option.WithGRPCConn(conn))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// serializeSchema returns schema as BigQuery sends it: an IPC schema message.
+func serializeSchema(t testing.TB, schema *arrow.Schema) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(schema))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// Drop the end-of-stream marker.
+	return buf.Bytes()[:buf.Len()-8]
+}
+
+// arrowResponse returns a ReadRows response carrying rec as an IPC record
+// batch message.
+func arrowResponse(t testing.TB, rec arrow.Record) *storagepb.ReadRowsResponse {
+	t.Helper()
+	schema := serializeSchema(t, rec.Schema())
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(rec.Schema()))
+	if err := w.Write(rec); err != nil {
+		t.Fatal(err)
+	}
+	return &storagepb.ReadRowsResponse{
+		Rows: &storagepb.ReadRowsResponse_ArrowRecordBatch{ArrowRecordBatch: &storagepb.ArrowRecordBatch{
+			SerializedRecordBatch: bytes.Clone(buf.Bytes()[len(schema):]),
+			RowCount:              rec.NumRows(),
+		}},
+		RowCount: rec.NumRows(),
+	}
+}
+
+// int64Schema is the schema of the records built by int64Record.
+var int64Schema = arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+
+// int64Record returns a record of the ids first to first+n-1.
+func int64Record(first int64, n int) arrow.Record {
+	b := array.NewInt64Builder(memory.DefaultAllocator)
+	defer b.Release()
+	for i := 0; i < n; i++ {
+		b.Append(first + int64(i))
+	}
+	col := b.NewArray()
+	defer col.Release()
+	return array.NewRecord(int64Schema, []arrow.Array{col}, int64(n))
+}
+
+// int64Responses returns one ReadRows response per size, numbering rows from
+// first on.
+func int64Responses(t testing.TB, first int64, sizes ...int) []*storagepb.ReadRowsResponse {
+	t.Helper()
+	var out []*storagepb.ReadRowsResponse
+	for _, n := range sizes {
+		rec := int64Record(first, n)
+		out = append(out, arrowResponse(t, rec))
+		rec.Release()
+		first += int64(n)
+	}
+	return out
+}
+
+// readIDs reads every record of r and returns the ids in order.
+func readIDs(t testing.TB, r interface{ Read() (arrow.Record, error) }) ([]int64, error) {
+	t.Helper()
+	var ids []int64
+	for {
+		rec, err := r.Read()
+		if err != nil {
+			return ids, err
+		}
+		ids = append(ids, rec.Column(0).(*array.Int64).Int64Values()...)
+		rec.Release()
+	}
+}
@@ -0,0 +1,111 @@
+package bigquery
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	storagepb "cloud.google.com/go/bigquery/storage/apiv1/storagepb"
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// streamResult is a record, or the error that ended a stream, passed from a
+// stream reader to Read.
+type streamResult struct {
+	rec arrow.Record
+	err error
+}
+
+// readMerged returns the next record from any of the session's streams,
+// starting the stream readers on the first call.
+func (r *BigQueryReader) readMerged() (arrow.Record, error) {
+	if r.results == nil {
+		r.startStreams()
+	}
+	res, ok := <-r.results
+	if !ok {
+		return nil, io.EOF
+	}
+	return res.rec, res.err
+}
+
+// startStreams reads the session's streams on up to maxConcurrency
+// goroutines, each reading one stream at a time in order. Records are handed
+// over on a channel buffered to one record per goroutine. The first error
+// stops every stream.
+func (r *BigQueryReader) startStreams() {
+	workers := len(r.streams)
+	if r.maxConcurrency > 0 {
+		workers = min(workers, r.maxConcurrency)
+	}
+	ctx, cancel := context.WithCancel(r.ctx)
+	r.cancel = cancel
+	r.results = make(chan streamResult, workers)
+
+	pending := make(chan *storagepb.ReadStream, len(r.streams))
+	for _, s := range r.streams {
+		pending <- s
+	}
+	close(pending)
+
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for s := range pending {
+				if err := r.readStream(ctx, s); err != nil {
+					if !errors.Is(err, context.Canceled) || r.ctx.Err() != nil {
+						r.results <- streamResult{err: err}
+					}
+					cancel()
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(r.results)
+	}()
+}
+
+// readStream reads every record of one stream into r.results.
+func (r *BigQueryReader) readStream(ctx context.Context, s *storagepb.ReadStream) error {
+	// A reader for just this stream, with its own offset and IPC state.
+	sr := &BigQueryReader{
+		ctx:               ctx,
+		client:            r.client,
+		callOptions:       r.callOptions,
+		onSchemaChange:    r.onSchemaChange,
+		decodeConcurrency: r.decodeConcurrency,
+		schemaBytes:       r.schemaBytes,
+		schema:            r.schema,
+		batchSchema:       r.batchSchema,
+		streams:           []*storagepb.ReadStream{s},
+		reopener:          streamReopener{max: r.reopener.max},
+		mem:               r.mem,
+		buf:               bytes.NewBuffer(nil),
+		avro:              r.avro,
+		stats:             r.stats,
+	}
+	defer sr.Close()
+
+	for {
+		rec, err := sr.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		select {
+		case r.results <- streamResult{rec: rec}:
+		case <-ctx.Done():
+			rec.Release()
+			return ctx.Err()
+		}
+	}
+}
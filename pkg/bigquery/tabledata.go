@@ -0,0 +1,233 @@
+package bigquery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"time"
+
+	bq "cloud.google.com/go/bigquery"
+	"cloud.google.com/go/civil"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/decimal128"
+	"github.com/apache/arrow-go/v18/arrow/decimal256"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"google.golang.org/api/iterator"
+)
+
+// tabledataBatchRows is the number of rows per record built by a tabledataReader.
+const tabledataBatchRows = 10000
+
+// tabledataReader reads a table through the tabledata.list API and converts
+// its rows to Arrow records in the read session's schema. It backs readers
+// whose session has no streams: BigQuery sometimes returns none for very small
+// tables, whose few rows are cheap to page through the REST API instead.
+type tabledataReader struct {
+	client *bq.Client
+	it     *bq.RowIterator
+	schema *arrow.Schema
+	mem    memory.Allocator
+
+	columns []int // Index in each row of every schema field; set on the first row.
+}
+
+func (c *BigQueryReadClient) newTabledataReader(ctx context.Context, project, dataset, table string, schema *arrow.Schema, mem memory.Allocator) (*tabledataReader, error) {
+	client, err := bq.NewClient(ctx, project, c.clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create BigQuery client: %w", err)
+	}
+	return &tabledataReader{
+		client: client,
+		it:     client.Dataset(dataset).Table(table).Read(ctx),
+		schema: schema,
+		mem:    mem,
+	}, nil
+}
+
+// Read returns the next record of up to tabledataBatchRows rows, or io.EOF.
+func (t *tabledataReader) Read() (arrow.Record, error) {
+	b := array.NewRecordBuilder(t.mem, t.schema)
+	defer b.Release()
+
+	rows := 0
+	for rows < tabledataBatchRows {
+		var row []bq.Value
+		err := t.it.Next(&row)
+		if errors.Is(err, iterator.Done) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read table rows: %w", err)
+		}
+		if t.columns == nil {
+			if t.columns, err = t.columnIndexes(); err != nil {
+				return nil, err
+			}
+		}
+		for i, col := range t.columns {
+			if err := appendValue(b.Field(i), row[col]); err != nil {
+				return nil, fmt.Errorf("column %s: %w", t.schema.Field(i).Name, err)
+			}
+		}
+		rows++
+	}
+	if rows == 0 {
+		return nil, io.EOF
+	}
+	return b.NewRecord(), nil
+}
+
+// columnIndexes maps each schema field to its position in the rows, which hold
+// every table column even when the session selected only some of them.
+func (t *tabledataReader) columnIndexes() ([]int, error) {
+	byName := make(map[string]int, len(t.it.Schema))
+	for i, f := range t.it.Schema {
+		byName[f.Name] = i
+	}
+	cols := make([]int, len(t.schema.Fields()))
+	for i, f := range t.schema.Fields() {
+		col, ok := byName[f.Name]
+		if !ok {
+			return nil, fmt.Errorf("column %s is missing from the table rows", f.Name)
+		}
+		cols[i] = col
+	}
+	return cols, nil
+}
+
+// Close closes the BigQuery API client.
+func (t *tabledataReader) Close() error {
+	return t.client.Close()
+}
+
+// appendValue appends a value decoded by the BigQuery client library to the
+// builder of the matching Storage API Arrow type.
+func appendValue(b array.Builder, v bq.Value) error {
+	if v == nil {
+		b.AppendNull()
+		return nil
+	}
+	switch b := b.(type) {
+	case *array.Int64Builder:
+		n, ok := v.(int64)
+		if !ok {
+			return unexpectedValue(v, b.Type())
+		}
+		b.Append(n)
+	case *array.Float64Builder:
+		f, ok := v.(float64)
+		if !ok {
+			return unexpectedValue(v, b.Type())
+		}
+		b.Append(f)
+	case *array.BooleanBuilder:
+		x, ok := v.(bool)
+		if !ok {
+			return unexpectedValue(v, b.Type())
+		}
+		b.Append(x)
+	case *array.StringBuilder:
+		// STRING, GEOGRAPHY, and JSON all arrive as strings.
+		s, ok := v.(string)
+		if !ok {
+			return unexpectedValue(v, b.Type())
+		}
+		b.Append(s)
+	case *array.BinaryBuilder:
+		x, ok := v.([]byte)
+		if !ok {
+			return unexpectedValue(v, b.Type())
+		}
+		b.Append(x)
+	case *array.Date32Builder:
+		d, ok := v.(civil.Date)
+		if !ok {
+			return unexpectedValue(v, b.Type())
+		}
+		b.Append(arrow.Date32FromTime(d.In(time.UTC)))
+	case *array.TimestampBuilder:
+		var ts time.Time
+		switch x := v.(type) {
+		case time.Time: // TIMESTAMP
+			ts = x
+		case civil.DateTime: // DATETIME
+			ts = x.In(time.UTC)
+		default:
+			return unexpectedValue(v, b.Type())
+		}
+		val, err := arrow.TimestampFromTime(ts, b.Type().(*arrow.TimestampType).Unit)
+		if err != nil {
+			return err
+		}
+		b.Append(val)
+	case *array.Time64Builder:
+		t, ok := v.(civil.Time)
+		if !ok {
+			return unexpectedValue(v, b.Type())
+		}
+		sinceMidnight := time.Duration(t.Hour)*time.Hour + time.Duration(t.Minute)*time.Minute +
+			time.Duration(t.Second)*time.Second + time.Duration(t.Nanosecond)
+		b.Append(arrow.Time64(sinceMidnight / b.Type().(*arrow.Time64Type).Unit.Multiplier()))
+	case *array.Decimal128Builder:
+		n, err := scaledDecimal(v, b.Type().(*arrow.Decimal128Type).Scale)
+		if err != nil {
+			return err
+		}
+		b.Append(decimal128.FromBigInt(n))
+	case *array.Decimal256Builder:
+		n, err := scaledDecimal(v, b.Type().(*arrow.Decimal256Type).Scale)
+		if err != nil {
+			return err
+		}
+		b.Append(decimal256.FromBigInt(n))
+	case *array.MonthDayNanoIntervalBuilder:
+		iv, ok := v.(*bq.IntervalValue)
+		if !ok {
+			return unexpectedValue(v, b.Type())
+		}
+		clock := ((int64(iv.Hours)*60+int64(iv.Minutes))*60+int64(iv.Seconds))*int64(time.Second) + int64(iv.SubSecondNanos)
+		b.Append(arrow.MonthDayNanoInterval{Months: iv.Years*12 + iv.Months, Days: iv.Days, Nanoseconds: clock})
+	case *array.StructBuilder:
+		fields, ok := v.([]bq.Value)
+		if !ok || len(fields) != b.NumField() {
+			return unexpectedValue(v, b.Type())
+		}
+		b.Append(true)
+		for i, f := range fields {
+			if err := appendValue(b.FieldBuilder(i), f); err != nil {
+				return err
+			}
+		}
+	case *array.ListBuilder:
+		elems, ok := v.([]bq.Value)
+		if !ok {
+			return unexpectedValue(v, b.Type())
+		}
+		b.Append(true)
+		for _, e := range elems {
+			if err := appendValue(b.ValueBuilder(), e); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("reading Arrow type %s through tabledata is not supported", b.Type())
+	}
+	return nil
+}
+
+// scaledDecimal returns a NUMERIC or BIGNUMERIC value as an integer scaled by 10^scale.
+func scaledDecimal(v bq.Value, scale int32) (*big.Int, error) {
+	r, ok := v.(*big.Rat)
+	if !ok {
+		return nil, fmt.Errorf("unexpected %T for a decimal column", v)
+	}
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(scale)), nil)))
+	return new(big.Int).Quo(scaled.Num(), scaled.Denom()), nil
+}
+
+func unexpectedValue(v bq.Value, dt arrow.DataType) error {
+	return fmt.Errorf("unexpected %T for Arrow type %s", v, dt)
+}
@@ -0,0 +1,120 @@
+// Package deadletter stores rows Snowflake rejected during COPY, as JSON
+// lines, so they can be inspected and reprocessed.
+package deadletter
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/storage/v1"
+
+	"github.com/TFMV/syncronicity/pkg/snowflake"
+)
+
+// Open returns the sink for a dead-letter location: "gs://bucket/prefix" for
+// Google Cloud Storage, "table:NAME" for a Snowflake table written through
+// client, or otherwise a local file path.
+func Open(ctx context.Context, location string, client *snowflake.Client, opts ...option.ClientOption) (snowflake.DeadLetterSink, error) {
+	switch {
+	case strings.HasPrefix(location, "gs://"):
+		return NewGCSSink(ctx, location, opts...)
+	case strings.HasPrefix(location, "table:"):
+		table := strings.TrimPrefix(location, "table:")
+		if table == "" {
+			return nil, fmt.Errorf("dead-letter location %q names no table", location)
+		}
+		return snowflake.NewTableDeadLetter(client, table), nil
+	default:
+		return &FileSink{Path: location}, nil
+	}
+}
+
+// encode renders rows as JSON lines.
+func encode(rows []snowflake.RejectedRow) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, r := range rows {
+		if err := enc.Encode(r); err != nil {
+			return nil, fmt.Errorf("failed to encode rejected row: %w", err)
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// FileSink appends rejected rows as JSON lines to a local file.
+type FileSink struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// WriteRejected implements snowflake.DeadLetterSink.
+func (s *FileSink) WriteRejected(ctx context.Context, rows []snowflake.RejectedRow) error {
+	data, err := encode(rows)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := os.OpenFile(s.Path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// GCSSink writes each batch of rejected rows to a new JSON lines object under
+// a Cloud Storage prefix.
+type GCSSink struct {
+	objects *storage.ObjectsService
+	bucket  string
+	prefix  string
+
+	mu sync.Mutex
+	n  int
+}
+
+// NewGCSSink creates a sink for a location of the form "gs://bucket/prefix".
+func NewGCSSink(ctx context.Context, location string, opts ...option.ClientOption) (*GCSSink, error) {
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(location, "gs://"), "/")
+	if bucket == "" {
+		return nil, fmt.Errorf("dead-letter location %q names no bucket", location)
+	}
+	svc, err := storage.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Storage client: %w", err)
+	}
+	return &GCSSink{objects: svc.Objects, bucket: bucket, prefix: strings.TrimSuffix(prefix, "/")}, nil
+}
+
+// WriteRejected implements snowflake.DeadLetterSink.
+func (s *GCSSink) WriteRejected(ctx context.Context, rows []snowflake.RejectedRow) error {
+	data, err := encode(rows)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.n++
+	name := fmt.Sprintf("rejected-%s-%05d.jsonl", time.Now().UTC().Format("20060102T150405Z"), s.n)
+	s.mu.Unlock()
+	if s.prefix != "" {
+		name = s.prefix + "/" + name
+	}
+
+	obj := &storage.Object{Name: name, ContentType: "application/x-ndjson"}
+	if _, err := s.objects.Insert(s.bucket, obj).Media(bytes.NewReader(data)).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to write gs://%s/%s: %w", s.bucket, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Package events publishes notifications about finished transfers so other
+// systems can react to newly loaded data.
+package events
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/pubsub/v1"
+)
+
+// TableLoaded is published after a table has been loaded successfully.
+type TableLoaded struct {
+	Table         string    `json:"table"`
+	Rows          int64     `json:"rows"`
+	Files         int       `json:"files"`
+	LoadedAt      time.Time `json:"loaded_at"`
+	CorrelationID string    `json:"correlation_id"` // Matches the run's log lines and TransferReport.
+}
+
+// Publisher delivers events to a message queue.
+type Publisher interface {
+	Publish(ctx context.Context, event TableLoaded) error
+}
+
+// PubSubPublisher publishes events as JSON messages to a Google Cloud Pub/Sub
+// topic. The table name is also set as the "table" message attribute so
+// subscribers can filter on it.
+type PubSubPublisher struct {
+	topics *pubsub.ProjectsTopicsService
+	topic  string
+}
+
+// NewPubSubPublisher creates a publisher for topic, given in the form
+// "projects/<project>/topics/<topic>".
+func NewPubSubPublisher(ctx context.Context, topic string, opts ...option.ClientOption) (*PubSubPublisher, error) {
+	svc, err := pubsub.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Pub/Sub client: %w", err)
+	}
+	return &PubSubPublisher{topics: svc.Projects.Topics, topic: topic}, nil
+}
+
+// Publish implements Publisher.
+func (p *PubSubPublisher) Publish(ctx context.Context, event TableLoaded) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+	req := &pubsub.PublishRequest{Messages: []*pubsub.PubsubMessage{{
+		Data:       base64.StdEncoding.EncodeToString(data),
+		Attributes: map[string]string{"table": event.Table},
+	}}}
+	if _, err := p.topics.Publish(p.topic, req).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to publish to %s: %w", p.topic, err)
+	}
+	return nil
+}
@@ -0,0 +1,364 @@
+// Package gateway serves BigQuery over Arrow Flight SQL, so standard Flight
+// SQL clients such as the JDBC and ODBC Flight SQL drivers can browse
+// BigQuery tables and run queries through syncronicity. Flight SQL catalogs,
+// schemas, and tables map to BigQuery projects, datasets, and tables. The
+// gateway is read-only: only queries are supported.
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/flight"
+	"github.com/apache/arrow-go/v18/arrow/flight/flightsql"
+	"github.com/apache/arrow-go/v18/arrow/flight/flightsql/schema_ref"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/TFMV/syncronicity/pkg/bigquery"
+)
+
+// Server is a Flight SQL server backed by the BigQuery Storage API. Queries
+// run as BigQuery jobs when a client asks for their FlightInfo; the result
+// table is then streamed over DoGet from a read session.
+type Server struct {
+	flightsql.BaseServer
+
+	client  *bigquery.BigQueryReadClient
+	project string // The default catalog, where queries run.
+	opts    *bigquery.BigQueryReaderOptions
+	logger  *zap.Logger
+}
+
+// NewServer creates a gateway over client. Queries run, and catalog
+// requests without a catalog are answered, in project. opts configure the
+// readers streaming results.
+func NewServer(client *bigquery.BigQueryReadClient, project string, opts *bigquery.BigQueryReaderOptions, logger *zap.Logger) (*Server, error) {
+	s := &Server{client: client, project: project, opts: opts, logger: logger}
+	s.Alloc = memory.DefaultAllocator
+	if opts.Allocator != nil {
+		s.Alloc = opts.Allocator
+	}
+	for id, v := range map[flightsql.SqlInfo]interface{}{
+		flightsql.SqlInfoFlightSqlServerName:     "syncronicity",
+		flightsql.SqlInfoFlightSqlServerVersion:  "1.0",
+		flightsql.SqlInfoFlightSqlServerReadOnly: true,
+	} {
+		if err := s.RegisterSqlInfo(id, v); err != nil {
+			return nil, fmt.Errorf("failed to register SQL info: %w", err)
+		}
+	}
+	return s, nil
+}
+
+// ListenAndServe serves Flight SQL on addr until the server fails.
+func (s *Server) ListenAndServe(addr string) error {
+	srv := flight.NewServerWithMiddleware(nil)
+	srv.RegisterFlightService(flightsql.NewFlightServer(s))
+	if err := srv.Init(addr); err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", addr, err)
+	}
+	s.logger.Info("Flight SQL gateway listening", zap.String("addr", srv.Addr().String()))
+	return srv.Serve()
+}
+
+// flightInfo describes a stream whose ticket is the request itself, as for
+// the catalog commands.
+func (s *Server) flightInfo(desc *flight.FlightDescriptor, schema *arrow.Schema) *flight.FlightInfo {
+	return &flight.FlightInfo{
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: desc.Cmd}}},
+		FlightDescriptor: desc,
+		Schema:           flight.SerializeSchema(schema, s.Alloc),
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}
+}
+
+// GetFlightInfoStatement runs the query and returns a ticket for its result
+// table.
+func (s *Server) GetFlightInfoStatement(ctx context.Context, cmd flightsql.StatementQuery, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	dst, schema, err := s.runQuery(ctx, cmd.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	ticket, err := flightsql.CreateStatementQueryTicket([]byte(dst))
+	if err != nil {
+		return nil, err
+	}
+	return &flight.FlightInfo{
+		Endpoint:         []*flight.FlightEndpoint{{Ticket: &flight.Ticket{Ticket: ticket}}},
+		FlightDescriptor: desc,
+		Schema:           flight.SerializeSchema(schema, s.Alloc),
+		TotalRecords:     -1,
+		TotalBytes:       -1,
+	}, nil
+}
+
+// GetSchemaStatement runs the query and returns its result schema. BigQuery
+// caches query results, so a following GetFlightInfoStatement for the same
+// query is cheap.
+func (s *Server) GetSchemaStatement(ctx context.Context, cmd flightsql.StatementQuery, desc *flight.FlightDescriptor) (*flight.SchemaResult, error) {
+	_, schema, err := s.runQuery(ctx, cmd.GetQuery())
+	if err != nil {
+		return nil, err
+	}
+	return &flight.SchemaResult{Schema: flight.SerializeSchema(schema, s.Alloc)}, nil
+}
+
+// runQuery runs sql and returns its result table, as "project.dataset.table",
+// and schema.
+func (s *Server) runQuery(ctx context.Context, sql string) (string, *arrow.Schema, error) {
+	dst, err := s.client.QueryDestination(ctx, s.project, sql)
+	if errors.Is(err, bigquery.ErrNoQueryDestination) {
+		return "", nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err != nil {
+		return "", nil, status.Error(codes.Internal, err.Error())
+	}
+	reader, err := s.client.NewBigQueryReader(ctx, dst.ProjectID, dst.DatasetID, dst.TableID, s.opts)
+	if err != nil {
+		return "", nil, status.Error(codes.Internal, err.Error())
+	}
+	defer reader.Close()
+	schema, err := reader.Schema()
+	if err != nil {
+		return "", nil, status.Error(codes.Internal, err.Error())
+	}
+	return fmt.Sprintf("%s.%s.%s", dst.ProjectID, dst.DatasetID, dst.TableID), schema, nil
+}
+
+// DoGetStatement streams a query's result table.
+func (s *Server) DoGetStatement(ctx context.Context, ticket flightsql.StatementQueryTicket) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	parts := strings.Split(string(ticket.GetStatementHandle()), ".")
+	if len(parts) != 3 {
+		return nil, nil, status.Error(codes.InvalidArgument, "invalid statement handle")
+	}
+	reader, err := s.client.NewBigQueryReader(ctx, parts[0], parts[1], parts[2], s.opts)
+	if err != nil {
+		return nil, nil, status.Error(codes.Internal, err.Error())
+	}
+	schema, err := reader.Schema()
+	if err != nil {
+		reader.Close()
+		return nil, nil, status.Error(codes.Internal, err.Error())
+	}
+
+	ch := make(chan flight.StreamChunk)
+	go func() {
+		defer close(ch)
+		defer reader.Close()
+		for {
+			rec, err := reader.Read()
+			if errors.Is(err, io.EOF) {
+				return
+			}
+			chunk := flight.StreamChunk{Data: rec, Err: err}
+			select {
+			case ch <- chunk:
+			case <-ctx.Done():
+				if rec != nil {
+					rec.Release()
+				}
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return schema, ch, nil
+}
+
+// GetFlightInfoCatalogs describes the catalog list.
+func (s *Server) GetFlightInfoCatalogs(ctx context.Context, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	return s.flightInfo(desc, schema_ref.Catalogs), nil
+}
+
+// DoGetCatalogs lists the gateway's project as the only catalog; BigQuery
+// has no API listing every project a caller can query.
+func (s *Server) DoGetCatalogs(ctx context.Context) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	b := array.NewRecordBuilder(s.Alloc, schema_ref.Catalogs)
+	defer b.Release()
+	b.Field(0).(*array.StringBuilder).Append(s.project)
+	return single(schema_ref.Catalogs, b.NewRecord())
+}
+
+// GetFlightInfoSchemas describes the dataset list.
+func (s *Server) GetFlightInfoSchemas(ctx context.Context, cmd flightsql.GetDBSchemas, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	return s.flightInfo(desc, schema_ref.DBSchemas), nil
+}
+
+// DoGetDBSchemas lists the datasets of the requested catalog.
+func (s *Server) DoGetDBSchemas(ctx context.Context, cmd flightsql.GetDBSchemas) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	project := s.catalog(cmd.GetCatalog())
+	datasets, err := s.datasets(ctx, project, cmd.GetDBSchemaFilterPattern())
+	if err != nil {
+		return nil, nil, err
+	}
+	b := array.NewRecordBuilder(s.Alloc, schema_ref.DBSchemas)
+	defer b.Release()
+	for _, ds := range datasets {
+		b.Field(0).(*array.StringBuilder).Append(project)
+		b.Field(1).(*array.StringBuilder).Append(ds)
+	}
+	return single(schema_ref.DBSchemas, b.NewRecord())
+}
+
+// GetFlightInfoTables describes the table list.
+func (s *Server) GetFlightInfoTables(ctx context.Context, cmd flightsql.GetTables, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	if cmd.GetIncludeSchema() {
+		return s.flightInfo(desc, schema_ref.TablesWithIncludedSchema), nil
+	}
+	return s.flightInfo(desc, schema_ref.Tables), nil
+}
+
+// DoGetTables lists the tables of the requested catalog's matching datasets.
+// Every table is reported as a TABLE. With IncludeSchema, each table's Arrow
+// schema is taken from a read session, which costs one CreateReadSession
+// call per table.
+func (s *Server) DoGetTables(ctx context.Context, cmd flightsql.GetTables) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	schema := schema_ref.Tables
+	if cmd.GetIncludeSchema() {
+		schema = schema_ref.TablesWithIncludedSchema
+	}
+	b := array.NewRecordBuilder(s.Alloc, schema)
+	defer b.Release()
+	if types := cmd.GetTableTypes(); len(types) > 0 && !containsFold(types, "TABLE") {
+		return single(schema, b.NewRecord())
+	}
+
+	project := s.catalog(cmd.GetCatalog())
+	datasets, err := s.datasets(ctx, project, cmd.GetDBSchemaFilterPattern())
+	if err != nil {
+		return nil, nil, err
+	}
+	match, err := likeMatcher(cmd.GetTableNameFilterPattern())
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, ds := range datasets {
+		tables, err := s.client.ListTables(ctx, project, ds)
+		if err != nil {
+			return nil, nil, status.Error(codes.Internal, err.Error())
+		}
+		for _, t := range tables {
+			if !match(t) {
+				continue
+			}
+			b.Field(0).(*array.StringBuilder).Append(project)
+			b.Field(1).(*array.StringBuilder).Append(ds)
+			b.Field(2).(*array.StringBuilder).Append(t)
+			b.Field(3).(*array.StringBuilder).Append("TABLE")
+			if cmd.GetIncludeSchema() {
+				ts, err := s.tableSchema(ctx, project, ds, t)
+				if err != nil {
+					return nil, nil, status.Error(codes.Internal, err.Error())
+				}
+				b.Field(4).(*array.BinaryBuilder).Append(flight.SerializeSchema(ts, s.Alloc))
+			}
+		}
+	}
+	return single(schema, b.NewRecord())
+}
+
+// GetFlightInfoTableTypes describes the table type list.
+func (s *Server) GetFlightInfoTableTypes(ctx context.Context, desc *flight.FlightDescriptor) (*flight.FlightInfo, error) {
+	return s.flightInfo(desc, schema_ref.TableTypes), nil
+}
+
+// DoGetTableTypes lists the single table type the gateway reports.
+func (s *Server) DoGetTableTypes(ctx context.Context) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	b := array.NewRecordBuilder(s.Alloc, schema_ref.TableTypes)
+	defer b.Release()
+	b.Field(0).(*array.StringBuilder).Append("TABLE")
+	return single(schema_ref.TableTypes, b.NewRecord())
+}
+
+// tableSchema returns a table's Arrow schema from a read session.
+func (s *Server) tableSchema(ctx context.Context, project, dataset, table string) (*arrow.Schema, error) {
+	reader, err := s.client.NewBigQueryReader(ctx, project, dataset, table, s.opts)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return reader.Schema()
+}
+
+// catalog maps a requested catalog to a project, defaulting to the gateway's.
+func (s *Server) catalog(catalog *string) string {
+	if catalog == nil || *catalog == "" {
+		return s.project
+	}
+	return *catalog
+}
+
+// datasets lists the datasets of project matching a LIKE pattern.
+func (s *Server) datasets(ctx context.Context, project string, pattern *string) ([]string, error) {
+	match, err := likeMatcher(pattern)
+	if err != nil {
+		return nil, err
+	}
+	all, err := s.client.ListDatasets(ctx, project)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	var datasets []string
+	for _, ds := range all {
+		if match(ds) {
+			datasets = append(datasets, ds)
+		}
+	}
+	return datasets, nil
+}
+
+// likeMatcher compiles a Flight SQL filter pattern, which uses SQL LIKE
+// syntax ("%" for any run of characters, "_" for one). A nil pattern matches
+// everything.
+func likeMatcher(pattern *string) (func(string) bool, error) {
+	if pattern == nil {
+		return func(string) bool { return true }, nil
+	}
+	var re strings.Builder
+	re.WriteString("^")
+	for _, r := range *pattern {
+		switch r {
+		case '%':
+			re.WriteString(".*")
+		case '_':
+			re.WriteString(".")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	re.WriteString("$")
+	compiled, err := regexp.Compile(re.String())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid filter pattern %q: %v", *pattern, err)
+	}
+	return compiled.MatchString, nil
+}
+
+func containsFold(values []string, want string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, want) {
+			return true
+		}
+	}
+	return false
+}
+
+// single returns a stream of one record.
+func single(schema *arrow.Schema, rec arrow.Record) (*arrow.Schema, <-chan flight.StreamChunk, error) {
+	ch := make(chan flight.StreamChunk, 1)
+	ch <- flight.StreamChunk{Data: rec}
+	close(ch)
+	return schema, ch, nil
+}
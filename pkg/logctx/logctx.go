@@ -0,0 +1,36 @@
+// Package logctx carries a per-transfer correlation ID through a context so log
+// lines from every stage of a transfer can be tied back to it.
+package logctx
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+type correlationKey struct{}
+
+// NewCorrelationID returns a fresh, random correlation ID.
+func NewCorrelationID() string {
+	return uuid.NewString()
+}
+
+// WithCorrelationID returns a copy of ctx carrying id.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationKey{}, id)
+}
+
+// CorrelationID returns the ID stored in ctx, or "" if there is none.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationKey{}).(string)
+	return id
+}
+
+// Logger returns logger annotated with the correlation ID from ctx, if any.
+func Logger(ctx context.Context, logger *zap.Logger) *zap.Logger {
+	if id := CorrelationID(ctx); id != "" {
+		return logger.With(zap.String("correlation_id", id))
+	}
+	return logger
+}
@@ -0,0 +1,114 @@
+// Package metrics lets callers feed transfer measurements into a metrics
+// system such as Prometheus. The BigQuery reader and the Snowflake client call
+// a Metrics implementation as they work, in addition to logging.
+package metrics
+
+import (
+	"sync"
+	"time"
+)
+
+// Metrics receives measurements as a transfer progresses. Implementations
+// must be safe for concurrent use: a reader of several streams and
+// concurrent uploads call them from many goroutines.
+type Metrics interface {
+	// RecordRowsRead is called with the rows of each batch read from the
+	// source, and RecordBytesRead with the batch's serialized size.
+	RecordRowsRead(rows int64)
+	RecordBytesRead(bytes int64)
+	// RecordBytesUploaded is called with the size of each Parquet file
+	// staged.
+	RecordBytesUploaded(bytes int64)
+	// RecordCopyDuration is called with how long each COPY took, and
+	// RecordRowsLoaded with the rows it loaded.
+	RecordCopyDuration(d time.Duration)
+	RecordRowsLoaded(rows int64)
+}
+
+// Or returns m, or Nop if m is nil.
+func Or(m Metrics) Metrics {
+	if m == nil {
+		return Nop{}
+	}
+	return m
+}
+
+// Nop discards every measurement.
+type Nop struct{}
+
+// RecordRowsRead implements Metrics.
+func (Nop) RecordRowsRead(int64) {}
+
+// RecordBytesRead implements Metrics.
+func (Nop) RecordBytesRead(int64) {}
+
+// RecordBytesUploaded implements Metrics.
+func (Nop) RecordBytesUploaded(int64) {}
+
+// RecordCopyDuration implements Metrics.
+func (Nop) RecordCopyDuration(time.Duration) {}
+
+// RecordRowsLoaded implements Metrics.
+func (Nop) RecordRowsLoaded(int64) {}
+
+// Memory accumulates measurements in memory, for tests and for reporting
+// totals at the end of a run. The zero value is ready to use.
+type Memory struct {
+	mu   sync.Mutex
+	snap Snapshot
+}
+
+// Snapshot holds the totals recorded by a Memory.
+type Snapshot struct {
+	RowsRead      int64
+	BytesRead     int64
+	BytesUploaded int64
+	FilesUploaded int64
+	RowsLoaded    int64
+	Copies        int64
+	CopyDuration  time.Duration // Summed over every COPY.
+}
+
+// Snapshot returns the totals recorded so far.
+func (m *Memory) Snapshot() Snapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snap
+}
+
+// RecordRowsRead implements Metrics.
+func (m *Memory) RecordRowsRead(rows int64) {
+	m.mu.Lock()
+	m.snap.RowsRead += rows
+	m.mu.Unlock()
+}
+
+// RecordBytesRead implements Metrics.
+func (m *Memory) RecordBytesRead(bytes int64) {
+	m.mu.Lock()
+	m.snap.BytesRead += bytes
+	m.mu.Unlock()
+}
+
+// RecordBytesUploaded implements Metrics.
+func (m *Memory) RecordBytesUploaded(bytes int64) {
+	m.mu.Lock()
+	m.snap.BytesUploaded += bytes
+	m.snap.FilesUploaded++
+	m.mu.Unlock()
+}
+
+// RecordCopyDuration implements Metrics.
+func (m *Memory) RecordCopyDuration(d time.Duration) {
+	m.mu.Lock()
+	m.snap.CopyDuration += d
+	m.snap.Copies++
+	m.mu.Unlock()
+}
+
+// RecordRowsLoaded implements Metrics.
+func (m *Memory) RecordRowsLoaded(rows int64) {
+	m.mu.Lock()
+	m.snap.RowsLoaded += rows
+	m.mu.Unlock()
+}
@@ -0,0 +1,96 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// AdaptiveLimiter bounds the number of concurrent Snowflake operations
+// (uploads and COPYs) across the transfers sharing it, adapting the bound to
+// warehouse contention. COPY latency above the target means queries are
+// queuing, so the limit is halved; after a full window of COPYs (one per
+// allowed operation) at or below the target, it grows by one. The limit stays
+// within [min, max] and starts at max.
+type AdaptiveLimiter struct {
+	min, max int
+	target   time.Duration
+
+	mu        sync.Mutex
+	limit     int
+	inFlight  int
+	successes int
+	changed   chan struct{} // Closed and replaced when a slot may have opened.
+}
+
+// NewAdaptiveLimiter creates a limiter allowing between minLimit and maxLimit
+// concurrent operations that backs off when COPY latency exceeds target.
+func NewAdaptiveLimiter(minLimit, maxLimit int, target time.Duration) *AdaptiveLimiter {
+	lo := max(minLimit, 1)
+	hi := max(maxLimit, lo)
+	return &AdaptiveLimiter{
+		min:     lo,
+		max:     hi,
+		target:  target,
+		limit:   hi,
+		changed: make(chan struct{}),
+	}
+}
+
+// Acquire blocks until an operation may start or ctx is done.
+func (l *AdaptiveLimiter) Acquire(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		if l.inFlight < l.limit {
+			l.inFlight++
+			l.mu.Unlock()
+			return nil
+		}
+		ch := l.changed
+		l.mu.Unlock()
+
+		select {
+		case <-ch:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Release ends an operation started with Acquire.
+func (l *AdaptiveLimiter) Release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.inFlight--
+	l.wake()
+}
+
+// Observe records the latency of a completed COPY and adjusts the limit.
+func (l *AdaptiveLimiter) Observe(latency time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if latency > l.target {
+		l.limit = max(l.min, l.limit/2)
+		l.successes = 0
+		return
+	}
+	l.successes++
+	if l.successes >= l.limit && l.limit < l.max {
+		l.limit++
+		l.successes = 0
+		l.wake()
+	}
+}
+
+// Limit returns the current concurrency limit.
+func (l *AdaptiveLimiter) Limit() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.limit
+}
+
+// wake signals waiters that a slot may have opened. l.mu must be held.
+func (l *AdaptiveLimiter) wake() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
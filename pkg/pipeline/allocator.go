@@ -0,0 +1,124 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// ErrMemoryLimit is reported by BoundedAllocator.Err once an allocation
+// exceeded the cap.
+var ErrMemoryLimit = errors.New("arrow memory limit exceeded")
+
+// BoundedAllocator caps the bytes outstanding across everything sharing it,
+// e.g. every reader and writer of concurrent transfers. When an allocation
+// would exceed the cap it either blocks until enough memory is freed
+// elsewhere, applying backpressure to the allocating goroutine, or fails.
+// Blocking only helps when other goroutines free memory: a single transfer
+// that needs more than the cap by itself waits forever, so size the cap for
+// the largest record times the number of concurrent transfers. An allocation
+// larger than the cap always fails.
+//
+// memory.Allocator has no error return, and allocations happen on reader,
+// decoder, and driver goroutines that can't recover a panic, so a failed
+// allocation is still satisfied and the failure is recorded instead: Err
+// returns it from then on. A Transfer whose Options.Allocator is a
+// BoundedAllocator checks Err after every record and aborts with it.
+type BoundedAllocator struct {
+	parent memory.Allocator
+	limit  int64
+	block  bool
+
+	mu   sync.Mutex
+	cond *sync.Cond
+	used int64
+	err  error // The first failed allocation.
+}
+
+// NewBoundedAllocator wraps parent (memory.DefaultAllocator if nil) with a cap
+// of limit bytes. block selects blocking instead of failing (recording
+// ErrMemoryLimit) at the cap.
+func NewBoundedAllocator(parent memory.Allocator, limit int64, block bool) *BoundedAllocator {
+	if parent == nil {
+		parent = memory.DefaultAllocator
+	}
+	a := &BoundedAllocator{parent: parent, limit: limit, block: block}
+	a.cond = sync.NewCond(&a.mu)
+	return a
+}
+
+// Allocate implements memory.Allocator.
+func (a *BoundedAllocator) Allocate(size int) []byte {
+	a.reserve(int64(size))
+	return a.parent.Allocate(size)
+}
+
+// Reallocate implements memory.Allocator.
+func (a *BoundedAllocator) Reallocate(size int, b []byte) []byte {
+	delta := int64(size - len(b))
+	if delta > 0 {
+		a.reserve(delta)
+	}
+	out := a.parent.Reallocate(size, b)
+	if delta < 0 {
+		a.unreserve(-delta)
+	}
+	return out
+}
+
+// Free implements memory.Allocator.
+func (a *BoundedAllocator) Free(b []byte) {
+	a.parent.Free(b)
+	a.unreserve(int64(len(b)))
+}
+
+// Err returns an error wrapping ErrMemoryLimit once an allocation has
+// exceeded the cap, or nil.
+func (a *BoundedAllocator) Err() error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.err
+}
+
+// Allocated returns the bytes currently outstanding.
+func (a *BoundedAllocator) Allocated() int64 {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.used
+}
+
+func (a *BoundedAllocator) reserve(n int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	switch {
+	case n > a.limit:
+		a.fail(fmt.Errorf("%w: allocation of %d bytes is larger than the %d byte limit", ErrMemoryLimit, n, a.limit))
+	case a.used+n > a.limit && (!a.block || a.err != nil):
+		// Once failed, the run is aborting; waiting could hang it.
+		a.fail(fmt.Errorf("%w: %d bytes in use, %d requested, limit %d", ErrMemoryLimit, a.used, n, a.limit))
+	default:
+		for a.used+n > a.limit && a.err == nil {
+			a.cond.Wait()
+		}
+	}
+	a.used += n
+}
+
+// fail records err unless an earlier failure was recorded, and wakes the
+// blocked allocations so they don't wait for an aborting run. a.mu must be
+// held.
+func (a *BoundedAllocator) fail(err error) {
+	if a.err == nil {
+		a.err = err
+	}
+	a.cond.Broadcast()
+}
+
+func (a *BoundedAllocator) unreserve(n int64) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.used -= n
+	a.cond.Broadcast()
+}
@@ -0,0 +1,83 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/TFMV/syncronicity/pkg/snowflake/snowflaketest"
+)
+
+func TestBoundedAllocatorRecordsFailures(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		block bool
+		sizes []int
+	}{
+		{"over the cap without blocking", false, []int{48, 48}},
+		{"larger than the cap without blocking", false, []int{128}},
+		{"larger than the cap while blocking", true, []int{128}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			a := NewBoundedAllocator(nil, 64, tc.block)
+			var bufs [][]byte
+			for _, n := range tc.sizes {
+				b := a.Allocate(n)
+				if len(b) != n {
+					t.Fatalf("Allocate(%d) returned %d bytes", n, len(b))
+				}
+				bufs = append(bufs, b)
+			}
+			if err := a.Err(); !errors.Is(err, ErrMemoryLimit) {
+				t.Fatalf("Err() = %v, want ErrMemoryLimit", err)
+			}
+			for _, b := range bufs {
+				a.Free(b)
+			}
+			if got := a.Allocated(); got != 0 {
+				t.Errorf("Allocated() = %d after freeing everything", got)
+			}
+		})
+	}
+}
+
+func TestBoundedAllocatorBlocksUntilFreed(t *testing.T) {
+	a := NewBoundedAllocator(nil, 64, true)
+	first := a.Allocate(48)
+	done := make(chan []byte)
+	go func() { done <- a.Allocate(48) }()
+	select {
+	case <-done:
+		t.Fatal("allocation over the cap didn't block")
+	case <-time.After(50 * time.Millisecond):
+	}
+	a.Free(first)
+	select {
+	case b := <-done:
+		a.Free(b)
+	case <-time.After(time.Second):
+		t.Fatal("allocation still blocked after memory was freed")
+	}
+	if err := a.Err(); err != nil {
+		t.Errorf("Err() = %v, want nil", err)
+	}
+}
+
+func TestTransferAbortsOnMemoryLimit(t *testing.T) {
+	a := NewBoundedAllocator(nil, 64, false)
+	a.Free(a.Allocate(128)) // As a reader goroutine would.
+
+	dst := snowflaketest.NewFakeClient()
+	defer dst.Release()
+	opts := Options{Table: "t", DataDir: t.TempDir(), StagePath: "@stage", Allocator: a}
+	_, err := NewTransfer(newIDSource(t, 0, 3), dst, zap.NewNop(), opts).Run(context.Background())
+	if !errors.Is(err, ErrMemoryLimit) {
+		t.Fatalf("Run() error = %v, want ErrMemoryLimit", err)
+	}
+	if got := dst.LoadedRows(); got != 0 {
+		t.Errorf("loaded %d rows after the memory limit was exceeded", got)
+	}
+}
@@ -0,0 +1,35 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/TFMV/syncronicity/pkg/snowflake"
+	"github.com/TFMV/syncronicity/pkg/snowflake/snowflaketest"
+)
+
+func TestTransferFailsWhenFilesStillFailAfterLastAttempt(t *testing.T) {
+	dst := snowflaketest.NewFakeClient()
+	defer dst.Release()
+	dst.FailFiles = []string{"arrow_record-00002.parquet"}
+
+	opts := Options{Table: "t", DataDir: t.TempDir(), StagePath: "@stage", CopyAttempts: 2}
+	_, err := NewTransfer(newIDSource(t, 0, 3, 4, 5), dst, zap.NewNop(), opts).Run(context.Background())
+
+	var loadErr *snowflake.ErrLoadErrors
+	if !errors.As(err, &loadErr) {
+		t.Fatalf("err = %v, want *snowflake.ErrLoadErrors", err)
+	}
+	if len(loadErr.Files) != 1 || loadErr.Files[0].File != dst.FailFiles[0] {
+		t.Errorf("failed files = %+v, want only %s", loadErr.Files, dst.FailFiles[0])
+	}
+	if got := dst.LoadedRows(); got != 8 {
+		t.Errorf("loaded %d rows, want the 8 from the files that didn't fail", got)
+	}
+	if got := dst.Copies(); got != 2 {
+		t.Errorf("COPY ran %d times, want once per attempt", got)
+	}
+}
@@ -0,0 +1,105 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+)
+
+// DedupStrategy selects where rows with duplicate keys are removed.
+type DedupStrategy int
+
+const (
+	// DedupClient drops duplicate rows in the transfer before they are staged,
+	// keeping the first row seen for each key. Every distinct key is held in
+	// memory for the whole transfer: roughly the key values' string length plus
+	// about 50 bytes of map overhead per distinct row, so it suits small to
+	// medium tables or narrow keys.
+	DedupClient DedupStrategy = iota
+	// DedupServer loads every row and then rewrites the target table in
+	// Snowflake keeping one row per key (INSERT OVERWRITE ... QUALIFY
+	// ROW_NUMBER() ...). It uses no client memory and also removes duplicates
+	// already in the table, but rewrites the whole table after each load.
+	DedupServer
+)
+
+// ParseDedupStrategy parses a strategy name: "" or "client", or "server".
+func ParseDedupStrategy(s string) (DedupStrategy, error) {
+	switch s {
+	case "", "client":
+		return DedupClient, nil
+	case "server":
+		return DedupServer, nil
+	default:
+		return DedupClient, fmt.Errorf("unknown dedup strategy %q (want client or server)", s)
+	}
+}
+
+// Deduper removes rows whose key columns repeat a key seen earlier, across
+// every record it filters. It is not safe for concurrent use.
+type Deduper struct {
+	keys []string
+	seen map[string]struct{}
+}
+
+// NewDeduper creates a Deduper keyed on the named columns.
+func NewDeduper(keys []string) *Deduper {
+	return &Deduper{keys: keys, seen: make(map[string]struct{})}
+}
+
+// Filter returns rec without rows whose key was already seen. The caller must
+// release the returned record; the input record is not released.
+func (d *Deduper) Filter(ctx context.Context, rec arrow.Record) (arrow.Record, error) {
+	cols := make([]arrow.Array, len(d.keys))
+	for i, key := range d.keys {
+		idx := rec.Schema().FieldIndices(key)
+		if len(idx) == 0 {
+			return nil, fmt.Errorf("dedup key %q is not a column", key)
+		}
+		cols[i] = rec.Column(idx[0])
+	}
+
+	mask := array.NewBooleanBuilder(compute.GetAllocator(ctx))
+	defer mask.Release()
+	dups := 0
+	var sb strings.Builder
+	for row := 0; row < int(rec.NumRows()); row++ {
+		// Length-prefix each value so distinct keys can't encode alike.
+		sb.Reset()
+		for _, col := range cols {
+			if col.IsNull(row) {
+				sb.WriteString("-;")
+				continue
+			}
+			v := col.ValueStr(row)
+			sb.WriteString(strconv.Itoa(len(v)))
+			sb.WriteByte(':')
+			sb.WriteString(v)
+		}
+		key := sb.String()
+		if _, ok := d.seen[key]; ok {
+			mask.Append(false)
+			dups++
+			continue
+		}
+		d.seen[key] = struct{}{}
+		mask.Append(true)
+	}
+
+	if dups == 0 {
+		rec.Retain()
+		return rec, nil
+	}
+	keep := mask.NewArray()
+	defer keep.Release()
+	out, err := compute.FilterRecordBatch(ctx, rec, keep, compute.DefaultFilterOptions())
+	if err != nil {
+		return nil, fmt.Errorf("failed to drop duplicate rows: %w", err)
+	}
+	return out, nil
+}
@@ -0,0 +1,99 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+)
+
+// ValidateExplodeColumn checks that column exists in schema and is a repeated
+// RECORD, i.e. an Arrow list of structs.
+func ValidateExplodeColumn(schema *arrow.Schema, column string) error {
+	idx := schema.FieldIndices(column)
+	if len(idx) == 0 {
+		return fmt.Errorf("explode column %q is not a column", column)
+	}
+	list, ok := schema.Field(idx[0]).Type.(*arrow.ListType)
+	if !ok {
+		return fmt.Errorf("explode column %q is %s, want a list of structs", column, schema.Field(idx[0]).Type)
+	}
+	if _, ok := list.Elem().(*arrow.StructType); !ok {
+		return fmt.Errorf("explode column %q is a list of %s, want a list of structs", column, list.Elem())
+	}
+	return nil
+}
+
+// ExplodeRecord unnests a list-of-struct column into one row per element, like
+// BigQuery's CROSS JOIN UNNEST: the other columns are repeated for each element
+// and the struct's fields replace the list column, named "<column>_<field>".
+// Rows whose list is null or empty, and null elements, produce no rows. The
+// caller must release the returned record; the input record is not released.
+func ExplodeRecord(ctx context.Context, rec arrow.Record, column string) (arrow.Record, error) {
+	if err := ValidateExplodeColumn(rec.Schema(), column); err != nil {
+		return nil, err
+	}
+	listIdx := rec.Schema().FieldIndices(column)[0]
+	list := rec.Column(listIdx).(*array.List)
+	elems := list.ListValues().(*array.Struct)
+
+	// Pair every element with the row it came from.
+	parentB := array.NewInt64Builder(compute.GetAllocator(ctx))
+	defer parentB.Release()
+	childB := array.NewInt64Builder(compute.GetAllocator(ctx))
+	defer childB.Release()
+	for row := 0; row < list.Len(); row++ {
+		if list.IsNull(row) {
+			continue
+		}
+		start, end := list.ValueOffsets(row)
+		for e := start; e < end; e++ {
+			if elems.IsNull(int(e)) {
+				continue
+			}
+			parentB.Append(int64(row))
+			childB.Append(e)
+		}
+	}
+	parents := parentB.NewArray()
+	defer parents.Release()
+	children := childB.NewArray()
+	defer children.Release()
+
+	var fields []arrow.Field
+	var cols []arrow.Array
+	defer func() { releaseAll(cols) }()
+	for i, f := range rec.Schema().Fields() {
+		if i != listIdx {
+			col, err := compute.TakeArray(ctx, rec.Column(i), parents)
+			if err != nil {
+				return nil, fmt.Errorf("failed to repeat column %q: %w", f.Name, err)
+			}
+			fields = append(fields, f)
+			cols = append(cols, col)
+			continue
+		}
+		st := elems.DataType().(*arrow.StructType)
+		for j, sf := range st.Fields() {
+			col, err := compute.TakeArray(ctx, elems.Field(j), children)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unnest %s.%s: %w", column, sf.Name, err)
+			}
+			sf.Name = column + "_" + sf.Name
+			fields = append(fields, sf)
+			cols = append(cols, col)
+		}
+	}
+
+	md := rec.Schema().Metadata()
+	return array.NewRecord(arrow.NewSchema(fields, &md), cols, int64(parents.Len())), nil
+}
+
+// releaseAll releases every array in the slice.
+func releaseAll(arrs []arrow.Array) {
+	for _, a := range arrs {
+		a.Release()
+	}
+}
@@ -0,0 +1,16 @@
+package pipeline
+
+// FileHook observes the lifecycle of each Parquet file a Transfer produces,
+// e.g. to register data files in an external catalog in lock-step with the
+// transfer. Hooks are called synchronously from the transfer loop, so they
+// must not block: hand slow work off to another goroutine.
+type FileHook interface {
+	// OnWritten is called once a record has been written to the local file path.
+	OnWritten(path string, rows, bytes int64)
+	// OnUploaded is called once the local file has been PUT to the stage;
+	// remote is the stage path followed by the file name.
+	OnUploaded(local, remote string)
+	// OnLoaded is called for each file a COPY loaded; remote is the file as
+	// reported by COPY.
+	OnLoaded(remote string, rowsLoaded int64)
+}
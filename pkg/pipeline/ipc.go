@@ -0,0 +1,44 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+)
+
+// WriteIPCStream copies every record from src to w as an Arrow IPC stream: the
+// schema is written once, followed by one message per record. It returns the
+// number of rows written.
+func WriteIPCStream(w io.Writer, src RecordSource) (int64, error) {
+	var writer *ipc.Writer
+	var rows int64
+	for {
+		rec, err := src.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return rows, fmt.Errorf("error reading Arrow record: %w", err)
+		}
+		if writer == nil {
+			writer = ipc.NewWriter(w, ipc.WithSchema(rec.Schema()))
+		}
+		n := rec.NumRows()
+		err = writer.Write(rec)
+		rec.Release()
+		if err != nil {
+			return rows, fmt.Errorf("failed to write Arrow IPC message: %w", err)
+		}
+		rows += n
+	}
+	if writer == nil {
+		// An empty source has no schema to write.
+		return 0, nil
+	}
+	if err := writer.Close(); err != nil {
+		return rows, fmt.Errorf("failed to close Arrow IPC stream: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,162 @@
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/api/option"
+	"google.golang.org/api/storage/v1"
+)
+
+// Manifest records what a transfer moved, for lineage and to reproduce it
+// later: the source as read, the schema loaded, every file staged with its
+// checksum, and the Snowflake queries that loaded them.
+type Manifest struct {
+	Table         string           `json:"table"`
+	CorrelationID string           `json:"correlation_id"`
+	State         string           `json:"state"`
+	StartedAt     time.Time        `json:"started_at"`
+	FinishedAt    time.Time        `json:"finished_at"`
+	Source        ManifestSource   `json:"source"`
+	Schema        []PreflightField `json:"schema"`
+	RowsRead      int64            `json:"rows_read"`
+	RowsLoaded    int64            `json:"rows_loaded"`
+	Files         []FileReport     `json:"files"`
+	QueryIDs      []string         `json:"query_ids"`
+	Errors        []string         `json:"errors,omitempty"`
+}
+
+// ManifestSource describes how the source was read.
+type ManifestSource struct {
+	// Table is the source table or query.
+	Table string `json:"table"`
+	// SnapshotTime is when the source was read as of. If nil, it is taken
+	// from the source when it has a SnapshotTime method, as
+	// *bigquery.BigQueryReader does.
+	SnapshotTime    *time.Time `json:"snapshot_time,omitempty"`
+	RowRestriction  string     `json:"row_restriction,omitempty"`
+	SelectedColumns []string   `json:"selected_columns,omitempty"`
+}
+
+// ManifestWriter stores a transfer's manifest.
+type ManifestWriter interface {
+	WriteManifest(ctx context.Context, m Manifest) error
+}
+
+// snapshotSource is implemented by sources that know when they were read as
+// of, such as *bigquery.BigQueryReader.
+type snapshotSource interface {
+	SnapshotTime() time.Time
+}
+
+// OpenManifest returns the writer for a manifest location: "gs://bucket/path"
+// for a Cloud Storage object, or otherwise a local file path.
+func OpenManifest(ctx context.Context, location string, opts ...option.ClientOption) (ManifestWriter, error) {
+	if !strings.HasPrefix(location, "gs://") {
+		return FileManifest(location), nil
+	}
+	bucket, name, _ := strings.Cut(strings.TrimPrefix(location, "gs://"), "/")
+	if bucket == "" || name == "" {
+		return nil, fmt.Errorf("manifest location %q names no bucket or object", location)
+	}
+	svc, err := storage.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloud Storage client: %w", err)
+	}
+	return &gcsManifest{objects: svc.Objects, bucket: bucket, name: name}, nil
+}
+
+// FileManifest writes the manifest as indented JSON to a local file,
+// replacing it if it exists.
+type FileManifest string
+
+// WriteManifest implements ManifestWriter.
+func (f FileManifest) WriteManifest(ctx context.Context, m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	return os.WriteFile(string(f), append(data, '\n'), 0644)
+}
+
+// gcsManifest writes the manifest to a Cloud Storage object.
+type gcsManifest struct {
+	objects      *storage.ObjectsService
+	bucket, name string
+}
+
+// WriteManifest implements ManifestWriter.
+func (g *gcsManifest) WriteManifest(ctx context.Context, m Manifest) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	obj := &storage.Object{Name: g.name, ContentType: "application/json"}
+	if _, err := g.objects.Insert(g.bucket, obj).Media(bytes.NewReader(data)).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("failed to write gs://%s/%s: %w", g.bucket, g.name, err)
+	}
+	return nil
+}
+
+// manifest builds the transfer's manifest from its report.
+func (t *Transfer) manifest() Manifest {
+	report := t.Report()
+	m := Manifest{
+		Table:         report.Table,
+		CorrelationID: report.CorrelationID,
+		State:         report.State,
+		StartedAt:     report.StartedAt,
+		FinishedAt:    report.FinishedAt,
+		Source:        t.opts.Source,
+		RowsRead:      report.RowsRead,
+		RowsLoaded:    report.RowsLoaded,
+		Files:         report.Files,
+		QueryIDs:      report.QueryIDs,
+		Errors:        report.Errors,
+	}
+	if m.Source.SnapshotTime == nil {
+		if s, ok := t.src.(snapshotSource); ok {
+			if at := s.SnapshotTime(); !at.IsZero() {
+				m.Source.SnapshotTime = &at
+			}
+		}
+	}
+	if schema := t.progress.firstSchema(); schema != nil {
+		m.Schema = fieldsOf(schema)
+	}
+	return m
+}
+
+// writeManifest writes the manifest, if one is configured. The data is loaded
+// whether or not this succeeds.
+func (t *Transfer) writeManifest(ctx context.Context) error {
+	if t.opts.Manifest == nil {
+		return nil
+	}
+	if err := t.opts.Manifest.WriteManifest(ctx, t.manifest()); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return nil
+}
+
+// fileChecksum returns the hex SHA-256 of the file at path.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to checksum %s: %w", path, err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
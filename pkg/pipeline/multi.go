@@ -0,0 +1,220 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"go.uber.org/zap"
+
+	"github.com/TFMV/syncronicity/pkg/logctx"
+	"github.com/TFMV/syncronicity/pkg/snowflake"
+)
+
+// OpenFunc opens the record source for one source table.
+type OpenFunc func(ctx context.Context, table string) (RecordSource, error)
+
+// TargetFunc returns the loader of the Snowflake table one source table is
+// loaded into, such as a snowflake.Client's ForTable.
+type TargetFunc func(table string) snowflake.Loader
+
+// MultiTransfer runs one Transfer per table, one table at a time, stopping at
+// the first failure. Each table is loaded into its own target, and gets its
+// own data subdirectory and stage path so their Parquet files don't collide.
+type MultiTransfer struct {
+	// BatchDDL moves DDL out of the per-table loads into a phase that runs
+	// first: every table's source is opened and its first record read, the
+	// CREATE TABLE (when Options.CreateTable is set) and ALTER TABLE statements
+	// needed are computed from those schemas, and all of them run as one batch
+	// before any data is loaded. In PrintSQLOnly mode this prints all DDL up
+	// front. Each source's first record is held in memory until its table's
+	// turn.
+	BatchDDL bool
+
+	tables []string
+	open   OpenFunc
+	target TargetFunc
+	logger *zap.Logger
+	opts   Options
+
+	mu      sync.Mutex
+	current *Transfer
+	paused  bool
+	sources map[string]RecordSource // Opened by the DDL phase.
+}
+
+// NewMultiTransfer creates a transfer of each of tables into the target
+// returns for it. opts applies to every table; its Table field is used as a
+// prefix (e.g. "project.dataset") for each table's name in logs and reports.
+func NewMultiTransfer(tables []string, open OpenFunc, target TargetFunc, logger *zap.Logger, opts Options) *MultiTransfer {
+	return &MultiTransfer{
+		tables: tables,
+		open:   open,
+		target: target,
+		logger: logger,
+		opts:   opts,
+	}
+}
+
+// Run transfers every table in order and returns the reports of the tables
+// attempted so far.
+func (m *MultiTransfer) Run(ctx context.Context) ([]*TransferReport, error) {
+	defer m.closeSources()
+	if m.BatchDDL {
+		if err := m.runDDL(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var reports []*TransferReport
+	for _, table := range m.tables {
+		report, err := m.runTable(ctx, table)
+		if report != nil {
+			reports = append(reports, report)
+		}
+		if err != nil {
+			return reports, fmt.Errorf("transfer of %s failed: %w", table, err)
+		}
+	}
+	return reports, nil
+}
+
+// Preflight opens each table in turn and reports what Run would do for it,
+// without writing, staging, or loading anything. Each table's report is
+// gathered with the options Run would transfer it with.
+func (m *MultiTransfer) Preflight(ctx context.Context) ([]PreflightReport, error) {
+	var reports []PreflightReport
+	for _, table := range m.tables {
+		src, err := m.open(ctx, table)
+		if err != nil {
+			return reports, fmt.Errorf("failed to open %s: %w", table, err)
+		}
+		report, err := NewTransfer(src, m.target(table), m.logger, m.tableOptions(table)).Preflight(ctx)
+		src.Close()
+		if err != nil {
+			return reports, fmt.Errorf("preflight of %s failed: %w", table, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// tableOptions returns the transfer options of one table.
+func (m *MultiTransfer) tableOptions(table string) Options {
+	opts := m.opts
+	opts.Table = table
+	if m.opts.Table != "" {
+		opts.Table = m.opts.Table + "." + table
+	}
+	opts.DataDir = filepath.Join(m.opts.DataDir, table)
+	opts.StagePath = subStagePath(m.target(table), m.opts.StagePath, table)
+	return opts
+}
+
+func (m *MultiTransfer) runTable(ctx context.Context, table string) (*TransferReport, error) {
+	src, ok := m.sources[table]
+	if ok {
+		delete(m.sources, table)
+	} else {
+		var err error
+		if src, err = m.open(ctx, table); err != nil {
+			return nil, err
+		}
+	}
+	defer src.Close()
+
+	opts := m.tableOptions(table)
+	if m.BatchDDL {
+		// The DDL phase already created the table.
+		opts.CreateTable = false
+	}
+
+	t := NewTransfer(src, m.target(table), m.logger, opts)
+	m.mu.Lock()
+	m.current = t
+	if m.paused {
+		t.Pause()
+	}
+	m.mu.Unlock()
+
+	return t.Run(ctx)
+}
+
+// runDDL is the BatchDDL phase. The batch runs through the first table's
+// target; the statements name their tables, so any target can run them.
+func (m *MultiTransfer) runDDL(ctx context.Context) error {
+	m.sources = make(map[string]RecordSource, len(m.tables))
+	if len(m.tables) == 0 {
+		return nil
+	}
+	var stmts []string
+	seen := make(map[string]bool)
+	for _, table := range m.tables {
+		src, err := m.open(ctx, table)
+		if err != nil {
+			return fmt.Errorf("failed to open %s: %w", table, err)
+		}
+		schema, src, err := peek(src)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", table, err)
+		}
+		m.sources[table] = src
+		if schema == nil {
+			continue
+		}
+
+		dst := m.target(table)
+		ddl, err := dst.TargetDDL(ctx, schema, m.opts.CreateTable)
+		if err != nil {
+			return fmt.Errorf("failed to plan DDL for %s: %w", table, err)
+		}
+		for _, stmt := range ddl {
+			if !seen[stmt] {
+				seen[stmt] = true
+				stmts = append(stmts, stmt)
+			}
+		}
+	}
+
+	logctx.Logger(ctx, m.logger).Info("Running DDL batch", zap.Int("tables", len(m.tables)), zap.Int("statements", len(stmts)))
+	return m.target(m.tables[0]).ExecDDL(ctx, stmts)
+}
+
+// closeSources closes sources opened by the DDL phase but never transferred.
+func (m *MultiTransfer) closeSources() {
+	for table, src := range m.sources {
+		src.Close()
+		delete(m.sources, table)
+	}
+}
+
+// Report returns the live report of the table currently being transferred.
+func (m *MultiTransfer) Report() TransferReport {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.current == nil {
+		return TransferReport{State: StateRunning}
+	}
+	return m.current.Report()
+}
+
+// Pause pauses the current table's transfer and any that start afterwards.
+func (m *MultiTransfer) Pause() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paused = true
+	if m.current != nil {
+		m.current.Pause()
+	}
+}
+
+// Resume resumes the current table's transfer.
+func (m *MultiTransfer) Resume() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.paused = false
+	if m.current != nil {
+		m.current.Resume()
+	}
+}
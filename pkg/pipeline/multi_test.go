@@ -0,0 +1,68 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/TFMV/syncronicity/pkg/snowflake"
+	"github.com/TFMV/syncronicity/pkg/snowflake/snowflaketest"
+)
+
+func TestMultiTransferLoadsEachTableIntoItsOwnTarget(t *testing.T) {
+	sizes := map[string][]int{"orders": {3, 4}, "users": {5}}
+	open := func(ctx context.Context, table string) (RecordSource, error) {
+		return newIDSource(t, 0, sizes[table]...), nil
+	}
+	targets := map[string]*snowflaketest.FakeClient{}
+	target := func(table string) snowflake.Loader {
+		if targets[table] == nil {
+			targets[table] = snowflaketest.NewFakeClient()
+			t.Cleanup(targets[table].Release)
+		}
+		return targets[table]
+	}
+	opts := Options{Table: "p.d", DataDir: t.TempDir(), StagePath: "@stage", CreateTable: true}
+
+	reports, err := NewMultiTransfer([]string{"orders", "users"}, open, target, zap.NewNop(), opts).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 2 {
+		t.Fatalf("got %d reports, want 2", len(reports))
+	}
+	for table, want := range map[string]int64{"orders": 7, "users": 5} {
+		dst := targets[table]
+		if got := dst.LoadedRows(); got != want {
+			t.Errorf("%s: loaded %d rows, want %d", table, got, want)
+		}
+		if got := len(dst.CreatedTables()); got != 1 {
+			t.Errorf("%s: target created %d times, want 1", table, got)
+		}
+	}
+}
+
+func TestMultiTransferDefaultStage(t *testing.T) {
+	open := func(ctx context.Context, table string) (RecordSource, error) {
+		return newIDSource(t, 0, 2), nil
+	}
+	dst := snowflaketest.NewFakeClient()
+	defer dst.Release()
+	target := func(table string) snowflake.Loader { return dst }
+	// No StagePath: each table's files go under the loader's stage.
+	opts := Options{Table: "p.d", DataDir: t.TempDir()}
+
+	reports, err := NewMultiTransfer([]string{"orders", "users"}, open, target, zap.NewNop(), opts).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, table := range []string{"orders", "users"} {
+		if got, want := reports[i].Files[0].Staged, table+"/arrow_record-00001.parquet"; got != want {
+			t.Errorf("%s: staged %s, want %s", table, got, want)
+		}
+	}
+	if got := dst.LoadedRows(); got != 4 {
+		t.Errorf("loaded %d rows, want 4", got)
+	}
+}
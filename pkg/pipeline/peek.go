@@ -0,0 +1,47 @@
+package pipeline
+
+import (
+	"errors"
+	"io"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// peekedSource replays a record read ahead of time before reading on.
+type peekedSource struct {
+	RecordSource
+	first arrow.Record
+	eof   bool
+}
+
+// peek reads the first record of src to learn its schema. It returns the
+// schema, nil if src is empty, and a source that yields that record first.
+func peek(src RecordSource) (*arrow.Schema, RecordSource, error) {
+	rec, err := src.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, &peekedSource{RecordSource: src, eof: true}, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return rec.Schema(), &peekedSource{RecordSource: src, first: rec}, nil
+}
+
+func (p *peekedSource) Read() (arrow.Record, error) {
+	if p.eof {
+		return nil, io.EOF
+	}
+	if rec := p.first; rec != nil {
+		p.first = nil
+		return rec, nil
+	}
+	return p.RecordSource.Read()
+}
+
+func (p *peekedSource) Close() error {
+	if p.first != nil {
+		p.first.Release()
+		p.first = nil
+	}
+	return p.RecordSource.Close()
+}
@@ -0,0 +1,124 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+
+	"github.com/TFMV/syncronicity/pkg/snowflake"
+)
+
+// PreflightReport describes what a transfer would do, gathered without loading
+// any data: the source schema and size, the target table, the SQL that would
+// run, and anything likely to go wrong.
+type PreflightReport struct {
+	Table          string               `json:"table"`
+	Schema         *arrow.Schema        `json:"-"`
+	Fields         []PreflightField     `json:"fields"`
+	EstimatedRows  int64                `json:"estimated_rows"`  // -1 if the source gives no estimate.
+	EstimatedBytes int64                `json:"estimated_bytes"` // -1 if the source gives no estimate.
+	Target         snowflake.TargetPlan `json:"target"`
+	Statements     []string             `json:"statements"`
+	Warnings       []string             `json:"warnings,omitempty"`
+}
+
+// PreflightField is a source column as shown in a PreflightReport.
+type PreflightField struct {
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	Nullable bool   `json:"nullable"`
+}
+
+// fieldsOf describes the top-level fields of schema.
+func fieldsOf(schema *arrow.Schema) []PreflightField {
+	fields := make([]PreflightField, len(schema.Fields()))
+	for i, f := range schema.Fields() {
+		fields[i] = PreflightField{Name: f.Name, Type: f.Type.String(), Nullable: f.Nullable}
+	}
+	return fields
+}
+
+// schemaSource is implemented by sources that know their schema before the
+// first read, such as *bigquery.BigQueryReader.
+type schemaSource interface {
+	Schema() (*arrow.Schema, error)
+}
+
+// estimator is implemented by sources that can estimate their size, such as
+// *bigquery.BigQueryReader.
+type estimator interface {
+	Estimate() (rows, bytes int64)
+}
+
+// Preflight inspects the source and the target table and reports what Run
+// would do, without writing, staging, or loading anything. A source that
+// can't report its schema up front is peeked at; the record read is kept, so
+// Run may still be called afterwards.
+func (t *Transfer) Preflight(ctx context.Context) (PreflightReport, error) {
+	report := PreflightReport{Table: t.opts.Table, EstimatedRows: -1, EstimatedBytes: -1}
+	if e, ok := t.src.(estimator); ok {
+		report.EstimatedRows, report.EstimatedBytes = e.Estimate()
+		if report.EstimatedRows == 0 {
+			report.Warnings = append(report.Warnings, "the source appears to be empty")
+		}
+	}
+
+	schema, err := t.sourceSchema()
+	if err != nil {
+		return report, err
+	}
+	if schema == nil {
+		report.Warnings = append(report.Warnings, "the source has no records, so its schema is unknown")
+		return report, nil
+	}
+	report.Schema = schema
+	report.Fields = fieldsOf(schema)
+	if t.opts.ExplodeColumn != "" {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("column %s is exploded before loading, which changes the loaded schema", t.opts.ExplodeColumn))
+	}
+
+	if report.Target, err = t.dst.PlanTarget(ctx, schema, t.opts.StagePath); err != nil {
+		return report, fmt.Errorf("failed to inspect the target table: %w", err)
+	}
+	target := report.Target
+	if !target.Exists && !t.opts.CreateTable {
+		report.Warnings = append(report.Warnings, "the target table does not exist and CreateTable is off, so COPY would fail")
+	}
+	if len(target.Missing) > 0 {
+		report.Warnings = append(report.Warnings, "target columns not in the source: "+strings.Join(target.Missing, ", "))
+	}
+	if len(target.Extra) > 0 {
+		report.Warnings = append(report.Warnings, "source columns not in the target: "+strings.Join(target.Extra, ", "))
+	}
+	for _, m := range target.Numeric {
+		report.Warnings = append(report.Warnings, m.String())
+	}
+
+	ddl, err := t.dst.TargetDDL(ctx, schema, t.opts.CreateTable && !target.Exists)
+	if err != nil {
+		report.Warnings = append(report.Warnings, fmt.Sprintf("preparing the target table would fail: %v", err))
+	}
+	report.Statements = append(slices.Clone(t.opts.PreLoadSQL), ddl...)
+	report.Statements = append(report.Statements, target.Statements()...)
+	report.Statements = append(report.Statements, t.opts.PostLoadSQL...)
+	return report, nil
+}
+
+// sourceSchema returns the source's schema, peeking at its first record if it
+// can't report one up front. It returns nil if the source is empty.
+func (t *Transfer) sourceSchema() (*arrow.Schema, error) {
+	if s, ok := t.src.(schemaSource); ok {
+		if schema, err := s.Schema(); err == nil {
+			return schema, nil
+		}
+	}
+	schema, src, err := peek(t.src)
+	if err != nil {
+		return nil, fmt.Errorf("error reading Arrow record: %w", err)
+	}
+	t.src = src
+	return schema, nil
+}
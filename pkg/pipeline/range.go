@@ -0,0 +1,207 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"go.uber.org/zap"
+
+	"github.com/TFMV/syncronicity/pkg/logctx"
+	"github.com/TFMV/syncronicity/pkg/snowflake"
+)
+
+// RangeOpenFunc opens the record source for one row range of the source table,
+// given as a row restriction such as "id >= 0 AND id < 1000".
+type RangeOpenFunc func(ctx context.Context, restriction string) (RecordSource, error)
+
+// RangeTransfer loads one table as several row ranges transferred concurrently,
+// for tables too large for parallel streams alone. Each range is read, written,
+// and staged by its own Transfer, in its own data subdirectory and stage path;
+// once every range has been staged, a single COPY loads them all, so a failed
+// range leaves the target untouched. The target table is prepared once, from
+// the first range's schema, before any range starts. The ranges must cover the table without
+// overlapping, or rows are lost or loaded twice.
+//
+// Options apply to every range, with these caveats: CommitPerBatch is ignored;
+// MaxPendingFiles counts the files of all ranges together, and a range that
+// reaches it loads everything staged so far, so earlier ranges' rows may be
+// loaded before a later range fails; and client-side deduplication only sees
+// one range at a time, so it is exact only when the range key is one of the
+// DedupKeys.
+type RangeTransfer struct {
+	restrictions []string
+	open         RangeOpenFunc
+	parent       *Transfer // Runs the final COPY and holds the shared progress.
+
+	mu        sync.Mutex
+	transfers []*Transfer
+	paused    bool
+}
+
+// NewRangeTransfer creates a transfer of the ranges of one table into dst.
+func NewRangeTransfer(restrictions []string, open RangeOpenFunc, dst snowflake.Loader, logger *zap.Logger, opts Options) *RangeTransfer {
+	opts.CommitPerBatch = false
+	return &RangeTransfer{
+		restrictions: restrictions,
+		open:         open,
+		parent:       NewTransfer(nil, dst, logger, opts),
+	}
+}
+
+// Run transfers every range concurrently, stopping the others at the first
+// failure, then loads the staged files. The report covers all ranges.
+func (r *RangeTransfer) Run(ctx context.Context) (*TransferReport, error) {
+	t := r.parent
+	id := logctx.CorrelationID(ctx)
+	if id == "" {
+		id = logctx.NewCorrelationID()
+		ctx = logctx.WithCorrelationID(ctx, id)
+	}
+	t.progress.setCorrelationID(id)
+
+	err := t.preLoad(ctx)
+	if err == nil {
+		err = r.run(ctx)
+	}
+	if err == nil {
+		err = t.postLoad(ctx)
+	}
+	if err == nil {
+		err = t.publish(ctx)
+	}
+	return t.finish(ctx, err)
+}
+
+func (r *RangeTransfer) run(ctx context.Context) error {
+	t := r.parent
+	sources := make([]RecordSource, 0, len(r.restrictions))
+	defer func() {
+		for _, src := range sources {
+			src.Close()
+		}
+	}()
+	for _, restriction := range r.restrictions {
+		src, err := r.open(ctx, restriction)
+		if err != nil {
+			return fmt.Errorf("failed to open range %q: %w", restriction, err)
+		}
+		sources = append(sources, src)
+	}
+
+	// Prepare the target once, before any range stages a file: in
+	// LoadReplace mode, a TRUNCATE per range could wipe rows another range
+	// already loaded.
+	schema, err := rangesSchema(sources)
+	if err != nil {
+		return err
+	}
+	if schema != nil {
+		t.progress.setSchema(schema)
+		if err := t.prepareTarget(ctx, schema); err != nil {
+			return err
+		}
+	}
+
+	r.mu.Lock()
+	for i, src := range sources {
+		opts := t.opts
+		opts.Table = fmt.Sprintf("%s#%d", t.opts.Table, i)
+		opts.DataDir = filepath.Join(t.opts.DataDir, rangeDir(i))
+		opts.StagePath = subStagePath(t.dst, t.opts.StagePath, rangeDir(i))
+		opts.Events = nil
+
+		sub := NewTransfer(src, t.dst, t.logger, opts)
+		sub.progress = t.progress
+		sub.gate = t.gate
+		sub.stageOnly = true
+		if r.paused {
+			sub.Pause()
+		}
+		r.transfers = append(r.transfers, sub)
+	}
+	r.mu.Unlock()
+
+	logctx.Logger(ctx, t.logger).Info("Transferring row ranges", zap.String("table", t.opts.Table), zap.Int("ranges", len(sources)))
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	errs := make([]error, len(r.transfers))
+	var wg sync.WaitGroup
+	for i, sub := range r.transfers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := sub.run(runCtx)
+			if err != nil && !(errors.Is(err, context.Canceled) && runCtx.Err() != nil) {
+				errs[i] = fmt.Errorf("range %q: %w", r.restrictions[i], err)
+			}
+			if err != nil {
+				cancel()
+			}
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	return t.finishLoad(ctx)
+}
+
+// rangesSchema returns the schema of the ranges' sources, from the first one
+// that reports it or, failing that, has a record to peek at. Peeked sources
+// are replaced in sources. It returns nil if every source is empty.
+func rangesSchema(sources []RecordSource) (*arrow.Schema, error) {
+	for i, src := range sources {
+		if s, ok := src.(schemaSource); ok {
+			if schema, err := s.Schema(); err == nil {
+				return schema, nil
+			}
+		}
+		schema, peeked, err := peek(src)
+		if err != nil {
+			return nil, fmt.Errorf("error reading Arrow record: %w", err)
+		}
+		sources[i] = peeked
+		if schema != nil {
+			return schema, nil
+		}
+	}
+	return nil, nil
+}
+
+// rangeDir names the data subdirectory and stage path of the i-th range.
+func rangeDir(i int) string {
+	return fmt.Sprintf("range-%03d", i)
+}
+
+// Report returns the combined progress of all ranges.
+func (r *RangeTransfer) Report() TransferReport {
+	return r.parent.Report()
+}
+
+// Pause pauses every range.
+func (r *RangeTransfer) Pause() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.paused = true
+	for _, t := range r.transfers {
+		t.Pause()
+	}
+}
+
+// Resume resumes every range.
+func (r *RangeTransfer) Resume() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.paused = false
+	for _, t := range r.transfers {
+		t.Resume()
+	}
+}
@@ -0,0 +1,104 @@
+package pipeline
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"go.uber.org/zap"
+
+	"github.com/TFMV/syncronicity/pkg/snowflake/snowflaketest"
+)
+
+func TestRangeTransferPreparesTargetOnce(t *testing.T) {
+	dst := snowflaketest.NewFakeClient()
+	defer dst.Release()
+	restrictions := []string{"id < 10", "id >= 10 AND id < 20", "id >= 20"}
+	open := func(ctx context.Context, restriction string) (RecordSource, error) {
+		for i, r := range restrictions {
+			if r == restriction {
+				return newIDSource(t, int64(i*10), 4, 6), nil
+			}
+		}
+		return nil, fmt.Errorf("unknown range %q", restriction)
+	}
+	opts := Options{Table: "t", DataDir: t.TempDir(), StagePath: "@stage", CreateTable: true, MaxPendingFiles: 2}
+
+	report, err := NewRangeTransfer(restrictions, open, dst, zap.NewNop(), opts).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := dst.BeginLoads(); got != 1 {
+		t.Errorf("BeginLoad called %d times, want once before the ranges start", got)
+	}
+	if got := len(dst.CreatedTables()); got != 1 {
+		t.Errorf("target created %d times, want 1", got)
+	}
+	if got := dst.LoadedRows(); got != 30 {
+		t.Errorf("loaded %d rows, want 30", got)
+	}
+	if report.RowsRead != 30 {
+		t.Errorf("report.RowsRead = %d, want 30", report.RowsRead)
+	}
+}
+
+func TestTransferLoadsOnlyItsOwnFiles(t *testing.T) {
+	dst := snowflaketest.NewFakeClient()
+	defer dst.Release()
+	ctx := context.Background()
+
+	// A file an earlier run staged but never loaded.
+	stale := newIDSource(t, 100, 5)
+	rec, _ := stale.Read()
+	staleFile := t.TempDir() + "/stale.parquet"
+	if err := dst.WriteArrowRecordToParquet(ctx, rec, staleFile); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.UploadParquetToStage(ctx, staleFile, "@stage"); err != nil {
+		t.Fatal(err)
+	}
+	rec.Release()
+
+	opts := Options{Table: "t", DataDir: t.TempDir(), StagePath: "@stage"}
+	if _, err := NewTransfer(newIDSource(t, 0, 3, 4), dst, zap.NewNop(), opts).Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := dst.LoadedRows(); got != 7 {
+		t.Errorf("loaded %d rows, want the 7 this run staged", got)
+	}
+	if got := len(dst.PendingRecords()); got != 1 {
+		t.Errorf("%d records left on the stage, want the stale one", got)
+	}
+}
+
+func TestRangeTransferDefaultStage(t *testing.T) {
+	dst := snowflaketest.NewFakeClient()
+	defer dst.Release()
+	restrictions := []string{"id < 10", "id >= 10"}
+	open := func(ctx context.Context, restriction string) (RecordSource, error) {
+		if restriction == restrictions[0] {
+			return newIDSource(t, 0, 2), nil
+		}
+		return newIDSource(t, 10, 3), nil
+	}
+	// No StagePath: files go to the loader's stage.
+	opts := Options{Table: "t", DataDir: t.TempDir()}
+
+	report, err := NewRangeTransfer(restrictions, open, dst, zap.NewNop(), opts).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var staged []string
+	for _, f := range report.Files {
+		staged = append(staged, f.Staged)
+	}
+	slices.Sort(staged)
+	want := []string{"range-000/arrow_record-00001.parquet", "range-001/arrow_record-00001.parquet"}
+	if !slices.Equal(staged, want) {
+		t.Errorf("staged files %v, want %v", staged, want)
+	}
+	if got := dst.LoadedRows(); got != 5 {
+		t.Errorf("loaded %d rows, want 5", got)
+	}
+}
@@ -0,0 +1,208 @@
+package pipeline
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+
+	"github.com/TFMV/syncronicity/pkg/snowflake"
+)
+
+// Transfer states reported in TransferReport.State.
+const (
+	StateRunning   = "running"
+	StatePaused    = "paused"
+	StateSucceeded = "succeeded"
+	StateFailed    = "failed"
+)
+
+// TransferReport summarizes a transfer. Stage timings are cumulative per stage
+// and are encoded in JSON as nanoseconds.
+type TransferReport struct {
+	Table         string                   `json:"table"`
+	CorrelationID string                   `json:"correlation_id"`
+	State         string                   `json:"state"`
+	StartedAt     time.Time                `json:"started_at"`
+	FinishedAt    time.Time                `json:"finished_at"`
+	RowsRead      int64                    `json:"rows_read"`
+	RowsLoaded    int64                    `json:"rows_loaded"`
+	FilesStaged   int                      `json:"files_staged"`
+	Batches       int                      `json:"batches_committed"`
+	StageTimings  map[string]time.Duration `json:"stage_timings"`
+	Errors        []string                 `json:"errors,omitempty"`
+
+	// PreLoadSQL and PostLoadSQL hold the results of the statements in
+	// Options.PreLoadSQL and Options.PostLoadSQL that were attempted.
+	PreLoadSQL  []snowflake.StatementResult `json:"pre_load_sql,omitempty"`
+	PostLoadSQL []snowflake.StatementResult `json:"post_load_sql,omitempty"`
+
+	// Files lists every file staged, and QueryIDs the Snowflake query IDs of
+	// the COPYs that loaded them.
+	Files    []FileReport `json:"files,omitempty"`
+	QueryIDs []string     `json:"query_ids,omitempty"`
+}
+
+// Elapsed is how long the transfer ran, or has been running if it hasn't
+// finished.
+func (r TransferReport) Elapsed() time.Duration {
+	if r.FinishedAt.IsZero() {
+		return time.Since(r.StartedAt)
+	}
+	return r.FinishedAt.Sub(r.StartedAt)
+}
+
+// FileReport describes a staged Parquet file.
+type FileReport struct {
+	Path       string `json:"path"`   // Local path.
+	Staged     string `json:"staged"` // Path relative to the stage; see snowflake.StagedFile.
+	Rows       int64  `json:"rows"`
+	Bytes      int64  `json:"bytes"`
+	SHA256     string `json:"sha256,omitempty"` // Only computed for a manifest.
+	RowsLoaded int64  `json:"rows_loaded"`
+}
+
+// progress is the live, mutex-protected state behind a running transfer's report.
+type progress struct {
+	mu     sync.Mutex
+	report TransferReport
+	schema *arrow.Schema  // Of the first record staged.
+	loc    *time.Location // Of the report's times.
+}
+
+func newProgress(table string, loc *time.Location) *progress {
+	return &progress{loc: loc, report: TransferReport{
+		Table:        table,
+		State:        StateRunning,
+		StartedAt:    time.Now().In(loc),
+		StageTimings: make(map[string]time.Duration),
+	}}
+}
+
+func (p *progress) setCorrelationID(id string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.report.CorrelationID = id
+}
+
+func (p *progress) setState(state string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.report.FinishedAt.IsZero() {
+		p.report.State = state
+	}
+}
+
+func (p *progress) addRows(n int64) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.report.RowsRead += n
+}
+
+func (p *progress) addFile() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.report.FilesStaged++
+}
+
+func (p *progress) addStagedFile(f FileReport) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.report.Files = append(p.report.Files, f)
+}
+
+// addLoaded records COPY results against the staged files they name. Rows
+// loaded are only counted if countRows is set.
+func (p *progress) addLoaded(results []snowflake.CopyFileResult, countRows bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, r := range results {
+		if r.QueryID != "" && !slices.Contains(p.report.QueryIDs, r.QueryID) {
+			p.report.QueryIDs = append(p.report.QueryIDs, r.QueryID)
+		}
+		if !countRows {
+			continue
+		}
+		p.report.RowsLoaded += r.RowsLoaded
+		for i := range p.report.Files {
+			if r.Is(p.report.Files[i].Staged) {
+				p.report.Files[i].RowsLoaded += r.RowsLoaded
+				break
+			}
+		}
+	}
+}
+
+// setSchema records schema unless one was recorded already.
+func (p *progress) setSchema(schema *arrow.Schema) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.schema == nil {
+		p.schema = schema
+	}
+}
+
+func (p *progress) firstSchema() *arrow.Schema {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.schema
+}
+
+func (p *progress) addBatch() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.report.Batches++
+}
+
+func (p *progress) setPreLoadSQL(results []snowflake.StatementResult) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.report.PreLoadSQL = results
+}
+
+func (p *progress) setPostLoadSQL(results []snowflake.StatementResult) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.report.PostLoadSQL = results
+}
+
+// timed runs fn and adds its duration to the named stage.
+func (p *progress) timed(stage string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.report.StageTimings[stage] += time.Since(start)
+	return err
+}
+
+// finish marks the transfer as done, recording err if it failed.
+func (p *progress) finish(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.report.FinishedAt = time.Now().In(p.loc)
+	if err != nil {
+		p.report.State = StateFailed
+		p.report.Errors = append(p.report.Errors, err.Error())
+		return
+	}
+	p.report.State = StateSucceeded
+}
+
+// snapshot returns a copy of the current report that is safe to read.
+func (p *progress) snapshot() TransferReport {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r := p.report
+	r.StageTimings = make(map[string]time.Duration, len(p.report.StageTimings))
+	for k, v := range p.report.StageTimings {
+		r.StageTimings[k] = v
+	}
+	r.Errors = append([]string(nil), p.report.Errors...)
+	r.PreLoadSQL = slices.Clone(p.report.PreLoadSQL)
+	r.PostLoadSQL = slices.Clone(p.report.PostLoadSQL)
+	r.Files = slices.Clone(p.report.Files)
+	r.QueryIDs = slices.Clone(p.report.QueryIDs)
+	return r
+}
@@ -0,0 +1,69 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/TFMV/syncronicity/pkg/snowflake"
+	"github.com/TFMV/syncronicity/pkg/snowflake/snowflaketest"
+)
+
+func TestProgressAddLoaded(t *testing.T) {
+	results := []snowflake.CopyFileResult{
+		{File: "run/a.parquet", Status: "LOADED", RowsParsed: 3, RowsLoaded: 3, QueryID: "q1"},
+		{File: "run/b.parquet", Status: "LOAD_FAILED", RowsParsed: 4, ErrorsSeen: 4, QueryID: "q1"},
+	}
+	for _, tc := range []struct {
+		name      string
+		countRows bool
+		want      int64
+	}{
+		{"every file reported", true, 3},
+		{"failed files only", false, 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			p := newProgress("t", time.UTC)
+			p.addStagedFile(FileReport{Staged: "run/a.parquet", Rows: 3})
+			p.addLoaded(results, tc.countRows)
+			report := p.report
+			if report.RowsLoaded != tc.want {
+				t.Errorf("RowsLoaded = %d, want %d", report.RowsLoaded, tc.want)
+			}
+			if report.Files[0].RowsLoaded != tc.want {
+				t.Errorf("file RowsLoaded = %d, want %d", report.Files[0].RowsLoaded, tc.want)
+			}
+			if len(report.QueryIDs) != 1 || report.QueryIDs[0] != "q1" {
+				t.Errorf("QueryIDs = %v, want [q1]", report.QueryIDs)
+			}
+		})
+	}
+}
+
+func TestTransferReportUsesLocation(t *testing.T) {
+	plus5 := time.FixedZone("UTC+5", 5*60*60)
+	for _, tc := range []struct {
+		name string
+		loc  *time.Location
+		want *time.Location
+	}{
+		{"configured", plus5, plus5},
+		{"default", nil, time.UTC},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := snowflaketest.NewFakeClient()
+			defer dst.Release()
+			opts := Options{Table: "t", DataDir: t.TempDir(), StagePath: "@stage", Location: tc.loc}
+
+			report, err := NewTransfer(newIDSource(t, 0, 2), dst, zap.NewNop(), opts).Run(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.StartedAt.Location() != tc.want || report.FinishedAt.Location() != tc.want {
+				t.Errorf("report times in %s and %s, want %s", report.StartedAt.Location(), report.FinishedAt.Location(), tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package pipeline
+
+import "github.com/apache/arrow-go/v18/arrow"
+
+// RecordSource yields Arrow records until it returns io.EOF. Both
+// *bigquery.BigQueryReader and *bigquery.ShardedReader satisfy it.
+type RecordSource interface {
+	Read() (arrow.Record, error)
+	Close() error
+}
@@ -0,0 +1,61 @@
+package pipeline
+
+import (
+	"io"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// idSchema is the schema of the records built by newIDSource.
+var idSchema = arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
+
+// idSource is a RecordSource yielding records of sequential int64 ids.
+type idSource struct {
+	recs   []arrow.Record
+	closed bool
+}
+
+// newIDSource returns a source of one record per size, numbering rows from
+// first on.
+func newIDSource(t *testing.T, first int64, sizes ...int) *idSource {
+	t.Helper()
+	src := &idSource{}
+	for _, n := range sizes {
+		b := array.NewInt64Builder(memory.DefaultAllocator)
+		for i := 0; i < n; i++ {
+			b.Append(first)
+			first++
+		}
+		col := b.NewArray()
+		b.Release()
+		src.recs = append(src.recs, array.NewRecord(idSchema, []arrow.Array{col}, int64(n)))
+		col.Release()
+	}
+	t.Cleanup(func() { src.Close() })
+	return src
+}
+
+func (s *idSource) Read() (arrow.Record, error) {
+	if len(s.recs) == 0 {
+		return nil, io.EOF
+	}
+	rec := s.recs[0]
+	s.recs = s.recs[1:]
+	return rec, nil
+}
+
+func (s *idSource) Schema() (*arrow.Schema, error) {
+	return idSchema, nil
+}
+
+func (s *idSource) Close() error {
+	for _, rec := range s.recs {
+		rec.Release()
+	}
+	s.recs = nil
+	s.closed = true
+	return nil
+}
@@ -0,0 +1,26 @@
+package pipeline
+
+import "github.com/apache/arrow-go/v18/arrow"
+
+// SplitRecord slices rec into chunks of at most maxRows rows using zero-copy
+// record slices. The original record is released once it has been sliced, so the
+// caller owns (and must release) every returned chunk instead.
+//
+// Each chunk is written as its own Parquet file, and pqarrow writes each record
+// as a single row group, so maxRows also bounds the row-group size. File rolling
+// applies after slicing: chunks are never merged back together.
+//
+// A maxRows of zero or less, or a record that already fits, is returned as is.
+func SplitRecord(rec arrow.Record, maxRows int64) []arrow.Record {
+	if maxRows <= 0 || rec.NumRows() <= maxRows {
+		return []arrow.Record{rec}
+	}
+	defer rec.Release()
+
+	chunks := make([]arrow.Record, 0, (rec.NumRows()+maxRows-1)/maxRows)
+	for start := int64(0); start < rec.NumRows(); start += maxRows {
+		end := min(start+maxRows, rec.NumRows())
+		chunks = append(chunks, rec.NewSlice(start, end))
+	}
+	return chunks
+}
@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Controllable is a running transfer the status server can report on and
+// pause. Transfer, MultiTransfer, and RangeTransfer implement it.
+type Controllable interface {
+	Report() TransferReport
+	Pause()
+	Resume()
+}
+
+// NewStatusServer returns an HTTP server exposing a transfer's live progress:
+// GET /status returns the current TransferReport as JSON and GET /healthz
+// returns 200 while the process is up. POST /pause and POST /resume pause and
+// resume the transfer (see Transfer.Pause). The caller starts and shuts it down.
+//
+// /status and /healthz are read-only and open to any client. Because pause and
+// resume control the transfer, they require an "Authorization: Bearer <token>"
+// header matching token; with an empty token they are only accepted from
+// loopback clients.
+func NewStatusServer(addr, token string, t Controllable, logger *zap.Logger) *http.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(t.Report()); err != nil {
+			logger.Warn("Failed to write status response", zap.Error(err))
+		}
+	})
+	mux.HandleFunc("POST /pause", authorizeControl(token, logger, func(w http.ResponseWriter, r *http.Request) {
+		t.Pause()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	mux.HandleFunc("POST /resume", authorizeControl(token, logger, func(w http.ResponseWriter, r *http.Request) {
+		t.Resume()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte("ok\n"))
+	})
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
+
+// authorizeControl wraps a handler that changes the transfer's state so it only
+// runs for requests carrying token, or for loopback clients when no token is
+// configured.
+func authorizeControl(token string, logger *zap.Logger, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if token == "" {
+			if !isLoopback(r.RemoteAddr) {
+				logger.Warn("Rejected status control request from non-loopback client",
+					zap.String("path", r.URL.Path), zap.String("remote_addr", r.RemoteAddr))
+				http.Error(w, "pause and resume are only accepted from localhost unless status_token is set", http.StatusForbidden)
+				return
+			}
+		} else if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), []byte("Bearer "+token)) != 1 {
+			logger.Warn("Rejected unauthorized status control request",
+				zap.String("path", r.URL.Path), zap.String("remote_addr", r.RemoteAddr))
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// isLoopback reports whether a request's RemoteAddr is a loopback address.
+func isLoopback(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
@@ -0,0 +1,72 @@
+package pipeline
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// fakeControllable counts pause and resume calls.
+type fakeControllable struct {
+	paused, resumed int
+}
+
+func (f *fakeControllable) Report() TransferReport { return TransferReport{Table: "t"} }
+func (f *fakeControllable) Pause()                 { f.paused++ }
+func (f *fakeControllable) Resume()                { f.resumed++ }
+
+func TestStatusServerControlAuthorization(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		token      string
+		remoteAddr string
+		auth       string
+		want       int
+	}{
+		{"no token, loopback", "", "127.0.0.1:5000", "", http.StatusNoContent},
+		{"no token, IPv6 loopback", "", "[::1]:5000", "", http.StatusNoContent},
+		{"no token, remote", "", "10.0.0.7:5000", "", http.StatusForbidden},
+		{"token, remote", "s3cret", "10.0.0.7:5000", "Bearer s3cret", http.StatusNoContent},
+		{"token missing", "s3cret", "127.0.0.1:5000", "", http.StatusUnauthorized},
+		{"token wrong", "s3cret", "10.0.0.7:5000", "Bearer nope", http.StatusUnauthorized},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			ctl := &fakeControllable{}
+			srv := NewStatusServer("", tc.token, ctl, zap.NewNop())
+			for _, path := range []string{"/pause", "/resume"} {
+				req := httptest.NewRequest(http.MethodPost, path, nil)
+				req.RemoteAddr = tc.remoteAddr
+				if tc.auth != "" {
+					req.Header.Set("Authorization", tc.auth)
+				}
+				rec := httptest.NewRecorder()
+				srv.Handler.ServeHTTP(rec, req)
+				if rec.Code != tc.want {
+					t.Errorf("POST %s = %d, want %d", path, rec.Code, tc.want)
+				}
+			}
+			want := 0
+			if tc.want == http.StatusNoContent {
+				want = 1
+			}
+			if ctl.paused != want || ctl.resumed != want {
+				t.Errorf("paused %d, resumed %d times, want %d", ctl.paused, ctl.resumed, want)
+			}
+		})
+	}
+}
+
+func TestStatusServerReadOnlyEndpointsOpen(t *testing.T) {
+	srv := NewStatusServer("", "s3cret", &fakeControllable{}, zap.NewNop())
+	for _, path := range []string{"/status", "/healthz"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = "10.0.0.7:5000"
+		rec := httptest.NewRecorder()
+		srv.Handler.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s = %d, want %d", path, rec.Code, http.StatusOK)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package pipeline
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"go.uber.org/zap"
+)
+
+// LeakPolicy controls how a RecordTracker reacts when a record is released
+// twice or is still outstanding when the tracker is drained.
+type LeakPolicy int
+
+const (
+	// LeakPolicyWarn logs a warning and keeps going. Outstanding records are
+	// still released.
+	LeakPolicyWarn LeakPolicy = iota
+	// LeakPolicyError logs the problem and returns an error wrapping
+	// ErrRecordLeak, which fails the transfer. Useful in tests to catch
+	// lifecycle bugs early.
+	LeakPolicyError
+)
+
+// ErrRecordLeak is wrapped by the errors a RecordTracker returns under
+// LeakPolicyError.
+var ErrRecordLeak = errors.New("record tracker")
+
+// ParseLeakPolicy converts a config string ("warn", "error") into a LeakPolicy.
+// An empty string selects LeakPolicyWarn.
+func ParseLeakPolicy(s string) (LeakPolicy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "warn":
+		return LeakPolicyWarn, nil
+	case "error":
+		return LeakPolicyError, nil
+	default:
+		return LeakPolicyWarn, fmt.Errorf("unknown record leak policy %q (supported: warn, error)", s)
+	}
+}
+
+// trackedRecord holds bookkeeping for a record owned by the tracker.
+type trackedRecord struct {
+	label string
+	since time.Time
+}
+
+// RecordTracker owns the release of Arrow records handed out by the reader.
+// Every record passed to Track is released exactly once, either through
+// Release or by ReleaseAll, so error paths don't need their own cleanup.
+type RecordTracker struct {
+	mu      sync.Mutex
+	records map[arrow.Record]trackedRecord
+	policy  LeakPolicy
+	maxAge  time.Duration
+	logger  *zap.Logger
+}
+
+// NewRecordTracker creates a tracker. Records held for longer than maxAge are
+// reported when released; a zero maxAge disables the lifetime check.
+func NewRecordTracker(logger *zap.Logger, policy LeakPolicy, maxAge time.Duration) *RecordTracker {
+	return &RecordTracker{
+		records: make(map[arrow.Record]trackedRecord),
+		policy:  policy,
+		maxAge:  maxAge,
+		logger:  logger,
+	}
+}
+
+// Track registers rec with the tracker and returns it for convenient chaining.
+// The tracker takes over the caller's reference; don't call rec.Release() directly.
+func (t *RecordTracker) Track(rec arrow.Record, label string) arrow.Record {
+	if rec == nil {
+		return nil
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.records[rec] = trackedRecord{label: label, since: time.Now()}
+	return rec
+}
+
+// Release releases a tracked record. Releasing a record the tracker doesn't own
+// (e.g. a double release) is reported according to the leak policy instead of
+// reaching Arrow's refcounting.
+func (t *RecordTracker) Release(rec arrow.Record) error {
+	if rec == nil {
+		return nil
+	}
+	t.mu.Lock()
+	tr, ok := t.records[rec]
+	delete(t.records, rec)
+	t.mu.Unlock()
+
+	if !ok {
+		return t.report("release of untracked or already released record", zap.Int64("numRows", rec.NumRows()))
+	}
+	rec.Release()
+
+	if age := time.Since(tr.since); t.maxAge > 0 && age > t.maxAge {
+		t.logger.Warn("Arrow record outlived its expected lifecycle",
+			zap.String("record", tr.label), zap.Duration("age", age), zap.Duration("maxAge", t.maxAge))
+	}
+	return nil
+}
+
+// Outstanding returns the number of records that have not been released yet.
+func (t *RecordTracker) Outstanding() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.records)
+}
+
+// ReleaseAll releases every outstanding record. It is intended to be deferred by
+// the orchestrator so records are freed on error paths too. Any record still
+// outstanding at this point is a leak and is reported per the leak policy.
+func (t *RecordTracker) ReleaseAll() error {
+	t.mu.Lock()
+	leaked := t.records
+	t.records = make(map[arrow.Record]trackedRecord)
+	t.mu.Unlock()
+
+	if len(leaked) == 0 {
+		return nil
+	}
+	labels := make([]string, 0, len(leaked))
+	for rec, tr := range leaked {
+		rec.Release()
+		labels = append(labels, tr.label)
+	}
+	return t.report("Arrow records were not released by their owner", zap.Int("count", len(leaked)), zap.Strings("records", labels))
+}
+
+// report applies the leak policy to a lifecycle problem. It never panics: the
+// tracker may be drained from any transfer goroutine, where a panic would take
+// down the whole process instead of failing that transfer.
+func (t *RecordTracker) report(msg string, fields ...zap.Field) error {
+	switch t.policy {
+	case LeakPolicyError:
+		t.logger.Error(msg, fields...)
+		return fmt.Errorf("%w: %s", ErrRecordLeak, msg)
+	default:
+		t.logger.Warn(msg, fields...)
+		return nil
+	}
+}
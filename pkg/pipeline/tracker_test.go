@@ -0,0 +1,61 @@
+package pipeline
+
+import (
+	"errors"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func TestRecordTrackerLeakPolicy(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		policy  LeakPolicy
+		wantErr bool
+	}{
+		{"warn", LeakPolicyWarn, false},
+		{"error", LeakPolicyError, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			src := newIDSource(t, 1, 2, 3)
+			tracker := NewRecordTracker(zap.NewNop(), tc.policy, 0)
+			released, _ := src.Read()
+			leaked, _ := src.Read()
+			tracker.Track(released, "released")
+			tracker.Track(leaked, "leaked")
+
+			if err := tracker.Release(released); err != nil {
+				t.Fatalf("Release() = %v", err)
+			}
+			err := tracker.Release(released)
+			if got := errors.Is(err, ErrRecordLeak); got != tc.wantErr {
+				t.Errorf("double Release() = %v, want ErrRecordLeak %t", err, tc.wantErr)
+			}
+
+			// Drain from another goroutine, as concurrent transfers do.
+			done := make(chan error)
+			go func() { done <- tracker.ReleaseAll() }()
+			err = <-done
+			if got := errors.Is(err, ErrRecordLeak); got != tc.wantErr {
+				t.Errorf("ReleaseAll() = %v, want ErrRecordLeak %t", err, tc.wantErr)
+			}
+			if n := tracker.Outstanding(); n != 0 {
+				t.Errorf("Outstanding() = %d after ReleaseAll", n)
+			}
+			if err := tracker.ReleaseAll(); err != nil {
+				t.Errorf("second ReleaseAll() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestParseLeakPolicy(t *testing.T) {
+	for in, want := range map[string]LeakPolicy{"": LeakPolicyWarn, "warn": LeakPolicyWarn, " Error ": LeakPolicyError} {
+		if got, err := ParseLeakPolicy(in); err != nil || got != want {
+			t.Errorf("ParseLeakPolicy(%q) = %v, %v, want %v", in, got, err, want)
+		}
+	}
+	if _, err := ParseLeakPolicy("panic"); err == nil {
+		t.Error(`ParseLeakPolicy("panic") succeeded`)
+	}
+}
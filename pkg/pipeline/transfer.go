@@ -0,0 +1,737 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"go.uber.org/zap"
+
+	"github.com/TFMV/syncronicity/pkg/events"
+	"github.com/TFMV/syncronicity/pkg/logctx"
+	"github.com/TFMV/syncronicity/pkg/snowflake"
+)
+
+// Options configures a Transfer.
+type Options struct {
+	Table         string        // Source table name, used in logs and the report.
+	DataDir       string        // Directory for local Parquet files.
+	StagePath     string        // Snowflake stage the files are uploaded to.
+	MaxRecordRows int64         // Slice records larger than this; see SplitRecord.
+	LeakPolicy    LeakPolicy    // How record lifecycle problems are reported.
+	MaxRecordAge  time.Duration // Warn when a record is held longer than this.
+	CreateTable   bool          // Create the target table from the first record's schema.
+
+	// WidenVarchar checks every record's longest strings against the target
+	// table's VARCHAR(n) columns and widens them before the record is staged.
+	// The target is only inspected when a record has a longer string than
+	// seen so far. Tables created by this tool use the maximum VARCHAR length
+	// and never need it; it is meant for pre-existing narrow tables.
+	WidenVarchar bool
+
+	// ExplodeColumn, if set, names a repeated RECORD (list-of-struct) column
+	// unnested into one row per element before loading; see ExplodeRecord.
+	ExplodeColumn string
+
+	// DedupKeys, if set, removes rows with duplicate values in these columns
+	// using DedupStrategy.
+	DedupKeys     []string
+	DedupStrategy DedupStrategy
+
+	// Allocator backs records the transfer builds itself (when exploding or
+	// deduplicating). Nil uses the default allocator. Readers and the
+	// Snowflake client take their own allocator, so share one across all of
+	// them to bound a run's total memory; see BoundedAllocator.
+	Allocator memory.Allocator
+
+	// Events, if set, is sent a TableLoaded event after a successful transfer.
+	// A failed publish is logged and ignored unless FailOnPublishError is set.
+	Events             events.Publisher
+	FailOnPublishError bool
+
+	// Hook, if set, is told about every file as it is written, uploaded, and loaded.
+	Hook FileHook
+
+	// VerifyAfterWrite re-opens every Parquet file after it is written and
+	// checks its metadata and row count before uploading it, so a corrupt
+	// file fails fast instead of at COPY time. VerifyRowGroup also decodes
+	// the first row group. Both cost a read of the file and are off by
+	// default; the time spent is reported as the "verify" stage.
+	VerifyAfterWrite bool
+	VerifyRowGroup   bool
+
+	// Concurrency, if set, gates uploads and COPYs and adapts how many may run
+	// at once to COPY latency; see AdaptiveLimiter. Share one across the
+	// transfers of a run (such as a RangeTransfer's ranges) so they back off
+	// together.
+	Concurrency *AdaptiveLimiter
+
+	// CommitPerBatch runs COPY after every record instead of once at the end, so
+	// each batch is committed as soon as it is staged. Snowflake's load metadata
+	// skips files already loaded from the stage, so each COPY picks up only the
+	// new files. This lowers latency and makes progress durable, at the cost of
+	// one COPY round trip (and warehouse time) per record; prefer the bulk path
+	// for large backfills.
+	CommitPerBatch bool
+
+	// MaxPendingFiles, if positive, bounds the files staged but not yet
+	// loaded. Once that many are waiting, the transfer stops writing new
+	// files and runs a COPY, continuing when it has drained the backlog. This
+	// keeps the stage from growing without bound when Snowflake ingestion is
+	// slower than reading. Zero stages everything before the final COPY.
+	MaxPendingFiles int
+
+	// CopyAttempts is how many times a COPY that fails, or that reports
+	// files it failed to load, is attempted in total. Retries load only the
+	// files not loaded yet. Zero or one doesn't retry. Files that still fail
+	// after the last attempt fail the transfer with *snowflake.ErrLoadErrors.
+	CopyAttempts int
+
+	// ReturnFailedOnly must be set when the Snowflake client's COPY uses
+	// RETURN_FAILED_ONLY. COPY then lists only the files it failed to load,
+	// so rows loaded aren't counted: the report's RowsLoaded stays zero.
+	ReturnFailedOnly bool
+
+	// PreLoadSQL runs before anything is read and PostLoadSQL after the load
+	// succeeded, each on a connection of its own; see Client.ExecSQL. Neither
+	// shares a transaction with the COPY, which commits separately. The
+	// statements' results are kept in the report. A failing pre-load
+	// statement fails the transfer before any data moves; a failing post-load
+	// statement is logged and ignored unless FailOnPostLoadError is set.
+	PreLoadSQL          []string
+	PostLoadSQL         []string
+	FailOnPostLoadError bool
+
+	// Manifest, if set, is sent a Manifest describing the transfer when it
+	// finishes, whether or not it succeeded; Source is recorded in it. Files
+	// are checksummed after they are written, which costs a read of each.
+	Manifest ManifestWriter
+	Source   ManifestSource
+
+	// Location is the time zone of the times in the report, manifest, and
+	// events. Nil uses UTC.
+	Location *time.Location
+}
+
+// location returns Location, defaulting to UTC.
+func (o Options) location() *time.Location {
+	if o.Location == nil {
+		return time.UTC
+	}
+	return o.Location
+}
+
+// Transfer moves every record from a source into Snowflake: each record is
+// written to a Parquet file and staged, and a final COPY loads the stage.
+type Transfer struct {
+	src      RecordSource
+	dst      snowflake.Loader
+	opts     Options
+	logger   *zap.Logger
+	progress *progress
+
+	pauseMu sync.Mutex
+	resume  chan struct{} // Non-nil while paused; closed by Resume.
+
+	varcharWidths map[string]int // Target VARCHAR widths known to fit; see WidenVarchar.
+	gate          *copyGate
+
+	// stageOnly leaves COPY and server-side deduplication to the caller; see
+	// RangeTransfer.
+	stageOnly bool
+}
+
+// NewTransfer creates a transfer from src to dst.
+func NewTransfer(src RecordSource, dst snowflake.Loader, logger *zap.Logger, opts Options) *Transfer {
+	return &Transfer{
+		src:      src,
+		dst:      dst,
+		opts:     opts,
+		logger:   logger,
+		progress: newProgress(opts.Table, opts.location()),
+		gate:     &copyGate{},
+	}
+}
+
+// subStagePath returns the path sub under stagePath, or under dst's stage when
+// stagePath is empty, so files of concurrent or successive transfers sharing
+// a stage are kept apart.
+func subStagePath(dst snowflake.Loader, stagePath, sub string) string {
+	if stagePath == "" {
+		stagePath = dst.StageName()
+	}
+	return strings.TrimSuffix(stagePath, "/") + "/" + sub
+}
+
+// copyGate serializes the COPYs of transfers sharing a stage and tracks the
+// files staged since the last one, named relative to the stage.
+type copyGate struct {
+	mu      sync.Mutex // Held for the duration of a COPY.
+	pending []string
+	pmu     sync.Mutex // Guards pending.
+}
+
+// add records a newly staged file and returns the number now pending.
+func (g *copyGate) add(file string) int {
+	g.pmu.Lock()
+	defer g.pmu.Unlock()
+	g.pending = append(g.pending, file)
+	return len(g.pending)
+}
+
+// snapshot returns the pending files.
+func (g *copyGate) snapshot() []string {
+	g.pmu.Lock()
+	defer g.pmu.Unlock()
+	return slices.Clone(g.pending)
+}
+
+// count returns the number of pending files.
+func (g *copyGate) count() int {
+	g.pmu.Lock()
+	defer g.pmu.Unlock()
+	return len(g.pending)
+}
+
+// drained records that a COPY loaded the first n pending files. Files staged
+// by other transfers while the COPY ran stay pending.
+func (g *copyGate) drained(n int) {
+	g.pmu.Lock()
+	defer g.pmu.Unlock()
+	g.pending = slices.Delete(g.pending, 0, n)
+}
+
+// Report returns a snapshot of the transfer's progress. It is safe to call from
+// other goroutines while Run is in progress.
+func (t *Transfer) Report() TransferReport {
+	return t.progress.snapshot()
+}
+
+// Pause stops the transfer from reading new records once the current one has
+// been staged. The source is left open, so its read session and offsets are
+// kept; BigQuery read sessions expire after six hours, which bounds how long a
+// transfer can stay paused. Pausing a paused transfer has no effect.
+func (t *Transfer) Pause() {
+	t.pauseMu.Lock()
+	defer t.pauseMu.Unlock()
+	if t.resume == nil {
+		t.resume = make(chan struct{})
+		t.progress.setState(StatePaused)
+	}
+}
+
+// Resume continues a paused transfer. Resuming a running transfer has no effect.
+func (t *Transfer) Resume() {
+	t.pauseMu.Lock()
+	defer t.pauseMu.Unlock()
+	if t.resume != nil {
+		close(t.resume)
+		t.resume = nil
+		t.progress.setState(StateRunning)
+	}
+}
+
+// waitWhilePaused blocks until the transfer is resumed or ctx is done.
+func (t *Transfer) waitWhilePaused(ctx context.Context) error {
+	t.pauseMu.Lock()
+	ch := t.resume
+	t.pauseMu.Unlock()
+	if ch == nil {
+		return nil
+	}
+
+	logctx.Logger(ctx, t.logger).Info("Transfer paused", zap.String("table", t.opts.Table))
+	select {
+	case <-ch:
+		logctx.Logger(ctx, t.logger).Info("Transfer resumed", zap.String("table", t.opts.Table))
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Run executes the transfer and returns its final report. Log lines from the
+// transfer carry the correlation ID from ctx, or a new one if ctx has none; the
+// ID is recorded in the report.
+func (t *Transfer) Run(ctx context.Context) (*TransferReport, error) {
+	id := logctx.CorrelationID(ctx)
+	if id == "" {
+		id = logctx.NewCorrelationID()
+		ctx = logctx.WithCorrelationID(ctx, id)
+	}
+	t.progress.setCorrelationID(id)
+
+	err := t.preLoad(ctx)
+	if err == nil {
+		err = t.run(ctx)
+	}
+	if err == nil {
+		err = t.postLoad(ctx)
+	}
+	if err == nil {
+		err = t.publish(ctx)
+	}
+	return t.finish(ctx, err)
+}
+
+// finish records the outcome of a run and writes the manifest, if any. A
+// manifest that can't be written fails an otherwise successful run.
+func (t *Transfer) finish(ctx context.Context, err error) (*TransferReport, error) {
+	t.progress.finish(err)
+	if merr := t.writeManifest(ctx); merr != nil {
+		logctx.Logger(ctx, t.logger).Error("Failed to write manifest", zap.String("table", t.opts.Table), zap.Error(merr))
+		if err == nil {
+			err = merr
+		}
+	}
+	report := t.Report()
+	return &report, err
+}
+
+func (t *Transfer) run(ctx context.Context) error {
+	// Track every record handed out by the source so it is released exactly once.
+	tracker := NewRecordTracker(logctx.Logger(ctx, t.logger), t.opts.LeakPolicy, t.opts.MaxRecordAge)
+	defer tracker.ReleaseAll()
+
+	if err := os.MkdirAll(t.opts.DataDir, 0755); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+
+	if t.opts.Allocator != nil {
+		ctx = compute.WithAllocator(ctx, t.opts.Allocator)
+	}
+
+	var deduper *Deduper
+	if len(t.opts.DedupKeys) > 0 && t.opts.DedupStrategy == DedupClient {
+		deduper = NewDeduper(t.opts.DedupKeys)
+	}
+
+	// Read every Arrow record, writing and staging each one as a Parquet file.
+	fileCount := 0
+	for {
+		if err := t.waitWhilePaused(ctx); err != nil {
+			return err
+		}
+
+		var rec arrow.Record
+		err := t.progress.timed("read", func() (err error) {
+			rec, err = t.src.Read()
+			return err
+		})
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("error reading Arrow record: %w", err)
+		}
+		if err := t.memoryErr(); err != nil {
+			rec.Release()
+			return err
+		}
+		t.progress.addRows(rec.NumRows())
+		if t.opts.ExplodeColumn != "" {
+			exploded, err := ExplodeRecord(ctx, rec, t.opts.ExplodeColumn)
+			rec.Release()
+			if err != nil {
+				return err
+			}
+			rec = exploded
+		}
+		if deduper != nil {
+			deduped, err := deduper.Filter(ctx, rec)
+			rec.Release()
+			if err != nil {
+				return err
+			}
+			rec = deduped
+		}
+		if rec.NumRows() == 0 {
+			rec.Release()
+			continue
+		}
+		if fileCount == 0 {
+			t.progress.setSchema(rec.Schema())
+			// A stage-only range's target was prepared by its RangeTransfer.
+			if !t.stageOnly {
+				if err := t.prepareTarget(ctx, rec.Schema()); err != nil {
+					rec.Release()
+					return err
+				}
+			}
+		}
+		if t.opts.WidenVarchar {
+			if err := t.fitVarchar(ctx, rec); err != nil {
+				rec.Release()
+				return err
+			}
+		}
+		logctx.Logger(ctx, t.logger).Info("Arrow record read", zap.String("table", t.opts.Table), zap.Int64("numRows", rec.NumRows()))
+
+		// Split oversized records so each Parquet file stays within the target size.
+		chunks := SplitRecord(rec, t.opts.MaxRecordRows)
+		for i, chunk := range chunks {
+			tracker.Track(chunk, fmt.Sprintf("%s[%d]", t.opts.Table, fileCount+i))
+		}
+
+		for _, chunk := range chunks {
+			fileCount++
+			parquetFile := filepath.Join(t.opts.DataDir, fmt.Sprintf("arrow_record-%05d.parquet", fileCount))
+
+			// Write the Arrow record to a Parquet file and upload it to the Snowflake stage.
+			if err := t.stage(ctx, chunk, parquetFile); err != nil {
+				return fmt.Errorf("error processing Arrow record for Snowflake stage: %w", err)
+			}
+			t.progress.addFile()
+			if err := tracker.Release(chunk); err != nil {
+				return err
+			}
+			if n := t.gate.add(snowflake.StagedFile(t.opts.StagePath, parquetFile)); t.opts.MaxPendingFiles > 0 && n >= t.opts.MaxPendingFiles {
+				logctx.Logger(ctx, t.logger).Info("Pending file limit reached; loading staged files",
+					zap.String("table", t.opts.Table), zap.Int("pending", n))
+				if err := t.commit(ctx); err != nil {
+					return err
+				}
+			}
+		}
+
+		if t.opts.CommitPerBatch && !t.stageOnly {
+			if err := t.commit(ctx); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := t.memoryErr(); err != nil {
+		return err
+	}
+	if t.stageOnly {
+		return tracker.ReleaseAll()
+	}
+	if err := t.finishLoad(ctx); err != nil {
+		return err
+	}
+	return tracker.ReleaseAll()
+}
+
+// memoryErr returns the failure recorded by the transfer's allocator, if it is
+// a BoundedAllocator that ran out of memory.
+func (t *Transfer) memoryErr() error {
+	if b, ok := t.opts.Allocator.(*BoundedAllocator); ok {
+		return b.Err()
+	}
+	return nil
+}
+
+// finishLoad runs the final COPY, unless every batch was already committed, and
+// any server-side deduplication. Nothing is loaded if the source was empty.
+func (t *Transfer) finishLoad(ctx context.Context) error {
+	if t.Report().FilesStaged == 0 {
+		return t.finishEmpty(ctx)
+	}
+	// Load the data into Snowflake using a COPY command.
+	if !t.opts.CommitPerBatch {
+		if err := t.commit(ctx); err != nil {
+			return err
+		}
+	}
+	if len(t.opts.DedupKeys) > 0 && t.opts.DedupStrategy == DedupServer {
+		if err := t.dst.DeduplicateTarget(ctx, t.opts.DedupKeys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finishEmpty completes a transfer whose source had no rows, so no file was
+// staged and there is nothing to COPY. If the source reports its schema, the
+// target is still prepared as it would be for the first record: created if
+// CreateTable is set, checked, and, in LoadReplace mode, truncated, so the
+// load leaves the target matching the empty source.
+func (t *Transfer) finishEmpty(ctx context.Context) error {
+	logctx.Logger(ctx, t.logger).Info("Source is empty; nothing to load", zap.String("table", t.opts.Table))
+	s, ok := t.src.(schemaSource)
+	if !ok {
+		return nil
+	}
+	schema, err := s.Schema()
+	if err != nil {
+		// Without a schema there is no target to prepare.
+		return nil
+	}
+	t.progress.setSchema(schema)
+	return t.prepareTarget(ctx, schema)
+}
+
+// preLoad runs the PreLoadSQL statements.
+func (t *Transfer) preLoad(ctx context.Context) error {
+	if len(t.opts.PreLoadSQL) == 0 {
+		return nil
+	}
+	results, err := t.dst.ExecSQL(ctx, t.opts.PreLoadSQL)
+	t.progress.setPreLoadSQL(results)
+	if err != nil {
+		return fmt.Errorf("pre-load SQL failed: %w", err)
+	}
+	return nil
+}
+
+// postLoad runs the PostLoadSQL statements after a successful load.
+func (t *Transfer) postLoad(ctx context.Context) error {
+	if len(t.opts.PostLoadSQL) == 0 {
+		return nil
+	}
+	results, err := t.dst.ExecSQL(ctx, t.opts.PostLoadSQL)
+	t.progress.setPostLoadSQL(results)
+	if err == nil {
+		return nil
+	}
+	if t.opts.FailOnPostLoadError {
+		return fmt.Errorf("post-load SQL failed: %w", err)
+	}
+	logctx.Logger(ctx, t.logger).Warn("Post-load SQL failed", zap.String("table", t.opts.Table), zap.Error(err))
+	return nil
+}
+
+// publish announces a successful transfer to the configured publisher.
+func (t *Transfer) publish(ctx context.Context) error {
+	if t.opts.Events == nil {
+		return nil
+	}
+	report := t.Report()
+	err := t.opts.Events.Publish(ctx, events.TableLoaded{
+		Table:         t.opts.Table,
+		Rows:          report.RowsRead,
+		Files:         report.FilesStaged,
+		LoadedAt:      time.Now().In(t.opts.location()),
+		CorrelationID: report.CorrelationID,
+	})
+	if err == nil || t.opts.FailOnPublishError {
+		return err
+	}
+	logctx.Logger(ctx, t.logger).Warn("Failed to publish table loaded event", zap.String("table", t.opts.Table), zap.Error(err))
+	return nil
+}
+
+// stage writes rec to parquetFile and uploads it, notifying the hook after each step.
+func (t *Transfer) stage(ctx context.Context, rec arrow.Record, parquetFile string) error {
+	err := t.progress.timed("stage", func() error {
+		return t.dst.WriteArrowRecordToParquet(ctx, rec, parquetFile)
+	})
+	if err != nil {
+		return err
+	}
+	if t.opts.VerifyAfterWrite {
+		err := t.progress.timed("verify", func() error {
+			return snowflake.VerifyParquetFile(ctx, parquetFile, rec.NumRows(), t.opts.VerifyRowGroup, t.opts.Allocator)
+		})
+		if err != nil {
+			return err
+		}
+	}
+	info, err := os.Stat(parquetFile)
+	if err != nil {
+		return err
+	}
+	file := FileReport{
+		Path:   parquetFile,
+		Staged: snowflake.StagedFile(t.opts.StagePath, parquetFile),
+		Rows:   rec.NumRows(),
+		Bytes:  info.Size(),
+	}
+	if t.opts.Manifest != nil {
+		if file.SHA256, err = fileChecksum(parquetFile); err != nil {
+			return err
+		}
+	}
+	if t.opts.Hook != nil {
+		t.opts.Hook.OnWritten(parquetFile, rec.NumRows(), info.Size())
+	}
+
+	err = t.progress.timed("stage", func() error {
+		return t.limited(ctx, false, func() error {
+			return t.dst.UploadParquetToStage(ctx, parquetFile, t.opts.StagePath)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	t.progress.addStagedFile(file)
+	if t.opts.Hook != nil {
+		t.opts.Hook.OnUploaded(parquetFile, strings.TrimSuffix(t.opts.StagePath, "/")+"/"+filepath.Base(parquetFile))
+	}
+	return nil
+}
+
+// commit COPYs the staged files into the target table.
+func (t *Transfer) commit(ctx context.Context) error {
+	t.gate.mu.Lock()
+	staged := t.gate.snapshot()
+	results, err := t.copyResumable(ctx, staged)
+	if err == nil {
+		t.gate.drained(len(staged))
+	}
+	t.gate.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("error loading data into Snowflake: %w", err)
+	}
+	t.progress.addLoaded(results, !t.opts.ReturnFailedOnly)
+	if t.opts.Hook != nil {
+		for _, r := range results {
+			t.opts.Hook.OnLoaded(r.File, r.RowsLoaded)
+		}
+	}
+	t.progress.addBatch()
+	report := t.Report()
+	logctx.Logger(ctx, t.logger).Info("Batch committed", zap.String("table", t.opts.Table),
+		zap.Int("batch", report.Batches), zap.Int64("rowsRead", report.RowsRead))
+	return nil
+}
+
+// copyResumable COPYs the staged files, making up to CopyAttempts attempts.
+// Each COPY lists the files in FILES clauses of at most MaxCopyFiles files,
+// so only the files this transfer staged are loaded, never files left on the
+// stage by earlier runs. After a failed attempt, only the files not loaded
+// yet are retried: those whose COPY failed outright, since a failed COPY
+// statement loads nothing, and those a COPY reported as failed. The results
+// of every attempt are returned, with an *snowflake.ErrLoadErrors listing
+// the files that still failed after the last attempt.
+func (t *Transfer) copyResumable(ctx context.Context, staged []string) ([]snowflake.CopyFileResult, error) {
+	attempts := max(t.opts.CopyAttempts, 1)
+	var all []snowflake.CopyFileResult
+	remaining := staged
+	for attempt := 1; ; attempt++ {
+		var results []snowflake.CopyFileResult
+		var notCopied []string
+		err := t.progress.timed("copy", func() error {
+			return t.limited(ctx, true, func() error {
+				for i := 0; i < len(remaining); i += snowflake.MaxCopyFiles {
+					chunk := remaining[i:min(i+snowflake.MaxCopyFiles, len(remaining))]
+					res, err := t.dst.CopyFiles(ctx, chunk)
+					results = append(results, res...)
+					if err != nil {
+						notCopied = remaining[i:]
+						return err
+					}
+				}
+				return nil
+			})
+		})
+		all = append(all, results...)
+		if err == nil {
+			remaining = failedFiles(remaining, results)
+			if len(remaining) == 0 {
+				return all, nil
+			}
+			if attempt >= attempts {
+				return all, &snowflake.ErrLoadErrors{Files: failedResults(remaining, results)}
+			}
+			err = fmt.Errorf("%d files failed to load", len(remaining))
+		} else {
+			remaining = append(failedFiles(remaining, results), notCopied...)
+			if attempt >= attempts || ctx.Err() != nil {
+				return all, err
+			}
+		}
+		logctx.Logger(ctx, t.logger).Warn("Retrying COPY of files not loaded yet", zap.String("table", t.opts.Table),
+			zap.Int("files", len(remaining)), zap.Int("attempt", attempt), zap.Error(err))
+	}
+}
+
+// failedResults returns the results of COPY for the given failed files.
+func failedResults(failed []string, results []snowflake.CopyFileResult) []snowflake.CopyFileResult {
+	var out []snowflake.CopyFileResult
+	for _, r := range results {
+		if r.Failed() && slices.ContainsFunc(failed, r.Is) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+// failedFiles returns the files among staged that COPY reported as failed.
+func failedFiles(staged []string, results []snowflake.CopyFileResult) []string {
+	var failed []string
+	for _, f := range staged {
+		for _, r := range results {
+			if r.Failed() && r.Is(f) {
+				failed = append(failed, f)
+				break
+			}
+		}
+	}
+	return failed
+}
+
+// limited runs fn once the concurrency limiter, if any, admits it. With
+// observe set, fn's latency on success feeds the limiter.
+func (t *Transfer) limited(ctx context.Context, observe bool, fn func() error) error {
+	l := t.opts.Concurrency
+	if l == nil {
+		return fn()
+	}
+	if err := l.Acquire(ctx); err != nil {
+		return err
+	}
+	defer l.Release()
+
+	start := time.Now()
+	err := fn()
+	if observe && err == nil {
+		latency, before := time.Since(start), l.Limit()
+		l.Observe(latency)
+		if after := l.Limit(); after != before {
+			logctx.Logger(ctx, t.logger).Info("Snowflake concurrency adjusted", zap.String("table", t.opts.Table),
+				zap.Int("from", before), zap.Int("to", after), zap.Duration("copyLatency", latency))
+		}
+	}
+	return err
+}
+
+// fitVarchar widens target VARCHAR columns that are too narrow for rec.
+func (t *Transfer) fitVarchar(ctx context.Context, rec arrow.Record) error {
+	lengths := snowflake.MaxStringLengths(rec)
+	if t.varcharWidths != nil {
+		fits := true
+		for name, n := range lengths {
+			if w, ok := t.varcharWidths[name]; ok && n > w {
+				fits = false
+				break
+			}
+		}
+		if fits {
+			return nil
+		}
+	}
+	widths, err := t.dst.FitVarcharColumns(ctx, lengths)
+	if err != nil {
+		return fmt.Errorf("failed to widen VARCHAR columns: %w", err)
+	}
+	t.varcharWidths = widths
+	return nil
+}
+
+// prepareTarget readies the target table before the first file is staged: it is
+// created if requested, and checked against the source schema so column
+// mismatches and numeric overflow are caught before any data moves. Then the
+// load begins, which truncates the table in replace mode.
+func (t *Transfer) prepareTarget(ctx context.Context, schema *arrow.Schema) error {
+	if t.opts.CreateTable {
+		if err := t.dst.EnsureTargetTable(ctx, schema); err != nil {
+			return err
+		}
+	}
+	if err := t.dst.CheckTargetSchema(ctx, schema); err != nil {
+		return err
+	}
+	return t.dst.BeginLoad(ctx)
+}
@@ -0,0 +1,49 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"go.uber.org/zap"
+
+	"github.com/TFMV/syncronicity/pkg/snowflake/snowflaketest"
+)
+
+// schemalessSource hides idSource's Schema method.
+type schemalessSource struct{ src *idSource }
+
+func (s schemalessSource) Read() (arrow.Record, error) { return s.src.Read() }
+func (s schemalessSource) Close() error                { return s.src.Close() }
+
+func TestTransferEmptySource(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		src         func() RecordSource
+		wantPrepare bool
+	}{
+		{"with schema", func() RecordSource { return newIDSource(t, 0) }, true},
+		{"without schema", func() RecordSource { return schemalessSource{newIDSource(t, 0)} }, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dst := snowflaketest.NewFakeClient()
+			defer dst.Release()
+			opts := Options{Table: "t", DataDir: t.TempDir(), StagePath: "@stage", CreateTable: true}
+
+			report, err := NewTransfer(tc.src(), dst, zap.NewNop(), opts).Run(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if report.State != StateSucceeded || report.FilesStaged != 0 {
+				t.Errorf("report state %s with %d files, want succeeded with none", report.State, report.FilesStaged)
+			}
+			if got := dst.Copies(); got != 0 {
+				t.Errorf("COPY ran %d times for an empty source", got)
+			}
+			prepared := len(dst.CreatedTables()) == 1 && dst.BeginLoads() == 1
+			if prepared != tc.wantPrepare {
+				t.Errorf("target prepared = %v (created %d, began %d), want %v", prepared, len(dst.CreatedTables()), dst.BeginLoads(), tc.wantPrepare)
+			}
+		})
+	}
+}
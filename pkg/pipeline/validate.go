@@ -0,0 +1,62 @@
+package pipeline
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+
+	"github.com/TFMV/syncronicity/pkg/snowflake"
+)
+
+// ValidationReport summarizes a single-batch round-trip validation.
+type ValidationReport struct {
+	Rows   int64    // Rows in the validated batch.
+	File   string   // Local Parquet file that was staged.
+	Errors []string // Rejected rows reported by COPY VALIDATION_MODE.
+}
+
+// OK reports whether the batch would load without errors.
+func (r *ValidationReport) OK() bool {
+	return len(r.Errors) == 0
+}
+
+// ValidateRoundTrip reads a single batch from src, writes it to Parquet, stages it,
+// and runs COPY in VALIDATION_MODE so schema, type, and format problems surface on
+// real data without committing anything. The staged and local validation files are
+// removed afterwards, whether or not validation succeeds.
+func ValidateRoundTrip(ctx context.Context, src RecordSource, dst *snowflake.Client, dataDir, stagePath string) (*ValidationReport, error) {
+	rec, err := src.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, fmt.Errorf("source has no rows to validate")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read validation batch: %w", err)
+	}
+	defer rec.Release()
+
+	report := &ValidationReport{
+		Rows: rec.NumRows(),
+		File: filepath.Join(dataDir, "validate.parquet"),
+	}
+	defer os.Remove(report.File)
+
+	if err := dst.ArrowToParquetStage(ctx, rec, report.File, stagePath); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := dst.RemoveStagedFile(ctx, stagePath, report.File); err != nil {
+			dst.Logger.Warn("Failed to remove staged validation file", zap.String("file", report.File), zap.Error(err))
+		}
+	}()
+
+	report.Errors, err = dst.ValidateStagedFile(ctx, stagePath, report.File)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,183 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+
+	bq "cloud.google.com/go/bigquery"
+)
+
+// RecordMapping selects how CreateTableSQLFromBigQuery maps RECORD columns.
+type RecordMapping int
+
+const (
+	// RecordVariant maps each RECORD column to a VARIANT column (the default).
+	RecordVariant RecordMapping = iota
+	// RecordFlatten maps each field of a non-repeated RECORD to its own
+	// column named parent_field, recursively. Repeated RECORDs can't be
+	// flattened and map to ARRAY.
+	RecordFlatten
+)
+
+// ParseRecordMapping converts a config string ("variant" or "flatten") into a
+// RecordMapping. An empty string selects RecordVariant.
+func ParseRecordMapping(s string) (RecordMapping, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "variant":
+		return RecordVariant, nil
+	case "flatten":
+		return RecordFlatten, nil
+	default:
+		return RecordVariant, fmt.Errorf("unknown record mapping %q (supported: variant, flatten)", s)
+	}
+}
+
+// BigQueryDDLOptions controls how CreateTableSQLFromBigQuery renders a table.
+// DDLOptions.Kind may not be TableIceberg, whose types CreateTableSQL maps.
+type BigQueryDDLOptions struct {
+	DDLOptions
+	Records RecordMapping
+	// ClusterBy adds a CLUSTER BY clause on the table's partitioning column,
+	// truncated to the partition granularity, followed by its clustering
+	// columns. Ingestion-time partitioning has no column and is left out.
+	ClusterBy bool
+}
+
+// BigQueryColumnType returns the Snowflake type of a BigQuery column. RECORD
+// columns map to VARIANT and REPEATED columns to ARRAY.
+func BigQueryColumnType(f *bq.FieldSchema) (string, error) {
+	if f.Repeated {
+		return "ARRAY", nil
+	}
+	switch f.Type {
+	case bq.StringFieldType:
+		return "VARCHAR", nil
+	case bq.BytesFieldType:
+		return "BINARY", nil
+	case bq.IntegerFieldType:
+		return "NUMBER(38,0)", nil
+	case bq.FloatFieldType:
+		return "FLOAT", nil
+	case bq.BooleanFieldType:
+		return "BOOLEAN", nil
+	case bq.TimestampFieldType:
+		return "TIMESTAMP_TZ", nil
+	case bq.DateFieldType:
+		return "DATE", nil
+	case bq.TimeFieldType:
+		return "TIME(6)", nil
+	case bq.DateTimeFieldType:
+		return "TIMESTAMP_NTZ", nil
+	case bq.NumericFieldType, bq.BigNumericFieldType:
+		precision, scale := f.Precision, f.Scale
+		if precision == 0 {
+			// Unparameterized NUMERIC is NUMERIC(38,9); BIGNUMERIC is wider
+			// than any NUMBER.
+			if f.Type == bq.BigNumericFieldType {
+				return "", fmt.Errorf("BIGNUMERIC without a precision exceeds Snowflake's maximum precision of %d", maxNumberPrecision)
+			}
+			precision, scale = 38, 9
+		}
+		if precision > maxNumberPrecision {
+			return "", fmt.Errorf("decimal precision %d exceeds Snowflake's maximum of %d", precision, maxNumberPrecision)
+		}
+		return fmt.Sprintf("NUMBER(%d,%d)", precision, scale), nil
+	case bq.GeographyFieldType:
+		return "GEOGRAPHY", nil
+	case bq.JSONFieldType, bq.RecordFieldType:
+		return "VARIANT", nil
+	case bq.RangeFieldType:
+		return "OBJECT", nil
+	case bq.IntervalFieldType:
+		return "VARCHAR", nil
+	default:
+		return "", fmt.Errorf("no Snowflake type mapping for BigQuery type %s", f.Type)
+	}
+}
+
+// CreateTableSQLFromBigQuery generates a CREATE TABLE IF NOT EXISTS statement
+// from a BigQuery table's metadata, as returned by
+// bigquery.BigQueryReadClient.TableMetadata, so the target can be reviewed
+// before any data moves. REQUIRED columns are NOT NULL; a flattened field is
+// NOT NULL only if it and every enclosing RECORD are REQUIRED.
+func CreateTableSQLFromBigQuery(table string, md *bq.TableMetadata, opts BigQueryDDLOptions) (string, error) {
+	if opts.Kind == TableIceberg {
+		return "", fmt.Errorf("cannot create Iceberg table %s from BigQuery metadata; use CreateTableSQL with the read schema", table)
+	}
+	if len(md.Schema) == 0 {
+		return "", fmt.Errorf("cannot create table %s from an empty schema", table)
+	}
+	var cols []string
+	var add func(prefix string, fields bq.Schema, required bool) error
+	add = func(prefix string, fields bq.Schema, required bool) error {
+		for _, f := range fields {
+			name := prefix + f.Name
+			if opts.Records == RecordFlatten && f.Type == bq.RecordFieldType && !f.Repeated {
+				if err := add(name+"_", f.Schema, required && f.Required); err != nil {
+					return err
+				}
+				continue
+			}
+			typ, err := BigQueryColumnType(f)
+			if err != nil {
+				return fmt.Errorf("column %q: %w", name, err)
+			}
+			col := fmt.Sprintf("%s %s", opts.Quoting.Quote(name), typ)
+			if def, ok := opts.Defaults[name]; ok {
+				col += " DEFAULT " + def
+			}
+			if required && f.Required {
+				col += " NOT NULL"
+			}
+			cols = append(cols, col)
+		}
+		return nil
+	}
+	if err := add("", md.Schema, true); err != nil {
+		return "", err
+	}
+
+	query := fmt.Sprintf("%s IF NOT EXISTS %s (\n  %s\n)", opts.Kind.keyword(), opts.Quoting.QuoteQualified(table), strings.Join(cols, ",\n  "))
+	if opts.ClusterBy {
+		if keys := clusteringKeys(md, opts.Quoting); len(keys) > 0 {
+			query += fmt.Sprintf("\nCLUSTER BY (%s)", strings.Join(keys, ", "))
+		}
+	}
+	return query, nil
+}
+
+// clusteringKeys returns the CLUSTER BY expressions for a table's
+// partitioning and clustering columns.
+func clusteringKeys(md *bq.TableMetadata, q QuoteStrategy) []string {
+	var keys []string
+	if p := md.TimePartitioning; p != nil && p.Field != "" {
+		col := q.Quote(p.Field)
+		var typ bq.FieldType
+		for _, f := range md.Schema {
+			if f.Name == p.Field {
+				typ = f.Type
+			}
+		}
+		switch {
+		case p.Type == bq.HourPartitioningType:
+			keys = append(keys, fmt.Sprintf("DATE_TRUNC('HOUR', %s)", col))
+		case p.Type == bq.MonthPartitioningType:
+			keys = append(keys, fmt.Sprintf("DATE_TRUNC('MONTH', %s)", col))
+		case p.Type == bq.YearPartitioningType:
+			keys = append(keys, fmt.Sprintf("DATE_TRUNC('YEAR', %s)", col))
+		case typ == bq.DateFieldType:
+			keys = append(keys, col)
+		default:
+			keys = append(keys, fmt.Sprintf("TO_DATE(%s)", col))
+		}
+	}
+	if p := md.RangePartitioning; p != nil && p.Field != "" {
+		keys = append(keys, q.Quote(p.Field))
+	}
+	if md.Clustering != nil {
+		for _, f := range md.Clustering.Fields {
+			keys = append(keys, q.Quote(f))
+		}
+	}
+	return keys
+}
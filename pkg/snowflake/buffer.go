@@ -0,0 +1,66 @@
+package snowflake
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"go.uber.org/zap"
+
+	"github.com/TFMV/syncronicity/pkg/metrics"
+)
+
+// ErrInternalStageFromMemory is returned when in-memory Parquet is uploaded to
+// an internal stage: PUT only reads local files.
+var ErrInternalStageFromMemory = errors.New("in-memory Parquet can only be uploaded to an external stage; PUT reads local files")
+
+// WriteArrowRecordToParquetBuffer writes the record to Parquet in memory,
+// with the same preparation and settings as WriteArrowRecordToParquet, and
+// returns the file's bytes. Stage them with UploadParquetBytes.
+func (c *Client) WriteArrowRecordToParquetBuffer(ctx context.Context, record arrow.Record) ([]byte, error) {
+	record, err := c.prepareRecord(ctx, record)
+	if err != nil {
+		return nil, err
+	}
+	defer record.Release()
+
+	var buf bytes.Buffer
+	writer, err := c.newParquetWriter(record.Schema(), &buf)
+	if err != nil {
+		return nil, err
+	}
+	if err := writer.Write(record); err != nil {
+		writer.Close()
+		return nil, fmt.Errorf("failed to write Arrow record to Parquet: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close Parquet writer: %w", err)
+	}
+	c.logger(ctx).Info("Successfully wrote Arrow record to Parquet in memory", zap.Int("bytes", buf.Len()))
+	return buf.Bytes(), nil
+}
+
+// UploadParquetBytes uploads Parquet data, such as that returned by
+// WriteArrowRecordToParquetBuffer, to the external stage as the file name,
+// without touching the local filesystem. An empty stagePath uploads to the
+// stage named by Stage. Internal stages are loaded with PUT, which only reads
+// local files, so they fail with ErrInternalStageFromMemory.
+func (c *Client) UploadParquetBytes(ctx context.Context, data []byte, name, stagePath string) error {
+	if !c.Stage.external() {
+		return ErrInternalStageFromMemory
+	}
+	if name == "" || path.Base(name) != name {
+		return fmt.Errorf("staged file name %q must be a plain file name", name)
+	}
+	err := c.uploadObject(ctx, name, stagePath, func() (io.ReadCloser, int64, error) {
+		return io.NopCloser(bytes.NewReader(data)), int64(len(data)), nil
+	})
+	if err == nil && !c.PrintSQLOnly {
+		metrics.Or(c.Metrics).RecordBytesUploaded(int64(len(data)))
+	}
+	return err
+}
@@ -0,0 +1,193 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+)
+
+// MissingColumnPolicy decides what happens when the target table has a column
+// the source doesn't. MATCH_BY_COLUMN_NAME loads such columns as NULL.
+type MissingColumnPolicy int
+
+const (
+	// MissingAsNull lets target-only columns load as NULL (the default).
+	MissingAsNull MissingColumnPolicy = iota
+	// MissingError fails the load before any data moves.
+	MissingError
+)
+
+// ParseMissingColumnPolicy parses a policy name: "" or "null", or "error".
+func ParseMissingColumnPolicy(s string) (MissingColumnPolicy, error) {
+	switch s {
+	case "", "null":
+		return MissingAsNull, nil
+	case "error":
+		return MissingError, nil
+	default:
+		return MissingAsNull, fmt.Errorf("unknown missing column policy %q (want null or error)", s)
+	}
+}
+
+// ExtraColumnPolicy decides what happens when the source has a column the
+// target table doesn't. MATCH_BY_COLUMN_NAME silently skips such columns.
+type ExtraColumnPolicy int
+
+const (
+	// ExtraIgnore skips source-only columns (the default).
+	ExtraIgnore ExtraColumnPolicy = iota
+	// ExtraError fails the load before any data moves.
+	ExtraError
+)
+
+// ParseExtraColumnPolicy parses a policy name: "" or "ignore", or "error".
+func ParseExtraColumnPolicy(s string) (ExtraColumnPolicy, error) {
+	switch s {
+	case "", "ignore":
+		return ExtraIgnore, nil
+	case "error":
+		return ExtraError, nil
+	default:
+		return ExtraIgnore, fmt.Errorf("unknown extra column policy %q (want ignore or error)", s)
+	}
+}
+
+// ColumnInfo describes a column of an existing Snowflake table.
+type ColumnInfo struct {
+	Name     string
+	Type     string // As reported by DESCRIBE TABLE, e.g. "NUMBER(38,0)" or "VARCHAR(16777216)".
+	Nullable bool
+}
+
+// DescribeTable returns the columns of an existing table in definition order.
+func (c *Client) DescribeTable(ctx context.Context, table string) ([]ColumnInfo, error) {
+	rows, err := c.queryRows(ctx, fmt.Sprintf("DESCRIBE TABLE %s", c.quoteIdentifier(table)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to describe table %s: %w", table, err)
+	}
+	cols := make([]ColumnInfo, 0, len(rows))
+	for _, row := range rows {
+		if row["kind"] != "" && row["kind"] != "COLUMN" {
+			continue
+		}
+		cols = append(cols, ColumnInfo{
+			Name:     row["name"],
+			Type:     row["type"],
+			Nullable: row["null?"] == "Y",
+		})
+	}
+	return cols, nil
+}
+
+// describeIfExists describes table, reporting false instead of an error when
+// the table doesn't exist yet.
+func (c *Client) describeIfExists(ctx context.Context, table string) ([]ColumnInfo, bool, error) {
+	cols, err := c.DescribeTable(ctx, table)
+	if err != nil {
+		if strings.Contains(strings.ToLower(err.Error()), "does not exist") {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	return cols, true, nil
+}
+
+// CheckTargetSchema compares the source schema with the existing COPY target
+// table before any data is loaded: the column policies are enforced (see
+// MissingColumns and ExtraColumns) and decimal columns are checked as in
+// CheckNumericFit. Columns match case-insensitively, like MATCH_BY_COLUMN_NAME.
+// Snowflake has no COPY option enforcing these policies for Parquet, so the
+// check happens here. It is skipped when the table doesn't exist yet, in
+// PrintSQLOnly mode, and when there is nothing to check.
+func (c *Client) CheckTargetSchema(ctx context.Context, schema *arrow.Schema) error {
+	stmts, err := c.TargetDDL(ctx, schema, false)
+	if err != nil {
+		return err
+	}
+	return c.ExecDDL(ctx, stmts)
+}
+
+// checkColumnMatch enforces the missing and extra column policies.
+func (c *Client) checkColumnMatch(schema *arrow.Schema, target []ColumnInfo) error {
+	missing, extra := columnDiff(schema, target)
+	if c.MissingColumns == MissingError && len(missing) > 0 {
+		return fmt.Errorf("target table has columns missing from the source: %s", strings.Join(missing, ", "))
+	}
+	if c.ExtraColumns == ExtraError && len(extra) > 0 {
+		return fmt.Errorf("source has columns not in the target table: %s", strings.Join(extra, ", "))
+	}
+	return nil
+}
+
+// columnDiff returns the target columns missing from the source schema and the
+// source columns missing from the target, matching names case-insensitively.
+func columnDiff(schema *arrow.Schema, target []ColumnInfo) (missing, extra []string) {
+	source := make(map[string]bool, schema.NumFields())
+	for _, f := range schema.Fields() {
+		source[strings.ToUpper(f.Name)] = true
+	}
+	inTarget := make(map[string]bool, len(target))
+	for _, col := range target {
+		inTarget[strings.ToUpper(col.Name)] = true
+		if !source[strings.ToUpper(col.Name)] {
+			missing = append(missing, col.Name)
+		}
+	}
+	for _, f := range schema.Fields() {
+		if !inTarget[strings.ToUpper(f.Name)] {
+			extra = append(extra, f.Name)
+		}
+	}
+	return missing, extra
+}
+
+// queryRows runs a query and returns every row as a map from lower-cased column
+// name to the value's string form. It is meant for small metadata result sets.
+func (c *Client) queryRows(ctx context.Context, query string) ([]map[string]string, error) {
+	conn, release, err := c.connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	stmt, err := conn.NewStatement()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Snowflake statement: %w", err)
+	}
+	defer stmt.Close()
+
+	if err := stmt.SetSqlQuery(query); err != nil {
+		return nil, fmt.Errorf("failed to set SQL query: %w", err)
+	}
+	rdr, _, err := stmt.ExecuteQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rdr.Release()
+	return readRows(rdr)
+}
+
+// readRows drains a result reader into one map per row, keyed by lower-cased
+// column name, holding each value's string form. Nulls are omitted.
+func readRows(rdr array.RecordReader) ([]map[string]string, error) {
+	var rows []map[string]string
+	for rdr.Next() {
+		rec := rdr.Record()
+		for i := 0; i < int(rec.NumRows()); i++ {
+			row := make(map[string]string, rec.NumCols())
+			for j, f := range rec.Schema().Fields() {
+				if col := rec.Column(j); col.IsValid(i) {
+					row[strings.ToLower(f.Name)] = col.ValueStr(i)
+				}
+			}
+			rows = append(rows, row)
+		}
+	}
+	if err := rdr.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read query results: %w", err)
+	}
+	return rows, nil
+}
@@ -0,0 +1,78 @@
+package snowflake
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"go.uber.org/zap"
+)
+
+func TestParseColumnPolicies(t *testing.T) {
+	for s, want := range map[string]MissingColumnPolicy{"": MissingAsNull, "null": MissingAsNull, "error": MissingError} {
+		if got, err := ParseMissingColumnPolicy(s); err != nil || got != want {
+			t.Errorf("ParseMissingColumnPolicy(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseMissingColumnPolicy("ignore"); err == nil {
+		t.Error("ParseMissingColumnPolicy(ignore) succeeded, want an error")
+	}
+	for s, want := range map[string]ExtraColumnPolicy{"": ExtraIgnore, "ignore": ExtraIgnore, "error": ExtraError} {
+		if got, err := ParseExtraColumnPolicy(s); err != nil || got != want {
+			t.Errorf("ParseExtraColumnPolicy(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseExtraColumnPolicy("null"); err == nil {
+		t.Error("ParseExtraColumnPolicy(null) succeeded, want an error")
+	}
+}
+
+// matchSchema is a source whose "Id" column differs from the target's "ID"
+// only in case, with a source-only "email" column.
+var matchSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "Id", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "name", Type: arrow.BinaryTypes.String},
+	{Name: "email", Type: arrow.BinaryTypes.String},
+}, nil)
+
+// matchTarget has a target-only "CREATED_AT" column.
+var matchTarget = []ColumnInfo{{Name: "ID"}, {Name: "NAME"}, {Name: "CREATED_AT"}}
+
+func TestColumnDiff(t *testing.T) {
+	missing, extra := columnDiff(matchSchema, matchTarget)
+	if !slices.Equal(missing, []string{"CREATED_AT"}) {
+		t.Errorf("missing = %v, want [CREATED_AT]", missing)
+	}
+	if !slices.Equal(extra, []string{"email"}) {
+		t.Errorf("extra = %v, want [email]", extra)
+	}
+}
+
+func TestCheckColumnMatch(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		missing MissingColumnPolicy
+		extra   ExtraColumnPolicy
+		wantErr string
+	}{
+		{"defaults", MissingAsNull, ExtraIgnore, ""},
+		{"missing fails", MissingError, ExtraIgnore, "target table has columns missing from the source: CREATED_AT"},
+		{"extra fails", MissingAsNull, ExtraError, "source has columns not in the target table: email"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewClient("", zap.NewNop())
+			c.MissingColumns, c.ExtraColumns = tc.missing, tc.extra
+			err := c.checkColumnMatch(matchSchema, matchTarget)
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("err = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
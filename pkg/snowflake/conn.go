@@ -0,0 +1,139 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"go.uber.org/zap"
+)
+
+// connPool holds the database and connection a Client reuses across
+// operations.
+type connPool struct {
+	mu sync.Mutex // Guards db and conn.
+	db adbc.Database
+
+	// conn is the connection opened by Connect. ADBC connections run one
+	// statement at a time, so operations hold connMu while they use it.
+	conn   adbc.Connection
+	connMu sync.Mutex
+}
+
+// Connect opens a Snowflake connection and keeps it for every later
+// operation until Close, saving a connection handshake per PUT, COPY, or
+// DDL statement. Operations then run one at a time on that connection, which
+// also keeps session state such as temporary tables alive between them.
+// Without Connect, each operation opens its own connection, so operations
+// may run concurrently. Calling Connect again has no effect.
+func (c *Client) Connect(ctx context.Context) error {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+	if c.pool.conn != nil {
+		return nil
+	}
+	db, err := c.database()
+	if err != nil {
+		return err
+	}
+	conn, err := db.Open(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to open Snowflake connection: %w", err)
+	}
+	c.pool.conn = conn
+	return nil
+}
+
+// connected reports whether operations share the connection opened by Connect.
+func (c *Client) connected() bool {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+	return c.pool.conn != nil
+}
+
+// Close releases the connection opened by Connect and the database the
+// client has been using. The client may be used again afterwards, reopening
+// them as needed.
+func (c *Client) Close() error {
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+	var errs []error
+	if c.pool.conn != nil {
+		c.pool.connMu.Lock()
+		errs = append(errs, c.pool.conn.Close())
+		c.pool.connMu.Unlock()
+		c.pool.conn = nil
+	}
+	if c.pool.db != nil {
+		errs = append(errs, c.pool.db.Close())
+		c.pool.db = nil
+	}
+	return errors.Join(errs...)
+}
+
+// database returns the client's database, initializing the driver on first
+// use. c.pool.mu must be held.
+func (c *Client) database() (adbc.Database, error) {
+	if c.pool.db == nil {
+		db, err := c.openDatabase()
+		if err != nil {
+			return nil, err
+		}
+		c.pool.db = db
+	}
+	return c.pool.db, nil
+}
+
+// heldConnKey marks a context whose operation holds the connection opened by
+// Connect; its value is the client. See holding.
+type heldConnKey struct{}
+
+// holding returns ctx marked as holding the client's shared connection, for
+// calls made while an operation holds it, such as EnsureStage during a COPY,
+// which then reuse it instead of waiting for it forever.
+func (c *Client) holding(ctx context.Context) context.Context {
+	return context.WithValue(ctx, heldConnKey{}, c)
+}
+
+// connection returns a connection for one operation and a function the
+// operation calls when done with it: the connection opened by Connect, held
+// exclusively until release, or else a new connection that release closes.
+func (c *Client) connection(ctx context.Context) (conn adbc.Connection, release func(), err error) {
+	c.pool.mu.Lock()
+	if shared := c.pool.conn; shared != nil {
+		c.pool.mu.Unlock()
+		if ctx.Value(heldConnKey{}) == c {
+			return shared, func() {}, nil
+		}
+		c.pool.connMu.Lock()
+		return shared, c.pool.connMu.Unlock, nil
+	}
+	db, err := c.database()
+	c.pool.mu.Unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+	conn, err = db.Open(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open Snowflake connection: %w", err)
+	}
+	return conn, func() { conn.Close() }, nil
+}
+
+// Ping checks that Snowflake is reachable with the client's credentials and
+// that the session has a warehouse to run COPY in, by querying the version and
+// current warehouse. It uses the connection opened by Connect, if any;
+// otherwise it opens and closes a connection of its own.
+func (c *Client) Ping(ctx context.Context) error {
+	rows, err := c.queryRows(ctx, "SELECT CURRENT_VERSION() AS version, CURRENT_WAREHOUSE() AS warehouse")
+	if err != nil {
+		return fmt.Errorf("cannot reach Snowflake: %w", err)
+	}
+	if len(rows) == 0 || rows[0]["warehouse"] == "" {
+		return errors.New("connected to Snowflake, but the session has no warehouse; set one in the DSN or as the user's default")
+	}
+	c.logger(ctx).Info("Snowflake is reachable", zap.String("version", rows[0]["version"]), zap.String("warehouse", rows[0]["warehouse"]))
+	return nil
+}
@@ -0,0 +1,389 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"go.uber.org/zap"
+)
+
+// CopyOptions adds optional clauses to the COPY INTO statement. The zero value
+// renders none of them.
+type CopyOptions struct {
+	// SizeLimit caps the bytes a single COPY ingests. Snowflake stops loading
+	// further files once the limit is exceeded; the remaining files are picked
+	// up by the next COPY. Zero means no limit.
+	SizeLimit int64
+	// ReturnFailedOnly makes COPY return only the files that failed to load,
+	// which are then logged individually. Loaded files aren't reported, so
+	// rows loaded go uncounted: the rows-loaded metric isn't recorded and
+	// LoadArrowIntoSnowflake returns -1 rows.
+	ReturnFailedOnly bool
+	// OnError sets COPY's ON_ERROR option. It is ignored when
+	// Client.DeadLetter is set, which loads with ON_ERROR = CONTINUE.
+	OnError OnError
+	// Purge makes COPY remove each file from the stage once it is loaded, so
+	// staged Parquet doesn't accumulate. Files that fail to load are kept.
+	Purge bool
+
+	// Select, if set, transforms rows during the load: COPY reads from
+	// (SELECT <Select> FROM @stage) instead of matching columns by name.
+	// Staged columns are referenced as $1:name (or $1:"Name"), e.g.
+	// `$1:id, UPPER($1:name), $1:amount * 100`, and are checked against the
+	// source schema before loading. Snowflake doesn't support VALIDATE or
+	// VALIDATION_MODE for such loads.
+	Select string
+	// Columns lists the target columns Select's expressions load, in order.
+	// Empty means every target column in table order.
+	Columns []string
+
+	// WriterConcurrency and UploadConcurrency set the driver's
+	// ingest_writer_concurrency and ingest_upload_concurrency options on the
+	// COPY statement. NewClient sets them to DefaultWriterConcurrency and
+	// DefaultUploadConcurrency; zero leaves the driver's own default.
+	WriterConcurrency int
+	UploadConcurrency int
+
+	// Mode selects whether rows are appended, replace the table's contents,
+	// or are merged into it on MergeKeys, which LoadMerge requires.
+	Mode      LoadMode
+	MergeKeys []string
+
+	// Timeout, if positive, bounds each COPY, and the MERGE that follows it
+	// in LoadMerge mode, separately from the caller's context. When it
+	// expires, or the context is canceled, the in-flight statement is
+	// canceled, which makes the driver abort the query server-side, and the
+	// COPY returns the context's error once the statement has stopped.
+	Timeout time.Duration
+}
+
+// OnError selects what COPY does when a file has rows it can't load.
+type OnError int
+
+const (
+	// OnErrorDefault leaves ON_ERROR out, so Snowflake's default,
+	// ABORT_STATEMENT for bulk loads, applies.
+	OnErrorDefault OnError = iota
+	// OnErrorContinue loads the rows it can and skips the rest.
+	OnErrorContinue
+	// OnErrorSkipFile skips any file with an error.
+	OnErrorSkipFile
+	// OnErrorAbortStatement fails the COPY at the first error.
+	OnErrorAbortStatement
+)
+
+// ParseOnError converts a config string ("continue", "skip_file", or
+// "abort_statement") into an OnError. An empty string selects
+// OnErrorDefault.
+func ParseOnError(s string) (OnError, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "":
+		return OnErrorDefault, nil
+	case "continue":
+		return OnErrorContinue, nil
+	case "skip_file":
+		return OnErrorSkipFile, nil
+	case "abort_statement":
+		return OnErrorAbortStatement, nil
+	default:
+		return OnErrorDefault, fmt.Errorf("unknown ON_ERROR option %q (supported: continue, skip_file, abort_statement)", s)
+	}
+}
+
+// keyword returns the ON_ERROR value, or "" for OnErrorDefault.
+func (o OnError) keyword() string {
+	switch o {
+	case OnErrorContinue:
+		return "CONTINUE"
+	case OnErrorSkipFile:
+		return "SKIP_FILE"
+	case OnErrorAbortStatement:
+		return "ABORT_STATEMENT"
+	default:
+		return ""
+	}
+}
+
+// Default COPY statement concurrency set by NewClient.
+const (
+	DefaultWriterConcurrency = 4
+	DefaultUploadConcurrency = 8
+)
+
+// Validate checks that the concurrency settings aren't negative, that
+// LoadMerge has key columns, and that OnError is known.
+func (o CopyOptions) Validate() error {
+	if o.Mode == LoadMerge && len(o.MergeKeys) == 0 {
+		return fmt.Errorf("merge loads need at least one key column")
+	}
+	if o.WriterConcurrency < 0 {
+		return fmt.Errorf("COPY writer concurrency must be positive, got %d", o.WriterConcurrency)
+	}
+	if o.UploadConcurrency < 0 {
+		return fmt.Errorf("COPY upload concurrency must be positive, got %d", o.UploadConcurrency)
+	}
+	if o.Timeout < 0 {
+		return fmt.Errorf("COPY timeout must be positive, got %s", o.Timeout)
+	}
+	if o.OnError < OnErrorDefault || o.OnError > OnErrorAbortStatement {
+		return fmt.Errorf("unknown COPY ON_ERROR option %d", o.OnError)
+	}
+	return nil
+}
+
+// setConcurrency sets the statement options for the non-zero concurrency
+// settings.
+func (o CopyOptions) setConcurrency(stmt adbc.Statement) error {
+	if o.WriterConcurrency > 0 {
+		if err := stmt.SetOption("adbc.snowflake.statement.ingest_writer_concurrency", strconv.Itoa(o.WriterConcurrency)); err != nil {
+			return fmt.Errorf("failed to set writer concurrency: %w", err)
+		}
+	}
+	if o.UploadConcurrency > 0 {
+		if err := stmt.SetOption("adbc.snowflake.statement.ingest_upload_concurrency", strconv.Itoa(o.UploadConcurrency)); err != nil {
+			return fmt.Errorf("failed to set upload concurrency: %w", err)
+		}
+	}
+	return nil
+}
+
+// stagedColumnRef matches a reference to a staged Parquet column in Select.
+var stagedColumnRef = regexp.MustCompile(`\$1:(?:"([^"]+)"|([A-Za-z_][A-Za-z0-9_$]*))`)
+
+// ValidateCopySelect checks that every staged column referenced by a COPY
+// select list exists in the staged schema. Parquet column names are matched
+// case-sensitively, as Snowflake does.
+func ValidateCopySelect(sel string, schema *arrow.Schema) error {
+	refs := stagedColumnRef.FindAllStringSubmatch(sel, -1)
+	if len(refs) == 0 {
+		return fmt.Errorf("COPY select list %q references no staged columns ($1:name)", sel)
+	}
+	var unknown []string
+	for _, m := range refs {
+		name := m[1] + m[2]
+		if len(schema.FieldIndices(name)) == 0 {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("COPY select list references columns not in the source: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// CastingCopySelect builds a Copy.Select list and its Copy.Columns that load
+// every column of the source schema cast to the type the DDL generator gives
+// it, so values don't depend on how Snowflake infers staged types or on the
+// session's time zone: decimals are cast to their exact NUMBER(p,s), and
+// time-zone-aware timestamps, staged as UTC instants, are read as UTC into
+// TIMESTAMP_TZ rather than in the session's zone. Columns rewritten by
+// ColumnTransforms are loaded as staged.
+func (c *Client) CastingCopySelect(schema *arrow.Schema) (sel string, columns []string, err error) {
+	staged := withIntervalsConverted(schema, c.IntervalFormat)
+	exprs := make([]string, 0, len(staged.Fields()))
+	for _, f := range staged.Fields() {
+		if strings.Contains(f.Name, `"`) {
+			return "", nil, fmt.Errorf("column %q can't be referenced in a COPY select list", f.Name)
+		}
+		ref := fmt.Sprintf(`$1:"%s"`, f.Name)
+		columns = append(columns, f.Name)
+		if _, ok := c.ColumnTransforms[f.Name]; ok {
+			exprs = append(exprs, ref)
+			continue
+		}
+		typ, err := SnowflakeType(f.Type)
+		if err != nil {
+			return "", nil, fmt.Errorf("column %q: %w", f.Name, err)
+		}
+		exprs = append(exprs, castStaged(ref, typ))
+	}
+	return strings.Join(exprs, ", "), columns, nil
+}
+
+// castStaged casts a staged column reference to typ. Parquet timestamps
+// adjusted to UTC are read as TIMESTAMP_NTZ in UTC, which a plain cast to
+// TIMESTAMP_TZ would place in the session's time zone, so the UTC offset is
+// attached explicitly.
+func castStaged(ref, typ string) string {
+	if typ == "TIMESTAMP_TZ" {
+		return fmt.Sprintf("TO_TIMESTAMP_TZ(TO_VARCHAR(%s::TIMESTAMP_NTZ, 'YYYY-MM-DD HH24:MI:SS.FF9') || ' +00:00', 'YYYY-MM-DD HH24:MI:SS.FF9 TZH:TZM')", ref)
+	}
+	return fmt.Sprintf("%s::%s", ref, typ)
+}
+
+// clauses renders the options as COPY clauses.
+func (o CopyOptions) clauses() string {
+	var parts []string
+	if o.SizeLimit > 0 {
+		parts = append(parts, fmt.Sprintf("SIZE_LIMIT = %d", o.SizeLimit))
+	}
+	if o.ReturnFailedOnly {
+		parts = append(parts, "RETURN_FAILED_ONLY = TRUE")
+	}
+	if o.Purge {
+		parts = append(parts, "PURGE = TRUE")
+	}
+	return strings.Join(parts, " ")
+}
+
+// CopyFileResult is one row of a COPY INTO result set.
+type CopyFileResult struct {
+	File       string
+	Status     string
+	RowsParsed int64
+	RowsLoaded int64
+	ErrorsSeen int64
+	FirstError string
+	// QueryID identifies the COPY in Snowflake's query history. It is empty if
+	// it couldn't be read.
+	QueryID string
+}
+
+// Failed reports whether COPY failed to load the file at all.
+func (r CopyFileResult) Failed() bool {
+	return r.Status == "LOAD_FAILED"
+}
+
+// Is reports whether the result is for file, a path relative to the stage as
+// returned by StagedFile. PUT may have added a ".gz" suffix when
+// auto-compressing, and Snowflake may report the path with the stage name.
+func (r CopyFileResult) Is(file string) bool {
+	name := strings.TrimSuffix(r.File, ".gz")
+	return name == file || strings.HasSuffix(name, "/"+file)
+}
+
+// MaxCopyFiles is the most files a COPY FILES clause may list; see CopyFiles.
+const MaxCopyFiles = 1000
+
+// filesClause renders a COPY FILES clause listing files.
+func filesClause(files []string) string {
+	quoted := make([]string, len(files))
+	for i, f := range files {
+		quoted[i] = "'" + escapeLiteral(f) + "'"
+	}
+	return "FILES = (" + strings.Join(quoted, ", ") + ")"
+}
+
+// StagedFile returns the path, relative to the stage COPY loads from, of a
+// local file PUT to stagePath.
+func StagedFile(stagePath, localFile string) string {
+	name := strings.Trim(strings.TrimLeft(stagePath, "@"), "/")
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[i+1:] + "/" + filepath.Base(localFile)
+	}
+	return filepath.Base(localFile)
+}
+
+// parseCopyResults converts COPY result rows (see readRows) into file results.
+// The single status row COPY returns when there is nothing to load is dropped.
+func parseCopyResults(rows []map[string]string) []CopyFileResult {
+	results := make([]CopyFileResult, 0, len(rows))
+	for _, row := range rows {
+		if row["file"] == "" {
+			continue
+		}
+		parsed, _ := strconv.ParseInt(row["rows_parsed"], 10, 64)
+		loaded, _ := strconv.ParseInt(row["rows_loaded"], 10, 64)
+		seen, _ := strconv.ParseInt(row["errors_seen"], 10, 64)
+		results = append(results, CopyFileResult{
+			File:       row["file"],
+			Status:     row["status"],
+			RowsParsed: parsed,
+			RowsLoaded: loaded,
+			ErrorsSeen: seen,
+			FirstError: row["first_error"],
+		})
+	}
+	return results
+}
+
+// lastQueryID returns the ID of the last query run on stmt's connection, or ""
+// if it can't be read. It replaces stmt's query.
+func (c *Client) lastQueryID(ctx context.Context, stmt adbc.Statement) string {
+	if err := stmt.SetSqlQuery("SELECT LAST_QUERY_ID()"); err != nil {
+		return ""
+	}
+	rdr, _, err := stmt.ExecuteQuery(ctx)
+	if err != nil {
+		c.logger(ctx).Warn("Failed to read the COPY query ID", zap.Error(err))
+		return ""
+	}
+	defer rdr.Release()
+	rows, err := readRows(rdr)
+	if err != nil || len(rows) == 0 {
+		return ""
+	}
+	for _, id := range rows[0] {
+		return id
+	}
+	return ""
+}
+
+// statementCanceler is implemented by ADBC statements that can be canceled
+// while executing.
+type statementCanceler interface {
+	Cancel() error
+}
+
+// executeCopy runs a prepared COPY statement and returns its per-file results.
+// Files that didn't load cleanly are logged.
+// If ctx is done first, the statement is canceled, if the driver supports it,
+// on top of the driver aborting the query for the done ctx. executeCopy still
+// waits for the execution to return, since the caller closes the statement
+// and releases its connection afterwards; it then returns ctx's error, unless
+// the COPY completed anyway.
+func (c *Client) executeCopy(ctx context.Context, stmt adbc.Statement) ([]CopyFileResult, error) {
+	type outcome struct {
+		rows []map[string]string
+		err  error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		rdr, _, err := stmt.ExecuteQuery(ctx)
+		if err != nil {
+			done <- outcome{err: err}
+			return
+		}
+		defer rdr.Release()
+		rows, err := readRows(rdr)
+		if err != nil {
+			err = fmt.Errorf("failed to read COPY results: %w", err)
+		}
+		done <- outcome{rows: rows, err: err}
+	}()
+
+	var out outcome
+	select {
+	case <-ctx.Done():
+		if canceler, ok := stmt.(statementCanceler); ok {
+			if err := canceler.Cancel(); err != nil {
+				c.logger(ctx).Warn("Failed to cancel COPY", zap.Error(err))
+			}
+		}
+		out = <-done
+		if out.err != nil {
+			return nil, fmt.Errorf("COPY abandoned: %w", context.Cause(ctx))
+		}
+	case out = <-done:
+		if out.err != nil {
+			return nil, out.err
+		}
+	}
+	rows := out.rows
+	results := parseCopyResults(rows)
+	for _, r := range results {
+		if r.ErrorsSeen > 0 || c.Copy.ReturnFailedOnly {
+			c.logger(ctx).Warn("File failed to load",
+				zap.String("file", r.File), zap.String("status", r.Status),
+				zap.Int64("errorsSeen", r.ErrorsSeen), zap.String("firstError", r.FirstError))
+		}
+	}
+	return results, nil
+}
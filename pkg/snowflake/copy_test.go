@@ -0,0 +1,173 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"go.uber.org/zap"
+)
+
+// blockingStatement is an adbc.Statement whose ExecuteQuery blocks like a
+// long COPY until ctx is done or, with honorCancel, until Cancel is called.
+type blockingStatement struct {
+	adbc.Statement // Unimplemented methods panic.
+
+	ignoreCtx bool
+	cancel    chan struct{}
+	canceled  atomic.Bool
+	returned  atomic.Bool
+}
+
+func newBlockingStatement(ignoreCtx bool) *blockingStatement {
+	return &blockingStatement{ignoreCtx: ignoreCtx, cancel: make(chan struct{})}
+}
+
+func (s *blockingStatement) ExecuteQuery(ctx context.Context) (array.RecordReader, int64, error) {
+	defer s.returned.Store(true)
+	done := ctx.Done()
+	if s.ignoreCtx {
+		done = nil
+	}
+	select {
+	case <-done:
+		return nil, -1, ctx.Err()
+	case <-s.cancel:
+		return nil, -1, errors.New("statement canceled")
+	}
+}
+
+func (s *blockingStatement) Cancel() error {
+	if s.canceled.CompareAndSwap(false, true) {
+		close(s.cancel)
+	}
+	return nil
+}
+
+func TestExecuteCopyCanceledMidCopy(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		ignoreCtx bool // The driver only stops on Cancel.
+	}{
+		{"driver aborts on context", false},
+		{"statement canceled", true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewClient("", zap.NewNop())
+			stmt := newBlockingStatement(tc.ignoreCtx)
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			start := time.Now()
+			_, err := c.executeCopy(ctx, stmt)
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Fatalf("executeCopy returned after %s, want promptly after cancellation", elapsed)
+			}
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+			}
+			if !stmt.canceled.Load() {
+				t.Error("statement was not canceled")
+			}
+			// The caller closes the statement next, so it must be idle.
+			if !stmt.returned.Load() {
+				t.Error("executeCopy returned while the statement was still executing")
+			}
+		})
+	}
+}
+
+func TestParseCopyResults(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		rows []map[string]string
+		want []CopyFileResult
+	}{
+		{
+			name: "loaded files",
+			rows: []map[string]string{
+				{"file": "run/a.parquet", "status": "LOADED", "rows_parsed": "3", "rows_loaded": "3", "errors_seen": "0"},
+				{"file": "run/b.parquet", "status": "PARTIALLY_LOADED", "rows_parsed": "4", "rows_loaded": "3", "errors_seen": "1", "first_error": "bad value"},
+			},
+			want: []CopyFileResult{
+				{File: "run/a.parquet", Status: "LOADED", RowsParsed: 3, RowsLoaded: 3},
+				{File: "run/b.parquet", Status: "PARTIALLY_LOADED", RowsParsed: 4, RowsLoaded: 3, ErrorsSeen: 1, FirstError: "bad value"},
+			},
+		},
+		{
+			// With RETURN_FAILED_ONLY and nothing failing, COPY returns only
+			// its status row.
+			name: "failed only, none failed",
+			rows: []map[string]string{{"status": "Copy executed with 0 files processed."}},
+			want: []CopyFileResult{},
+		},
+		{
+			name: "failed only",
+			rows: []map[string]string{
+				{"file": "run/c.parquet", "status": "LOAD_FAILED", "rows_parsed": "5", "rows_loaded": "0", "errors_seen": "5", "first_error": "invalid Parquet"},
+			},
+			want: []CopyFileResult{
+				{File: "run/c.parquet", Status: "LOAD_FAILED", RowsParsed: 5, ErrorsSeen: 5, FirstError: "invalid Parquet"},
+			},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := parseCopyResults(tc.rows)
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseCopyResults() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestCastingCopySelect(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "ID", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "AMOUNT", Type: &arrow.Decimal128Type{Precision: 12, Scale: 2}, Nullable: true},
+		{Name: "CREATED", Type: &arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}},
+		{Name: "LOCAL", Type: &arrow.TimestampType{Unit: arrow.Microsecond}},
+		{Name: "SPAN", Type: arrow.FixedWidthTypes.MonthDayNanoInterval},
+		{Name: "EMAIL", Type: arrow.BinaryTypes.String},
+	}, nil)
+	c := NewClient("", zap.NewNop())
+	c.TargetTable = "ORDERS"
+	c.ColumnTransforms = map[string]ColumnTransform{"EMAIL": func(a arrow.Array) (arrow.Array, error) { return a, nil }}
+
+	sel, columns, err := c.CastingCopySelect(schema)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		`$1:"ID"::NUMBER(38,0)`,
+		`$1:"AMOUNT"::NUMBER(12,2)`,
+		`TO_TIMESTAMP_TZ(TO_VARCHAR($1:"CREATED"::TIMESTAMP_NTZ, 'YYYY-MM-DD HH24:MI:SS.FF9') || ' +00:00', 'YYYY-MM-DD HH24:MI:SS.FF9 TZH:TZM')`,
+		`$1:"LOCAL"::TIMESTAMP_NTZ`,
+		`$1:"SPAN"::VARCHAR`,
+		`$1:"EMAIL"`,
+	}
+	if sel != strings.Join(want, ", ") {
+		t.Errorf("select list\n%s\nwant\n%s", sel, strings.Join(want, ", "))
+	}
+	if !slices.Equal(columns, []string{"ID", "AMOUNT", "CREATED", "LOCAL", "SPAN", "EMAIL"}) {
+		t.Errorf("columns = %v", columns)
+	}
+
+	c.Copy.Select, c.Copy.Columns = sel, columns
+	query := c.copyStatement(c.TargetTable, "@STAGE/run", "")
+	if prefix := "COPY INTO ORDERS (ID, AMOUNT, CREATED, LOCAL, SPAN, EMAIL) FROM (SELECT " + sel + " FROM @STAGE/run) "; !strings.HasPrefix(query, prefix) {
+		t.Errorf("COPY statement\n%s\nwant prefix\n%s", query, prefix)
+	}
+
+	quoted := arrow.NewSchema([]arrow.Field{{Name: `say "hi"`, Type: arrow.BinaryTypes.String}}, nil)
+	if _, _, err := c.CastingCopySelect(quoted); err == nil {
+		t.Error("a column name with a double quote was accepted")
+	}
+}
@@ -0,0 +1,314 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"go.uber.org/zap"
+)
+
+// SnowflakeType returns the Snowflake column type used for an Arrow data type.
+func SnowflakeType(dt arrow.DataType) (string, error) {
+	switch t := dt.(type) {
+	case *arrow.BooleanType:
+		return "BOOLEAN", nil
+	case *arrow.Int8Type, *arrow.Int16Type, *arrow.Int32Type, *arrow.Int64Type,
+		*arrow.Uint8Type, *arrow.Uint16Type, *arrow.Uint32Type, *arrow.Uint64Type:
+		return "NUMBER(38,0)", nil
+	case *arrow.Float16Type, *arrow.Float32Type, *arrow.Float64Type:
+		return "FLOAT", nil
+	case *arrow.StringType, *arrow.LargeStringType:
+		return "VARCHAR", nil
+	case *arrow.BinaryType, *arrow.LargeBinaryType, *arrow.FixedSizeBinaryType:
+		return "BINARY", nil
+	case *arrow.Date32Type, *arrow.Date64Type:
+		return "DATE", nil
+	case *arrow.TimestampType:
+		// BigQuery TIMESTAMP carries a UTC zone; DATETIME has none.
+		if t.TimeZone != "" {
+			return "TIMESTAMP_TZ", nil
+		}
+		return "TIMESTAMP_NTZ", nil
+	case *arrow.Time32Type, *arrow.Time64Type:
+		// BigQuery TIME has microsecond precision; keep whatever the unit carries.
+		return fmt.Sprintf("TIME(%d)", timeUnitDigits(dt.(arrow.TemporalWithUnit).TimeUnit())), nil
+	case *arrow.DurationType, *arrow.MonthIntervalType, *arrow.DayTimeIntervalType, *arrow.MonthDayNanoIntervalType:
+		// Written as ISO-8601 strings by default; see IntervalFormat.
+		return "VARCHAR", nil
+	case *arrow.Decimal128Type:
+		if t.Precision > maxNumberPrecision {
+			return "", fmt.Errorf("decimal precision %d exceeds Snowflake's maximum of %d", t.Precision, maxNumberPrecision)
+		}
+		return fmt.Sprintf("NUMBER(%d,%d)", t.Precision, t.Scale), nil
+	case *arrow.StructType:
+		// BigQuery RECORD columns are written as Parquet groups, which COPY
+		// converts to semi-structured values when loading an OBJECT column.
+		// Field names are kept as object keys and nested types as their JSON
+		// equivalents.
+		return "OBJECT", nil
+	default:
+		return "", fmt.Errorf("no Snowflake type mapping for Arrow type %s", dt)
+	}
+}
+
+// timeUnitDigits returns the fractional-second digits of a time unit.
+func timeUnitDigits(unit arrow.TimeUnit) int {
+	switch unit {
+	case arrow.Millisecond:
+		return 3
+	case arrow.Microsecond:
+		return 6
+	case arrow.Nanosecond:
+		return 9
+	default:
+		return 0
+	}
+}
+
+// TableKind selects the kind of table created by the DDL generator.
+type TableKind int
+
+const (
+	// TablePermanent creates a regular table with Time Travel and Fail-safe (the default).
+	TablePermanent TableKind = iota
+	// TableTransient creates a table without Fail-safe, suited to staging data in ELT pipelines.
+	TableTransient
+	// TableTemporary creates a table that only exists for the current session. It
+	// is dropped when the connection that created it closes, so it is only usable
+	// when the DDL and the COPY run on the same connection; see Client.Connect.
+	TableTemporary
+	// TableIceberg creates a Snowflake-managed Iceberg table on an external
+	// volume; see IcebergOptions and IcebergType.
+	TableIceberg
+)
+
+// ParseTableKind converts a config string ("permanent", "transient",
+// "temporary", or "iceberg") into a TableKind. An empty string selects TablePermanent.
+func ParseTableKind(s string) (TableKind, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "permanent":
+		return TablePermanent, nil
+	case "transient":
+		return TableTransient, nil
+	case "temporary":
+		return TableTemporary, nil
+	case "iceberg":
+		return TableIceberg, nil
+	default:
+		return TablePermanent, fmt.Errorf("unknown table kind %q (supported: permanent, transient, temporary, iceberg)", s)
+	}
+}
+
+// keyword returns the CREATE keyword for the table kind.
+func (k TableKind) keyword() string {
+	switch k {
+	case TableTransient:
+		return "CREATE TRANSIENT TABLE"
+	case TableTemporary:
+		return "CREATE TEMPORARY TABLE"
+	case TableIceberg:
+		return "CREATE ICEBERG TABLE"
+	default:
+		return "CREATE TABLE"
+	}
+}
+
+// errTemporaryUnconnected is returned when creating a temporary target table
+// without a connection shared by the DDL and the COPY.
+var errTemporaryUnconnected = errors.New("temporary target tables are session-scoped and don't survive until COPY unless the client is connected; see Client.Connect")
+
+// DDLOptions controls how CreateTableSQL renders a table definition.
+type DDLOptions struct {
+	Kind    TableKind
+	Quoting QuoteStrategy
+	Iceberg IcebergOptions // Used with TableIceberg.
+	// Defaults holds Snowflake DEFAULT expressions keyed by column name.
+	Defaults map[string]string
+}
+
+// CreateTableSQL generates a CREATE TABLE IF NOT EXISTS statement whose columns
+// mirror the Arrow schema.
+func CreateTableSQL(table string, schema *arrow.Schema, opts DDLOptions) (string, error) {
+	if len(schema.Fields()) == 0 {
+		return "", fmt.Errorf("cannot create table %s from an empty schema", table)
+	}
+	typeOf := SnowflakeType
+	if opts.Kind == TableIceberg {
+		if opts.Iceberg.ExternalVolume == "" {
+			return "", fmt.Errorf("cannot create Iceberg table %s without an external volume", table)
+		}
+		typeOf = IcebergType
+	}
+	cols := make([]string, 0, len(schema.Fields()))
+	for _, f := range schema.Fields() {
+		typ, err := typeOf(f.Type)
+		if err != nil {
+			return "", fmt.Errorf("column %q: %w", f.Name, err)
+		}
+		col := fmt.Sprintf("%s %s", opts.Quoting.Quote(f.Name), typ)
+		if def, ok := opts.Defaults[f.Name]; ok {
+			col += " DEFAULT " + def
+		}
+		if !f.Nullable {
+			col += " NOT NULL"
+		}
+		cols = append(cols, col)
+	}
+	query := fmt.Sprintf("%s IF NOT EXISTS %s (\n  %s\n)", opts.Kind.keyword(), opts.Quoting.QuoteQualified(table), strings.Join(cols, ",\n  "))
+	if opts.Kind == TableIceberg {
+		query += "\n" + opts.Iceberg.clauses(table)
+	}
+	return query, nil
+}
+
+// ddlOptions returns the client's DDL settings.
+func (c *Client) ddlOptions() DDLOptions {
+	return DDLOptions{Kind: c.TableKind, Quoting: c.Quoting, Iceberg: c.Iceberg, Defaults: c.ColumnDefaults}
+}
+
+// alterTable returns the ALTER keyword for the client's TableKind; Iceberg
+// tables must be altered with ALTER ICEBERG TABLE.
+func (c *Client) alterTable() string {
+	if c.TableKind == TableIceberg {
+		return "ALTER ICEBERG TABLE"
+	}
+	return "ALTER TABLE"
+}
+
+// CreateTableFromArrowSchema creates a table of the client's TableKind from an
+// Arrow schema if it does not already exist.
+func (c *Client) CreateTableFromArrowSchema(ctx context.Context, table string, schema *arrow.Schema) error {
+	query, err := CreateTableSQL(table, schema, c.ddlOptions())
+	if err != nil {
+		return err
+	}
+	if _, err := c.execUpdate(ctx, query); err != nil {
+		return fmt.Errorf("failed to create table %s: %w", table, err)
+	}
+	c.logger(ctx).Info("Snowflake table ensured", zap.String("table", table))
+	return nil
+}
+
+// TargetDDL computes, without running anything, the DDL that prepares the COPY
+// target table for schema. If the table doesn't exist and create is set, that
+// is its CREATE TABLE statement. If it exists, it is checked as described in
+// CheckTargetSchema and the result holds any ALTER TABLE statements widening
+// NUMBER columns. In PrintSQLOnly mode the table isn't inspected, so only the
+// CREATE TABLE statement is returned. With Copy.Select, the select list is
+// validated against schema instead of checking the table's columns.
+func (c *Client) TargetDDL(ctx context.Context, schema *arrow.Schema, create bool) ([]string, error) {
+	if err := c.checkTargetTable(); err != nil {
+		return nil, err
+	}
+	if create && c.TableKind == TableTemporary && !c.connected() {
+		return nil, errTemporaryUnconnected
+	}
+	createTable := func() ([]string, error) {
+		if !create {
+			return nil, nil
+		}
+		if c.TableKind == TableIceberg {
+			if err := c.checkIcebergVolume(ctx); err != nil {
+				return nil, err
+			}
+		}
+		query, err := CreateTableSQL(c.TargetTable, withIntervalsConverted(schema, c.IntervalFormat), c.ddlOptions())
+		if err != nil {
+			return nil, err
+		}
+		return []string{query}, nil
+	}
+
+	if c.Copy.Select != "" {
+		// Columns are loaded by expression rather than by name, so the name
+		// based checks below don't apply.
+		if err := ValidateCopySelect(c.Copy.Select, schema); err != nil {
+			return nil, err
+		}
+		if c.PrintSQLOnly || !create {
+			return createTable()
+		}
+		if _, ok, err := c.describeIfExists(ctx, c.TargetTable); err != nil || ok {
+			return nil, err
+		}
+		return createTable()
+	}
+	if c.PrintSQLOnly {
+		return createTable()
+	}
+	if !create && !hasDecimals(schema) && c.MissingColumns == MissingAsNull && c.ExtraColumns == ExtraIgnore {
+		return nil, nil
+	}
+	cols, ok, err := c.describeIfExists(ctx, c.TargetTable)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return createTable()
+	}
+	if err := c.checkColumnMatch(schema, cols); err != nil {
+		return nil, err
+	}
+	return c.numericAlters(c.TargetTable, schema, cols)
+}
+
+// ExecDDL runs DDL statements in order on a single connection, stopping at the
+// first failure. In PrintSQLOnly mode they are all printed instead.
+func (c *Client) ExecDDL(ctx context.Context, stmts []string) error {
+	if len(stmts) == 0 {
+		return nil
+	}
+	if c.PrintSQLOnly {
+		for _, query := range stmts {
+			c.sqlOnly(query)
+		}
+		return nil
+	}
+
+	conn, release, err := c.connection(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	stmt, err := conn.NewStatement()
+	if err != nil {
+		return fmt.Errorf("failed to create Snowflake statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, query := range stmts {
+		if err := stmt.SetSqlQuery(query); err != nil {
+			return fmt.Errorf("failed to set SQL query: %w", err)
+		}
+		if _, err := stmt.ExecuteUpdate(ctx); err != nil {
+			return fmt.Errorf("failed to execute DDL %q: %w", query, err)
+		}
+	}
+	c.logger(ctx).Info("DDL executed", zap.Int("statements", len(stmts)))
+	return nil
+}
+
+// EnsureTargetTable creates the COPY target table from an Arrow schema if it does
+// not already exist. Temporary tables are rejected because every operation opens
+// its own connection, so the table would be gone before the COPY runs. For
+// Iceberg tables the external volume is checked first.
+func (c *Client) EnsureTargetTable(ctx context.Context, schema *arrow.Schema) error {
+	if err := c.checkTargetTable(); err != nil {
+		return err
+	}
+	switch c.TableKind {
+	case TableTemporary:
+		if !c.connected() {
+			return errTemporaryUnconnected
+		}
+	case TableIceberg:
+		if err := c.checkIcebergVolume(ctx); err != nil {
+			return err
+		}
+	}
+	return c.CreateTableFromArrowSchema(ctx, c.TargetTable, withIntervalsConverted(schema, c.IntervalFormat))
+}
@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"github.com/apache/arrow-go/v18/parquet/schema"
+	"go.uber.org/zap"
+)
+
+// addressType is a BigQuery RECORD column as Arrow reads it.
+var addressType = arrow.StructOf(
+	arrow.Field{Name: "city", Type: arrow.BinaryTypes.String, Nullable: true},
+	arrow.Field{Name: "geo", Type: arrow.StructOf(
+		arrow.Field{Name: "lat", Type: arrow.PrimitiveTypes.Float64},
+		arrow.Field{Name: "lng", Type: arrow.PrimitiveTypes.Float64},
+	), Nullable: true},
+)
+
+func TestSnowflakeType(t *testing.T) {
+	for _, tc := range []struct {
+		typ  arrow.DataType
+		want string
+	}{
+		{arrow.FixedWidthTypes.Boolean, "BOOLEAN"},
+		{arrow.PrimitiveTypes.Int64, "NUMBER(38,0)"},
+		{arrow.PrimitiveTypes.Float64, "FLOAT"},
+		{arrow.BinaryTypes.String, "VARCHAR"},
+		{arrow.BinaryTypes.Binary, "BINARY"},
+		{arrow.FixedWidthTypes.Date32, "DATE"},
+		{&arrow.TimestampType{Unit: arrow.Microsecond, TimeZone: "UTC"}, "TIMESTAMP_TZ"},
+		{&arrow.TimestampType{Unit: arrow.Microsecond}, "TIMESTAMP_NTZ"},
+		{&arrow.Decimal128Type{Precision: 38, Scale: 9}, "NUMBER(38,9)"},
+		{arrow.FixedWidthTypes.Time64us, "TIME(6)"},
+		{arrow.FixedWidthTypes.Time32s, "TIME(0)"},
+		{arrow.FixedWidthTypes.Time64ns, "TIME(9)"},
+		{arrow.FixedWidthTypes.MonthDayNanoInterval, "VARCHAR"},
+		{arrow.FixedWidthTypes.Duration_us, "VARCHAR"},
+		{addressType, "OBJECT"},
+		{arrow.StructOf(), "OBJECT"},
+	} {
+		got, err := SnowflakeType(tc.typ)
+		if err != nil || got != tc.want {
+			t.Errorf("SnowflakeType(%s) = %q, %v, want %q", tc.typ, got, err, tc.want)
+		}
+	}
+	if _, err := SnowflakeType(&arrow.Decimal128Type{Precision: 39}); err == nil {
+		t.Error("SnowflakeType(decimal(39, 0)) succeeded, want an error")
+	}
+}
+
+// TestStructWrittenAsParquetGroup checks that a struct column is written as
+// a Parquet group, which COPY loads into the OBJECT column it maps to.
+func TestStructWrittenAsParquetGroup(t *testing.T) {
+	b := array.NewRecordBuilder(memory.DefaultAllocator, arrow.NewSchema([]arrow.Field{{Name: "address", Type: addressType, Nullable: true}}, nil))
+	defer b.Release()
+	sb := b.Field(0).(*array.StructBuilder)
+	sb.Append(true)
+	sb.FieldBuilder(0).(*array.StringBuilder).Append("Oslo")
+	geo := sb.FieldBuilder(1).(*array.StructBuilder)
+	geo.Append(true)
+	geo.FieldBuilder(0).(*array.Float64Builder).Append(59.9)
+	geo.FieldBuilder(1).(*array.Float64Builder).Append(10.7)
+	sb.AppendNull()
+	rec := b.NewRecord()
+	defer rec.Release()
+
+	path := filepath.Join(t.TempDir(), "struct.parquet")
+	if err := NewClient("", zap.NewNop()).WriteArrowRecordToParquet(context.Background(), rec, path); err != nil {
+		t.Fatal(err)
+	}
+	rdr, err := file.OpenParquetFile(path, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rdr.Close()
+	if got := rdr.MetaData().Schema.Root().Field(0).Type(); got != schema.Group {
+		t.Errorf("address written as a Parquet %v node, want a group", got)
+	}
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{}, memory.DefaultAllocator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := fr.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !arrow.TypeEqual(got.Field(0).Type, addressType) {
+		t.Errorf("address read back as %s, want %s", got.Field(0).Type, addressType)
+	}
+}
@@ -0,0 +1,119 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"go.uber.org/zap"
+)
+
+// RejectedRow is a source row COPY skipped under ON_ERROR = CONTINUE, as
+// reported by the VALIDATE table function.
+type RejectedRow struct {
+	Table      string    `json:"table"`
+	File       string    `json:"file"`
+	RowNumber  int64     `json:"row_number"`
+	Column     string    `json:"column,omitempty"`
+	Error      string    `json:"error"`
+	Record     string    `json:"rejected_record,omitempty"`
+	RejectedAt time.Time `json:"rejected_at"`
+}
+
+// DeadLetterSink receives the rows a COPY rejected so they can be inspected
+// and reprocessed instead of being silently dropped.
+type DeadLetterSink interface {
+	WriteRejected(ctx context.Context, rows []RejectedRow) error
+}
+
+// rejectedAny reports whether COPY rejected rows from any file.
+func rejectedAny(results []CopyFileResult) bool {
+	for _, r := range results {
+		if r.ErrorsSeen > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// collectRejected reads the rows rejected by the last COPY on stmt's
+// connection and hands them to the dead-letter sink.
+func (c *Client) collectRejected(ctx context.Context, stmt adbc.Statement) error {
+	query := fmt.Sprintf("SELECT * FROM TABLE(VALIDATE(%s, JOB_ID => '_last'))", c.quoteIdentifier(c.copyTarget()))
+	if err := stmt.SetSqlQuery(query); err != nil {
+		return fmt.Errorf("failed to set SQL query: %w", err)
+	}
+	rdr, _, err := stmt.ExecuteQuery(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read rejected rows: %w", err)
+	}
+	defer rdr.Release()
+	rows, err := readRows(rdr)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	now := time.Now().In(c.location())
+	rejected := make([]RejectedRow, 0, len(rows))
+	for _, row := range rows {
+		n, _ := strconv.ParseInt(row["row_number"], 10, 64)
+		rejected = append(rejected, RejectedRow{
+			Table:      c.TargetTable,
+			File:       row["file"],
+			RowNumber:  n,
+			Column:     row["column_name"],
+			Error:      row["error"],
+			Record:     row["rejected_record"],
+			RejectedAt: now,
+		})
+	}
+	if err := c.DeadLetter.WriteRejected(ctx, rejected); err != nil {
+		return fmt.Errorf("failed to write %d rejected rows to the dead-letter sink: %w", len(rejected), err)
+	}
+	c.logger(ctx).Warn("Rejected rows written to the dead-letter sink", zap.Int("rows", len(rejected)))
+	return nil
+}
+
+// TableDeadLetter is a DeadLetterSink that inserts rejected rows into a
+// Snowflake table, created on first use.
+type TableDeadLetter struct {
+	client *Client
+	table  string
+
+	once sync.Once
+	err  error
+}
+
+// NewTableDeadLetter returns a sink writing to table through client.
+func NewTableDeadLetter(client *Client, table string) *TableDeadLetter {
+	return &TableDeadLetter{client: client, table: table}
+}
+
+// WriteRejected inserts the rows into the dead-letter table.
+func (d *TableDeadLetter) WriteRejected(ctx context.Context, rows []RejectedRow) error {
+	table := d.client.quoteIdentifier(d.table)
+	d.once.Do(func() {
+		_, d.err = d.client.execUpdate(ctx, fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s ("+
+			"TABLE_NAME VARCHAR, FILE VARCHAR, ROW_NUMBER NUMBER, COLUMN_NAME VARCHAR, "+
+			"ERROR VARCHAR, REJECTED_RECORD VARCHAR, REJECTED_AT TIMESTAMP_TZ)", table))
+	})
+	if d.err != nil {
+		return fmt.Errorf("failed to create dead-letter table %s: %w", d.table, d.err)
+	}
+
+	values := make([]string, 0, len(rows))
+	for _, r := range rows {
+		values = append(values, fmt.Sprintf("('%s', '%s', %d, '%s', '%s', '%s', '%s')",
+			escapeLiteral(r.Table), escapeLiteral(r.File), r.RowNumber, escapeLiteral(r.Column),
+			escapeLiteral(r.Error), escapeLiteral(r.Record), r.RejectedAt.Format(time.RFC3339Nano)))
+	}
+	_, err := d.client.execUpdate(ctx, fmt.Sprintf("INSERT INTO %s VALUES %s", table, strings.Join(values, ", ")))
+	return err
+}
@@ -0,0 +1,34 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// DeduplicateTarget rewrites the COPY target table keeping one row per
+// combination of the key columns. Which row of a duplicate group survives is
+// unspecified. The whole table is rewritten, so this also removes duplicates
+// that were already there.
+func (c *Client) DeduplicateTarget(ctx context.Context, keys []string) error {
+	if err := c.checkTargetTable(); err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("deduplication needs at least one key column")
+	}
+	quoted := make([]string, len(keys))
+	for i, key := range keys {
+		quoted[i] = c.Quoting.Quote(key)
+	}
+	table := c.quoteIdentifier(c.TargetTable)
+	cols := strings.Join(quoted, ", ")
+	query := fmt.Sprintf("INSERT OVERWRITE INTO %s SELECT * FROM %s QUALIFY ROW_NUMBER() OVER (PARTITION BY %s ORDER BY %s) = 1", table, table, cols, cols)
+	if _, err := c.execUpdate(ctx, query); err != nil {
+		return fmt.Errorf("failed to deduplicate %s: %w", c.TargetTable, err)
+	}
+	c.logger(ctx).Info("Target table deduplicated", zap.Strings("keys", keys))
+	return nil
+}
@@ -0,0 +1,70 @@
+package snowflake
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+var (
+	// defaultFunctions maps the argument-less BigQuery functions allowed in
+	// column defaults to their Snowflake equivalents. CURRENT_DATETIME is UTC
+	// in BigQuery, as SYSDATE is in Snowflake.
+	defaultFunctions = map[string]string{
+		"CURRENT_TIMESTAMP": "CURRENT_TIMESTAMP()",
+		"CURRENT_DATE":      "CURRENT_DATE()",
+		"CURRENT_TIME":      "CURRENT_TIME()",
+		"CURRENT_DATETIME":  "SYSDATE()",
+		"GENERATE_UUID":     "UUID_STRING()",
+	}
+	defaultCallPattern   = regexp.MustCompile(`^([A-Za-z_]+)\s*(?:\(\s*\))?$`)
+	defaultNumberPattern = regexp.MustCompile(`^[+-]?(?:\d+(?:\.\d*)?|\.\d+)(?:[eE][+-]?\d+)?$`)
+	defaultDatePattern   = regexp.MustCompile(`(?i)^DATE\s+('[^'\\]*'|"[^"\\]*")$`)
+	defaultStringPattern = regexp.MustCompile(`^(?:'[^'\\]*'|"[^"\\]*")$`)
+)
+
+// TranslateDefault converts a BigQuery column default expression into a
+// Snowflake DEFAULT expression. It handles NULL, boolean, numeric, string,
+// and DATE literals and the current date/time and UUID functions; ok is false
+// for anything else, such as expressions or escaped strings, which should be
+// left out of the DDL rather than guessed at.
+func TranslateDefault(expr string) (sql string, ok bool) {
+	expr = strings.TrimSpace(expr)
+	upper := strings.ToUpper(expr)
+	switch {
+	case upper == "NULL" || upper == "TRUE" || upper == "FALSE":
+		return upper, true
+	case defaultNumberPattern.MatchString(expr):
+		return expr, true
+	case defaultStringPattern.MatchString(expr):
+		return "'" + escapeLiteral(expr[1:len(expr)-1]) + "'", true
+	}
+	if m := defaultDatePattern.FindStringSubmatch(expr); m != nil {
+		// Snowflake converts the string when the column is a DATE.
+		return "'" + escapeLiteral(m[1][1:len(m[1])-1]) + "'", true
+	}
+	if m := defaultCallPattern.FindStringSubmatch(expr); m != nil {
+		// BigQuery allows CURRENT_TIMESTAMP and friends without parentheses.
+		if fn, ok := defaultFunctions[strings.ToUpper(m[1])]; ok {
+			return fn, true
+		}
+	}
+	return "", false
+}
+
+// TranslateDefaults applies TranslateDefault to BigQuery column defaults keyed
+// by column name, returning the Snowflake defaults for Client.ColumnDefaults
+// and a description of each one that was skipped, sorted by column.
+func TranslateDefaults(exprs map[string]string) (defaults map[string]string, skipped []string) {
+	defaults = make(map[string]string, len(exprs))
+	for col, expr := range exprs {
+		if sql, ok := TranslateDefault(expr); ok {
+			defaults[col] = sql
+		} else {
+			skipped = append(skipped, fmt.Sprintf("%s DEFAULT %s", col, expr))
+		}
+	}
+	sort.Strings(skipped)
+	return defaults, skipped
+}
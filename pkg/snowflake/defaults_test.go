@@ -0,0 +1,68 @@
+package snowflake
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+func TestTranslateDefault(t *testing.T) {
+	for _, tc := range []struct {
+		expr string
+		want string
+		ok   bool
+	}{
+		{"NULL", "NULL", true},
+		{"true", "TRUE", true},
+		{"42", "42", true},
+		{"-1.5e3", "-1.5e3", true},
+		{"'open'", "'open'", true},
+		{`"it's"`, `'it\'s'`, true},
+		{"DATE '2024-01-31'", "'2024-01-31'", true},
+		{"CURRENT_TIMESTAMP", "CURRENT_TIMESTAMP()", true},
+		{"current_timestamp()", "CURRENT_TIMESTAMP()", true},
+		{"CURRENT_DATETIME()", "SYSDATE()", true},
+		{"GENERATE_UUID()", "UUID_STRING()", true},
+		{"SESSION_USER()", "", false},
+		{"1 + 1", "", false},
+		{`'a\'b'`, "", false},
+		{"[1, 2]", "", false},
+	} {
+		got, ok := TranslateDefault(tc.expr)
+		if got != tc.want || ok != tc.ok {
+			t.Errorf("TranslateDefault(%q) = %q, %v, want %q, %v", tc.expr, got, ok, tc.want, tc.ok)
+		}
+	}
+}
+
+func TestTranslateDefaults(t *testing.T) {
+	defaults, skipped := TranslateDefaults(map[string]string{
+		"status":  "'new'",
+		"created": "CURRENT_TIMESTAMP()",
+		"total":   "price * qty",
+		"id":      "SESSION_USER()",
+	})
+	want := map[string]string{"status": "'new'", "created": "CURRENT_TIMESTAMP()"}
+	if !reflect.DeepEqual(defaults, want) {
+		t.Errorf("defaults = %v, want %v", defaults, want)
+	}
+	if wantSkipped := []string{"id DEFAULT SESSION_USER()", "total DEFAULT price * qty"}; !reflect.DeepEqual(skipped, wantSkipped) {
+		t.Errorf("skipped = %q, want %q", skipped, wantSkipped)
+	}
+}
+
+func TestCreateTableSQLDefaults(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "ID", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "STATUS", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	ddl, err := CreateTableSQL("ORDERS", schema, DDLOptions{Defaults: map[string]string{"STATUS": "'new'"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(ddl, "STATUS VARCHAR DEFAULT 'new'") || strings.Contains(ddl, "ID NUMBER(38,0) DEFAULT") {
+		t.Errorf("DDL doesn't carry exactly the STATUS default:\n%s", ddl)
+	}
+}
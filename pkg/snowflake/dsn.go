@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// DSNComponents are the parts of a Snowflake DSN of the form
+// "user:password@account/database/schema?warehouse=wh&role=r". Database,
+// schema, warehouse, and role may also be given as query parameters, as
+// gosnowflake.DSN writes them.
+type DSNComponents struct {
+	User      string
+	Password  string
+	Account   string // The account identifier, or the host and port.
+	Database  string
+	Schema    string
+	Warehouse string
+	Role      string
+	Params    url.Values // Every query parameter.
+}
+
+// ParseDSN checks that dsn is a well-formed Snowflake DSN and returns its
+// parts. The password may be omitted when an authenticator parameter selects
+// another way to sign in. The errors say which part is missing or malformed;
+// they never include the password.
+func ParseDSN(dsn string) (*DSNComponents, error) {
+	if strings.TrimSpace(dsn) == "" {
+		return nil, errors.New("snowflake DSN is empty")
+	}
+	at := strings.LastIndex(dsn, "@")
+	if at < 0 {
+		return nil, errors.New(`snowflake DSN has no "@" between the credentials and the account (want user:password@account/database/schema)`)
+	}
+	creds, rest := dsn[:at], dsn[at+1:]
+	rest, query, _ := strings.Cut(rest, "?")
+	params, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("snowflake DSN has malformed query parameters: %w", err)
+	}
+
+	var d DSNComponents
+	d.Params = params
+	user, password, hasPassword := strings.Cut(creds, ":")
+	if d.User, err = url.QueryUnescape(user); err != nil {
+		return nil, fmt.Errorf("snowflake DSN user is not properly escaped: %w", err)
+	}
+	if d.User == "" {
+		return nil, errors.New("snowflake DSN has no user before the \"@\"")
+	}
+	if hasPassword {
+		if d.Password, err = url.QueryUnescape(password); err != nil {
+			return nil, errors.New("snowflake DSN password is not properly escaped (escape special characters with url.QueryEscape)")
+		}
+	}
+	if d.Password == "" && params.Get("authenticator") == "" {
+		return nil, errors.New("snowflake DSN has no password after the user (user:password@...) and no authenticator parameter")
+	}
+
+	parts := strings.Split(rest, "/")
+	if len(parts) > 3 {
+		return nil, fmt.Errorf("snowflake DSN path %q has more than account/database/schema", rest)
+	}
+	d.Account = parts[0]
+	if d.Account == "" {
+		return nil, errors.New("snowflake DSN has no account after the \"@\"")
+	}
+	if len(parts) > 1 {
+		d.Database = parts[1]
+	}
+	if len(parts) > 2 {
+		d.Schema = parts[2]
+	}
+	if d.Database == "" {
+		d.Database = params.Get("database")
+	}
+	if d.Schema == "" {
+		d.Schema = params.Get("schema")
+	}
+	if d.Schema != "" && d.Database == "" {
+		return nil, errors.New("snowflake DSN names a schema but no database")
+	}
+	d.Warehouse = params.Get("warehouse")
+	d.Role = params.Get("role")
+	return &d, nil
+}
+
+// Validate checks the client's DSN with ParseDSN, so a malformed one fails
+// before anything connects.
+func (c *Client) Validate() error {
+	_, err := ParseDSN(c.DSN)
+	return err
+}
@@ -0,0 +1,59 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrStageNotFound is returned when Snowflake reports that a stage does not exist
+// or the current role is not authorized to use it.
+type ErrStageNotFound struct {
+	Stage string
+	Err   error
+}
+
+func (e *ErrStageNotFound) Error() string {
+	return fmt.Sprintf("snowflake stage %s does not exist or is not authorized (create it with Client.EnsureStage or set CreateStageIfMissing): %v", e.Stage, e.Err)
+}
+
+func (e *ErrStageNotFound) Unwrap() error {
+	return e.Err
+}
+
+// isStageNotFound reports whether err is Snowflake's "Stage '...' does not exist or not authorized" error.
+func isStageNotFound(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "stage") && strings.Contains(msg, "does not exist or not authorized")
+}
+
+// ErrNoTargetTable is returned by operations on the target table when
+// Client.TargetTable is unset.
+var ErrNoTargetTable = errors.New("no Snowflake target table configured")
+
+// ErrCopyFailed is returned when Snowflake fails a COPY statement outright,
+// so it loaded nothing. COPYs that load some files but not others report
+// *ErrLoadErrors or per-file results instead.
+var ErrCopyFailed = errors.New("failed to execute COPY command")
+
+// ErrLoadErrors is returned by LoadArrowIntoSnowflake when COPY failed to load
+// some files or rejected rows, and by a pipeline transfer when files still
+// fail after its last COPY attempt. Files holds the results of those files.
+type ErrLoadErrors struct {
+	Files []CopyFileResult
+}
+
+func (e *ErrLoadErrors) Error() string {
+	var rejected int64
+	for _, f := range e.Files {
+		rejected += f.ErrorsSeen
+	}
+	msg := fmt.Sprintf("COPY reported %d rejected rows in %d files", rejected, len(e.Files))
+	if len(e.Files) > 0 && e.Files[0].FirstError != "" {
+		msg += fmt.Sprintf("; first in %s: %s", e.Files[0].File, e.Files[0].FirstError)
+	}
+	return msg
+}
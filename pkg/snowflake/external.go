@@ -0,0 +1,175 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/storage/v1"
+)
+
+// ErrExternalStage is returned when a PUT targets an external stage, which
+// only accepts files written to its bucket directly.
+var ErrExternalStage = errors.New("PUT only uploads to internal stages; set StageConfig.External to write to an external stage's bucket")
+
+// isExternalStagePut reports whether err is Snowflake rejecting a PUT to an
+// external stage.
+func isExternalStagePut(err error) bool {
+	return err != nil && strings.Contains(strings.ToLower(err.Error()), "external stage")
+}
+
+// external reports whether the stage is an external one whose files are
+// written to its bucket instead of PUT.
+func (s StageConfig) external() bool {
+	return s.External != ""
+}
+
+// validateExternal checks External and that the stage can be external.
+func (s StageConfig) validateExternal() error {
+	if !s.external() {
+		return nil
+	}
+	if name := s.name(); name == "~" || strings.HasPrefix(name, "%") {
+		return fmt.Errorf("stage %q is internal and can't have an external URL", name)
+	}
+	bucket, _, _ := strings.Cut(strings.TrimPrefix(s.External, "gs://"), "/")
+	if !strings.HasPrefix(s.External, "gs://") || bucket == "" {
+		return fmt.Errorf("external stage URL %q is not a gs:// URL; only Cloud Storage external stages are supported", s.External)
+	}
+	if s.StorageIntegration != "" && !isQualifiedIdentifier(s.StorageIntegration) {
+		return fmt.Errorf("storage integration name %q is not a valid Snowflake identifier", s.StorageIntegration)
+	}
+	return nil
+}
+
+// externalObject returns the bucket and object name of a file staged at
+// staged, a path relative to the stage as returned by StagedFile.
+func (s StageConfig) externalObject(staged string) (bucket, object string) {
+	bucket, prefix, _ := strings.Cut(strings.TrimPrefix(s.External, "gs://"), "/")
+	return bucket, strings.TrimPrefix(path.Join(prefix, staged), "/")
+}
+
+// storageService returns the client's Cloud Storage service, creating it on
+// first use with StorageOptions.
+func (c *Client) storageService(ctx context.Context) (*storage.Service, error) {
+	c.gcsMu.Lock()
+	defer c.gcsMu.Unlock()
+	if c.gcs == nil {
+		svc, err := storage.NewService(ctx, c.StorageOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Cloud Storage client: %w", err)
+		}
+		c.gcs = svc
+	}
+	return c.gcs, nil
+}
+
+// uploadExternal writes filePath to the external stage's bucket where COPY
+// finds it at stagePath, retrying and verifying according to Upload.
+func (c *Client) uploadExternal(ctx context.Context, filePath, stagePath string) error {
+	return c.uploadObject(ctx, filePath, stagePath, func() (io.ReadCloser, int64, error) {
+		f, err := os.Open(filePath)
+		if err != nil {
+			return nil, 0, err
+		}
+		info, err := f.Stat()
+		if err != nil {
+			f.Close()
+			return nil, 0, err
+		}
+		return f, info.Size(), nil
+	})
+}
+
+// uploadObject writes the object opened by open to the external stage's
+// bucket as the file name, where COPY finds it at stagePath, retrying and
+// verifying according to Upload. open is called again for each attempt.
+func (c *Client) uploadObject(ctx context.Context, name, stagePath string, open func() (io.ReadCloser, int64, error)) error {
+	bucket, object := c.Stage.externalObject(StagedFile(stagePath, name))
+	url := fmt.Sprintf("gs://%s/%s", bucket, object)
+	if c.sqlOnly(fmt.Sprintf("-- upload %s to %s", name, url)) {
+		return nil
+	}
+	svc, err := c.storageService(ctx)
+	if err != nil {
+		return err
+	}
+
+	attempts := max(c.Upload.MaxAttempts, 1)
+	backoff := c.Upload.backoff()
+	for attempt := 1; ; attempt++ {
+		err := c.writeObject(ctx, svc, open, bucket, object)
+		if err == nil {
+			break
+		}
+		if attempt >= attempts || ctx.Err() != nil || !retryableObjectWrite(err) {
+			return err
+		}
+		pause := backoff.Pause()
+		c.logger(ctx).Warn("Retrying Parquet upload", zap.String("file", name), zap.Int("attempt", attempt),
+			zap.Duration("backoff", pause), zap.Error(err))
+		t := time.NewTimer(pause)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
+	}
+
+	c.logger(ctx).Info("Parquet file successfully uploaded to external stage",
+		zap.String("file", name), zap.String("url", url))
+	return nil
+}
+
+// errObjectSize is returned by writeObject when verification finds an object
+// of the wrong size.
+var errObjectSize = errors.New("uploaded object has the wrong size")
+
+// retryableObjectWrite reports whether a failed object write may succeed if
+// repeated: Cloud Storage was throttling (429) or failing (5xx), the
+// connection broke, or verification found a short object. Other API errors,
+// such as bad credentials or a missing bucket, won't change on a retry.
+func retryableObjectWrite(err error) bool {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return apiErr.Code == http.StatusTooManyRequests || apiErr.Code >= http.StatusInternalServerError
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, errObjectSize)
+}
+
+// writeObject uploads one object to bucket/object, checking with
+// Upload.Verify that it landed with the size open reports.
+func (c *Client) writeObject(ctx context.Context, svc *storage.Service, open func() (io.ReadCloser, int64, error), bucket, object string) error {
+	r, size, err := open()
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+	obj := &storage.Object{Name: object, ContentType: "application/vnd.apache.parquet"}
+	written, err := svc.Objects.Insert(bucket, obj).Media(r).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to write gs://%s/%s: %w", bucket, object, err)
+	}
+	if !c.Upload.Verify {
+		return nil
+	}
+	got, err := svc.Objects.Get(bucket, object).Generation(written.Generation).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("failed to verify gs://%s/%s: %w", bucket, object, err)
+	}
+	if got.Size != uint64(size) {
+		return fmt.Errorf("%w: gs://%s/%s holds %d bytes, want %d", errObjectSize, bucket, object, got.Size, size)
+	}
+	return nil
+}
@@ -0,0 +1,127 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go.uber.org/zap"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+// newExternalClient returns a client writing to an external stage whose bucket
+// is served by handler, and a counter of the object writes it received.
+func newExternalClient(t *testing.T, handler func(attempt int32, w http.ResponseWriter)) (*Client, *atomic.Int32) {
+	t.Helper()
+	var writes atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		handler(writes.Add(1), w)
+	}))
+	t.Cleanup(srv.Close)
+
+	c := NewClient("", zap.NewNop())
+	c.Stage = StageConfig{Name: "EXT", External: "gs://bucket/prefix"}
+	c.StorageOptions = []option.ClientOption{option.WithEndpoint(srv.URL + "/storage/v1/"), option.WithoutAuthentication()}
+	c.Upload = UploadOptions{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	return c, &writes
+}
+
+// uploadTestObject uploads a small object named file.parquet.
+func uploadTestObject(ctx context.Context, c *Client) error {
+	return c.uploadObject(ctx, "file.parquet", "@EXT", func() (io.ReadCloser, int64, error) {
+		return io.NopCloser(strings.NewReader("PAR1")), 4, nil
+	})
+}
+
+func writeObjectResponse(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "application/json")
+	io.WriteString(w, `{"name": "prefix/file.parquet", "generation": "1", "size": "4"}`)
+}
+
+func TestUploadObjectRetries(t *testing.T) {
+	for _, tc := range []struct {
+		name       string
+		failure    func(w http.ResponseWriter)
+		wantWrites int32
+		wantErr    bool
+	}{
+		{"throttled", func(w http.ResponseWriter) { http.Error(w, "slow down", http.StatusTooManyRequests) }, 2, false},
+		{"server error", func(w http.ResponseWriter) { http.Error(w, "unavailable", http.StatusServiceUnavailable) }, 2, false},
+		{"dropped connection", func(w http.ResponseWriter) {
+			conn, _, _ := w.(http.Hijacker).Hijack()
+			conn.Close()
+		}, 2, false},
+		{"unauthenticated", func(w http.ResponseWriter) { http.Error(w, "no", http.StatusUnauthorized) }, 1, true},
+		{"forbidden", func(w http.ResponseWriter) { http.Error(w, "no", http.StatusForbidden) }, 1, true},
+		{"bucket not found", func(w http.ResponseWriter) { http.Error(w, "no bucket", http.StatusNotFound) }, 1, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c, writes := newExternalClient(t, func(attempt int32, w http.ResponseWriter) {
+				if attempt == 1 {
+					tc.failure(w)
+					return
+				}
+				writeObjectResponse(w)
+			})
+			err := uploadTestObject(context.Background(), c)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("uploadObject() = %v, want error %t", err, tc.wantErr)
+			}
+			if got := writes.Load(); got != tc.wantWrites {
+				t.Errorf("%d writes, want %d", got, tc.wantWrites)
+			}
+			var apiErr *googleapi.Error
+			if tc.wantErr && !errors.As(err, &apiErr) {
+				t.Errorf("err = %v, want a googleapi.Error", err)
+			}
+		})
+	}
+}
+
+func TestUploadObjectBackoffHonorsContext(t *testing.T) {
+	c, writes := newExternalClient(t, func(_ int32, w http.ResponseWriter) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	})
+	c.Upload = UploadOptions{MaxAttempts: 5, InitialBackoff: time.Hour, MaxBackoff: time.Hour}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := uploadTestObject(ctx, c); err == nil {
+		t.Fatal("uploadObject succeeded")
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("uploadObject returned after %s, want promptly after cancellation", elapsed)
+	}
+	// The backoff pauses between attempts instead of retrying at once.
+	if got := writes.Load(); got != 1 {
+		t.Errorf("%d writes before the context expired, want 1", got)
+	}
+}
+
+func TestRetryableObjectWrite(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{&googleapi.Error{Code: http.StatusTooManyRequests}, true},
+		{&googleapi.Error{Code: http.StatusBadGateway}, true},
+		{&googleapi.Error{Code: http.StatusBadRequest}, false},
+		{&googleapi.Error{Code: http.StatusPreconditionFailed}, false},
+		{io.ErrUnexpectedEOF, true},
+		{errObjectSize, true},
+		{errors.New("open file.parquet: no such file or directory"), false},
+	} {
+		if got := retryableObjectWrite(tc.err); got != tc.want {
+			t.Errorf("retryableObjectWrite(%v) = %t, want %t", tc.err, got, tc.want)
+		}
+	}
+}
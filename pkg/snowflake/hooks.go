@@ -0,0 +1,60 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+
+	"go.uber.org/zap"
+)
+
+// StatementResult is the outcome of one statement run by ExecSQL.
+type StatementResult struct {
+	SQL          string `json:"sql"`
+	RowsAffected int64  `json:"rows_affected"` // -1 if Snowflake doesn't report it.
+	Error        string `json:"error,omitempty"`
+}
+
+// ExecSQL runs arbitrary statements in order on a single connection, stopping
+// at the first failure, and returns the result of each statement attempted.
+// The connection is in autocommit mode, so each statement commits on its own
+// unless the list opens a transaction itself with BEGIN and ends it with
+// COMMIT. In PrintSQLOnly mode the statements are printed instead.
+func (c *Client) ExecSQL(ctx context.Context, stmts []string) ([]StatementResult, error) {
+	if len(stmts) == 0 {
+		return nil, nil
+	}
+	results := make([]StatementResult, 0, len(stmts))
+	if c.PrintSQLOnly {
+		for _, query := range stmts {
+			c.sqlOnly(query)
+			results = append(results, StatementResult{SQL: query, RowsAffected: -1})
+		}
+		return results, nil
+	}
+
+	conn, release, err := c.connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	stmt, err := conn.NewStatement()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Snowflake statement: %w", err)
+	}
+	defer stmt.Close()
+
+	for _, query := range stmts {
+		if err := stmt.SetSqlQuery(query); err != nil {
+			return results, fmt.Errorf("failed to set SQL query: %w", err)
+		}
+		n, err := stmt.ExecuteUpdate(ctx)
+		if err != nil {
+			results = append(results, StatementResult{SQL: query, RowsAffected: -1, Error: err.Error()})
+			return results, fmt.Errorf("failed to execute %q: %w", query, err)
+		}
+		results = append(results, StatementResult{SQL: query, RowsAffected: n})
+	}
+	c.logger(ctx).Info("SQL executed", zap.Int("statements", len(stmts)))
+	return results, nil
+}
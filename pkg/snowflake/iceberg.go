@@ -0,0 +1,88 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// IcebergOptions configures the Snowflake-managed Iceberg tables created with
+// TableIceberg. Their data lives on an external volume in the customer's
+// cloud storage rather than in Snowflake-managed storage.
+type IcebergOptions struct {
+	// ExternalVolume names the external volume the table's data and metadata
+	// are written to. Required.
+	ExternalVolume string
+	// Catalog is the Iceberg catalog; only Snowflake-managed tables can be
+	// loaded with COPY. Defaults to "SNOWFLAKE".
+	Catalog string
+	// BaseLocation is the path under the external volume for the table's
+	// files. Defaults to the table name.
+	BaseLocation string
+}
+
+// clauses renders the CREATE ICEBERG TABLE options for table.
+func (o IcebergOptions) clauses(table string) string {
+	catalog := o.Catalog
+	if catalog == "" {
+		catalog = "SNOWFLAKE"
+	}
+	location := o.BaseLocation
+	if location == "" {
+		location = table
+	}
+	return fmt.Sprintf("CATALOG = '%s' EXTERNAL_VOLUME = '%s' BASE_LOCATION = '%s'",
+		escapeLiteral(catalog), escapeLiteral(o.ExternalVolume), escapeLiteral(location))
+}
+
+// IcebergType returns the Snowflake column type used for an Arrow data type in
+// an Iceberg table. It follows SnowflakeType, restricted to the types Iceberg
+// can store: integers map to the NUMBER types of Iceberg's int and long,
+// timestamps and times have microsecond precision, and TIMESTAMP_TZ, which
+// Iceberg lacks, becomes TIMESTAMP_LTZ.
+func IcebergType(dt arrow.DataType) (string, error) {
+	switch dt.ID() {
+	case arrow.INT8, arrow.INT16, arrow.INT32, arrow.UINT8, arrow.UINT16:
+		return "NUMBER(10,0)", nil
+	case arrow.INT64, arrow.UINT32:
+		return "NUMBER(19,0)", nil
+	case arrow.UINT64:
+		return "NUMBER(20,0)", nil
+	case arrow.TIMESTAMP:
+		if dt.(*arrow.TimestampType).TimeZone != "" {
+			return "TIMESTAMP_LTZ(6)", nil
+		}
+		return "TIMESTAMP_NTZ(6)", nil
+	case arrow.TIME32, arrow.TIME64:
+		return "TIME(6)", nil
+	case arrow.STRUCT:
+		return "", fmt.Errorf("RECORD columns aren't supported in Iceberg tables")
+	}
+	return SnowflakeType(dt)
+}
+
+// checkIcebergVolume verifies that Iceberg tables can be created: the external
+// volume is configured and exists, and the account supports Iceberg.
+func (c *Client) checkIcebergVolume(ctx context.Context) error {
+	if c.Iceberg.ExternalVolume == "" {
+		return fmt.Errorf("Iceberg tables need an external volume")
+	}
+	if c.PrintSQLOnly {
+		return nil
+	}
+	_, err := c.queryRows(ctx, fmt.Sprintf("DESC EXTERNAL VOLUME %s", c.quoteIdentifier(c.Iceberg.ExternalVolume)))
+	if err == nil {
+		return nil
+	}
+	msg := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(msg, "does not exist"):
+		return fmt.Errorf("external volume %s does not exist or is not authorized: %w", c.Iceberg.ExternalVolume, err)
+	case strings.Contains(msg, "syntax error"), strings.Contains(msg, "unsupported feature"):
+		return fmt.Errorf("Iceberg tables aren't available on this Snowflake account: %w", err)
+	default:
+		return fmt.Errorf("failed to describe external volume %s: %w", c.Iceberg.ExternalVolume, err)
+	}
+}
@@ -0,0 +1,167 @@
+package snowflake
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// IntervalFormat selects how BigQuery INTERVAL (and Arrow duration) columns
+// are written, since Snowflake has no interval column type.
+type IntervalFormat int
+
+const (
+	// IntervalISO8601 writes VARCHAR ISO-8601 durations such as "P1Y2M3DT4H5M6.5S"
+	// (the default). Each component keeps its own sign, as in BigQuery's
+	// canonical format, e.g. "P-1Y2D".
+	IntervalISO8601 IntervalFormat = iota
+	// IntervalNanos writes NUMBER(38,0) nanoseconds, which Snowflake date
+	// arithmetic can use directly. Intervals with a month or year part have no
+	// fixed length and fail the write.
+	IntervalNanos
+)
+
+// ParseIntervalFormat parses a format name: "" or "iso8601", or "nanos".
+func ParseIntervalFormat(s string) (IntervalFormat, error) {
+	switch s {
+	case "", "iso8601":
+		return IntervalISO8601, nil
+	case "nanos":
+		return IntervalNanos, nil
+	default:
+		return IntervalISO8601, fmt.Errorf("unknown interval format %q (want iso8601 or nanos)", s)
+	}
+}
+
+// isInterval reports whether dt is an Arrow interval or duration type.
+func isInterval(dt arrow.DataType) bool {
+	switch dt.ID() {
+	case arrow.DURATION, arrow.INTERVAL_MONTHS, arrow.INTERVAL_DAY_TIME, arrow.INTERVAL_MONTH_DAY_NANO:
+		return true
+	}
+	return false
+}
+
+// withIntervalsConverted returns schema with interval columns replaced by the
+// type they are written as.
+func withIntervalsConverted(schema *arrow.Schema, format IntervalFormat) *arrow.Schema {
+	fields := append([]arrow.Field{}, schema.Fields()...)
+	changed := false
+	for i, f := range fields {
+		if isInterval(f.Type) {
+			fields[i].Type = intervalTarget(format)
+			changed = true
+		}
+	}
+	if !changed {
+		return schema
+	}
+	md := schema.Metadata()
+	return arrow.NewSchema(fields, &md)
+}
+
+func intervalTarget(format IntervalFormat) arrow.DataType {
+	if format == IntervalNanos {
+		return arrow.PrimitiveTypes.Int64
+	}
+	return arrow.BinaryTypes.String
+}
+
+// intervalTransforms returns column transforms converting every interval column
+// of schema to format, for use with applyColumnTransforms.
+func intervalTransforms(schema *arrow.Schema, format IntervalFormat, mem memory.Allocator) map[string]ColumnTransform {
+	transforms := make(map[string]ColumnTransform)
+	for _, f := range schema.Fields() {
+		if isInterval(f.Type) {
+			transforms[f.Name] = func(arr arrow.Array) (arrow.Array, error) {
+				return convertInterval(arr, format, mem)
+			}
+		}
+	}
+	return transforms
+}
+
+// convertInterval converts an interval or duration array to ISO-8601 strings
+// or nanoseconds. Nulls stay null.
+func convertInterval(arr arrow.Array, format IntervalFormat, mem memory.Allocator) (arrow.Array, error) {
+	var b array.Builder
+	if format == IntervalNanos {
+		b = array.NewInt64Builder(mem)
+	} else {
+		b = array.NewStringBuilder(mem)
+	}
+	defer b.Release()
+
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		var months, days int64
+		var nanos int64
+		switch a := arr.(type) {
+		case *array.Duration:
+			unit := arr.DataType().(*arrow.DurationType).Unit
+			nanos = int64(a.Value(i)) * int64(unit.Multiplier())
+		case *array.MonthInterval:
+			months = int64(a.Value(i))
+		case *array.DayTimeInterval:
+			v := a.Value(i)
+			days, nanos = int64(v.Days), int64(v.Milliseconds)*int64(time.Millisecond)
+		case *array.MonthDayNanoInterval:
+			v := a.Value(i)
+			months, days, nanos = int64(v.Months), int64(v.Days), v.Nanoseconds
+		default:
+			return nil, fmt.Errorf("unsupported interval type %s", arr.DataType())
+		}
+
+		if format == IntervalNanos {
+			if months != 0 {
+				return nil, fmt.Errorf("interval with %d months has no fixed length in nanoseconds", months)
+			}
+			b.(*array.Int64Builder).Append(days*int64(24*time.Hour) + nanos)
+			continue
+		}
+		b.(*array.StringBuilder).Append(iso8601Interval(months, days, nanos))
+	}
+	return b.NewArray(), nil
+}
+
+// iso8601Interval formats interval parts as an ISO-8601 duration, giving each
+// non-zero component its own sign.
+func iso8601Interval(months, days, nanos int64) string {
+	var sb strings.Builder
+	sb.WriteString("P")
+	if y := months / 12; y != 0 {
+		fmt.Fprintf(&sb, "%dY", y)
+	}
+	if m := months % 12; m != 0 {
+		fmt.Fprintf(&sb, "%dM", m)
+	}
+	if days != 0 {
+		fmt.Fprintf(&sb, "%dD", days)
+	}
+	if nanos != 0 {
+		sb.WriteString("T")
+		d := time.Duration(nanos)
+		if h := d / time.Hour; h != 0 {
+			fmt.Fprintf(&sb, "%dH", h)
+		}
+		if m := (d % time.Hour) / time.Minute; m != 0 {
+			fmt.Fprintf(&sb, "%dM", m)
+		}
+		if s := d % time.Minute; s != 0 {
+			sb.WriteString(strconv.FormatFloat(s.Seconds(), 'f', -1, 64))
+			sb.WriteString("S")
+		}
+	}
+	if sb.Len() == 1 {
+		return "PT0S"
+	}
+	return sb.String()
+}
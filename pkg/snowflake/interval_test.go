@@ -0,0 +1,90 @@
+package snowflake
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestIso8601Interval(t *testing.T) {
+	for _, tc := range []struct {
+		months, days, nanos int64
+		want                string
+	}{
+		{0, 0, 0, "PT0S"},
+		{14, 3, 4*3600e9 + 5*60e9 + 6.5e9, "P1Y2M3DT4H5M6.5S"},
+		{-12, 2, 0, "P-1Y2D"},
+		{0, 0, -90e9, "PT-1M-30S"},
+		{0, 1, 1000, "P1DT0.000001S"},
+	} {
+		if got := iso8601Interval(tc.months, tc.days, tc.nanos); got != tc.want {
+			t.Errorf("iso8601Interval(%d, %d, %d) = %s, want %s", tc.months, tc.days, tc.nanos, got, tc.want)
+		}
+	}
+}
+
+func TestConvertInterval(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+	b := array.NewMonthDayNanoIntervalBuilder(mem)
+	b.Append(arrow.MonthDayNanoInterval{Days: 1, Nanoseconds: 1500})
+	b.AppendNull()
+	b.Append(arrow.MonthDayNanoInterval{Months: 13})
+	intervals := b.NewArray()
+	b.Release()
+	defer intervals.Release()
+
+	iso, err := convertInterval(intervals, IntervalISO8601, mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer iso.Release()
+	strs := iso.(*array.String)
+	if strs.Value(0) != "P1DT0.0000015S" || !strs.IsNull(1) || strs.Value(2) != "P1Y1M" {
+		t.Errorf("ISO-8601 intervals = %s", strs)
+	}
+
+	// Months have no fixed length in nanoseconds.
+	if _, err := convertInterval(intervals, IntervalNanos, mem); err == nil {
+		t.Error("converting a month interval to nanoseconds succeeded, want an error")
+	}
+
+	d := array.NewDurationBuilder(mem, &arrow.DurationType{Unit: arrow.Millisecond})
+	d.AppendValues([]arrow.Duration{1500, -2}, nil)
+	durations := d.NewArray()
+	d.Release()
+	defer durations.Release()
+	nanos, err := convertInterval(durations, IntervalNanos, mem)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer nanos.Release()
+	if got := nanos.(*array.Int64).Int64Values(); got[0] != 1_500_000_000 || got[1] != -2_000_000 {
+		t.Errorf("nanoseconds = %v, want [1500000000 -2000000]", got)
+	}
+}
+
+func TestWithIntervalsConverted(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "span", Type: arrow.FixedWidthTypes.MonthDayNanoInterval, Nullable: true},
+	}, nil)
+	for format, want := range map[IntervalFormat]arrow.DataType{
+		IntervalISO8601: arrow.BinaryTypes.String,
+		IntervalNanos:   arrow.PrimitiveTypes.Int64,
+	} {
+		got := withIntervalsConverted(schema, format)
+		if !arrow.TypeEqual(got.Field(1).Type, want) || !got.Field(1).Nullable {
+			t.Errorf("format %d: span converted to %s, want nullable %s", format, got.Field(1), want)
+		}
+		if !arrow.TypeEqual(got.Field(0).Type, arrow.PrimitiveTypes.Int64) {
+			t.Errorf("format %d: id changed to %s", format, got.Field(0).Type)
+		}
+	}
+	plain := arrow.NewSchema(schema.Fields()[:1], nil)
+	if withIntervalsConverted(plain, IntervalNanos) != plain {
+		t.Error("schema without intervals was copied")
+	}
+}
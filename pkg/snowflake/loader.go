@@ -0,0 +1,43 @@
+package snowflake
+
+import (
+	"context"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// Loader is the part of Client the transfer orchestrator depends on: staging
+// records as Parquet files and COPYing the stage into the target table. It lets
+// the orchestrator run against a fake (see package snowflaketest).
+type Loader interface {
+	// WriteArrowRecordToParquet writes a record to outputFile.
+	WriteArrowRecordToParquet(ctx context.Context, record arrow.Record, outputFile string) error
+	// UploadParquetToStage PUTs a local file to the stage.
+	UploadParquetToStage(ctx context.Context, filePath, stagePath string) error
+	// StageName returns the stage files are PUT to when stagePath is empty.
+	StageName() string
+	// BeginLoad readies the target table before the first COPY of a load.
+	BeginLoad(ctx context.Context) error
+	// CopyStaged COPYs the staged files into the target table.
+	CopyStaged(ctx context.Context) ([]CopyFileResult, error)
+	// CopyFiles COPYs only the given staged files; see StagedFile.
+	CopyFiles(ctx context.Context, files []string) ([]CopyFileResult, error)
+	// EnsureTargetTable creates the target table from schema if it is missing.
+	EnsureTargetTable(ctx context.Context, schema *arrow.Schema) error
+	// CheckTargetSchema checks an existing target table against schema.
+	CheckTargetSchema(ctx context.Context, schema *arrow.Schema) error
+	// TargetDDL computes the DDL preparing the target table for schema.
+	TargetDDL(ctx context.Context, schema *arrow.Schema, create bool) ([]string, error)
+	// PlanTarget describes the target table and the load statements for schema.
+	PlanTarget(ctx context.Context, schema *arrow.Schema, stagePath string) (TargetPlan, error)
+	// ExecDDL runs DDL statements as one batch.
+	ExecDDL(ctx context.Context, stmts []string) error
+	// ExecSQL runs statements on one connection and reports each result.
+	ExecSQL(ctx context.Context, stmts []string) ([]StatementResult, error)
+	// DeduplicateTarget keeps one row per key in the target table.
+	DeduplicateTarget(ctx context.Context, keys []string) error
+	// FitVarcharColumns widens target VARCHAR columns to the given lengths.
+	FitVarcharColumns(ctx context.Context, lengths map[string]int) (map[string]int, error)
+}
+
+var _ Loader = (*Client)(nil)
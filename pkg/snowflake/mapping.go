@@ -0,0 +1,96 @@
+package snowflake
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// ColumnMapping describes how one source column is loaded into Snowflake.
+type ColumnMapping struct {
+	Name          string
+	ArrowType     string
+	SnowflakeType string // "UNSUPPORTED" when the Arrow type has no mapping.
+	Nullable      bool
+}
+
+// SchemaMapping returns the Snowflake type each column of an Arrow schema is
+// created as, using the same mapping as the DDL generator. Interval columns
+// map to the type they are written as in format.
+func SchemaMapping(schema *arrow.Schema, format IntervalFormat) []ColumnMapping {
+	converted := withIntervalsConverted(schema, format)
+	out := make([]ColumnMapping, 0, len(schema.Fields()))
+	for i, f := range schema.Fields() {
+		typ, err := SnowflakeType(converted.Field(i).Type)
+		if err != nil {
+			typ = "UNSUPPORTED"
+		}
+		out = append(out, ColumnMapping{
+			Name:          f.Name,
+			ArrowType:     f.Type.String(),
+			SnowflakeType: typ,
+			Nullable:      f.Nullable,
+		})
+	}
+	return out
+}
+
+// MappingFormat selects how WriteSchemaMapping renders a mapping.
+type MappingFormat int
+
+const (
+	// MappingMarkdown renders a Markdown table (the default).
+	MappingMarkdown MappingFormat = iota
+	// MappingCSV renders CSV with a header row.
+	MappingCSV
+)
+
+// ParseMappingFormat converts a config string ("markdown" or "csv") into a
+// MappingFormat. An empty string selects MappingMarkdown.
+func ParseMappingFormat(s string) (MappingFormat, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "markdown", "md":
+		return MappingMarkdown, nil
+	case "csv":
+		return MappingCSV, nil
+	default:
+		return MappingMarkdown, fmt.Errorf("unknown mapping format %q (supported: markdown, csv)", s)
+	}
+}
+
+var mappingHeader = []string{"column", "arrow_type", "snowflake_type", "nullable"}
+
+// WriteSchemaMapping writes a column mapping report to w.
+func WriteSchemaMapping(w io.Writer, mappings []ColumnMapping, format MappingFormat) error {
+	rows := make([][]string, 0, len(mappings))
+	for _, m := range mappings {
+		rows = append(rows, []string{m.Name, m.ArrowType, m.SnowflakeType, fmt.Sprint(m.Nullable)})
+	}
+
+	if format == MappingCSV {
+		cw := csv.NewWriter(w)
+		cw.Write(mappingHeader)
+		cw.WriteAll(rows)
+		return cw.Error()
+	}
+
+	escape := strings.NewReplacer("|", `\|`, "\n", " ")
+	line := func(cells []string) string {
+		escaped := make([]string, len(cells))
+		for i, c := range cells {
+			escaped[i] = escape.Replace(c)
+		}
+		return "| " + strings.Join(escaped, " | ") + " |\n"
+	}
+	var b strings.Builder
+	b.WriteString(line(mappingHeader))
+	b.WriteString("| --- | --- | --- | --- |\n")
+	for _, row := range rows {
+		b.WriteString(line(row))
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,167 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// LoadMode selects how loaded rows are applied to the target table.
+type LoadMode int
+
+const (
+	// LoadAppend COPYs rows into the target table (the default).
+	LoadAppend LoadMode = iota
+	// LoadReplace truncates the target table once, in BeginLoad, before the
+	// first COPY, so the load replaces its contents.
+	LoadReplace
+	// LoadMerge COPYs rows into a transient staging table and MERGEs them
+	// into the target on CopyOptions.MergeKeys: rows with a matching key are
+	// updated and the rest inserted, so a transfer can be re-run without
+	// duplicating rows.
+	LoadMerge
+)
+
+// ParseLoadMode converts a config string ("append", "replace", or "merge")
+// into a LoadMode. An empty string selects LoadAppend.
+func ParseLoadMode(s string) (LoadMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "append":
+		return LoadAppend, nil
+	case "replace":
+		return LoadReplace, nil
+	case "merge":
+		return LoadMerge, nil
+	default:
+		return LoadAppend, fmt.Errorf("unknown load mode %q (supported: append, replace, merge)", s)
+	}
+}
+
+// mergeStagingSuffix names the staging table of a LoadMerge target.
+const mergeStagingSuffix = "_SYNC_MERGE"
+
+// copyTarget returns the table COPY loads: the target table, or its staging
+// table in LoadMerge mode.
+func (c *Client) copyTarget() string {
+	if c.Copy.Mode == LoadMerge {
+		return c.TargetTable + mergeStagingSuffix
+	}
+	return c.TargetTable
+}
+
+// BeginLoad readies the target table for a load according to Copy.Mode: in
+// LoadReplace mode it is truncated. Call it once per load, before the first
+// COPY; the transfer does so after preparing the target table.
+func (c *Client) BeginLoad(ctx context.Context) error {
+	if err := c.checkTargetTable(); err != nil {
+		return err
+	}
+	for _, stmt := range c.beginLoadStatements() {
+		if _, err := c.execUpdate(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to truncate %s: %w", c.TargetTable, err)
+		}
+		c.logger(ctx).Info("Target table truncated for replacement", zap.String("table", c.TargetTable))
+	}
+	return nil
+}
+
+// beginLoadStatements returns the statements BeginLoad runs.
+func (c *Client) beginLoadStatements() []string {
+	if c.Copy.Mode != LoadReplace {
+		return nil
+	}
+	return []string{fmt.Sprintf("TRUNCATE TABLE IF EXISTS %s", c.quoteIdentifier(c.TargetTable))}
+}
+
+// stagingStatements returns the statements that ready the LoadMerge staging
+// table before each COPY.
+func (c *Client) stagingStatements() []string {
+	staging := c.copyTarget()
+	return []string{
+		fmt.Sprintf("CREATE TRANSIENT TABLE IF NOT EXISTS %s LIKE %s", c.quoteIdentifier(staging), c.quoteIdentifier(c.TargetTable)),
+		fmt.Sprintf("DELETE FROM %s", c.quoteIdentifier(staging)),
+	}
+}
+
+// copyMerged runs a COPY into the staging table, built by copyStatement for
+// copyTarget, and MERGEs the rows it loaded into the target table. The
+// staging table is kept between COPYs and emptied with DELETE rather than
+// recreated, so Snowflake's load metadata keeps later COPYs from loading
+// files already merged. The target is described to list its columns, even in
+// PrintSQLOnly mode.
+func (c *Client) copyMerged(ctx context.Context, query string) ([]CopyFileResult, error) {
+	cols, err := c.DescribeTable(ctx, c.TargetTable)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, len(cols))
+	for i, col := range cols {
+		names[i] = col.Name
+	}
+	staging := c.copyTarget()
+	merge, err := c.mergeStatement(staging, names)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, stmt := range c.stagingStatements() {
+		if _, err := c.execUpdate(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("failed to prepare staging table %s: %w", staging, err)
+		}
+	}
+	results, err := c.copyInto(ctx, query)
+	if err != nil {
+		return results, err
+	}
+	merged, err := c.execUpdate(ctx, merge)
+	if err != nil {
+		return results, fmt.Errorf("failed to merge %s into %s: %w", staging, c.TargetTable, err)
+	}
+	c.logger(ctx).Info("Staged rows merged into target table", zap.String("table", c.TargetTable), zap.Int64("rows", merged))
+	return results, nil
+}
+
+// mergeStatement builds the MERGE of staging into the target table on
+// Copy.MergeKeys, given the target's columns. Keys match columns
+// case-insensitively. Rows whose keys are NULL never match and are inserted.
+// When the staging table holds several rows with one key, an arbitrary one
+// of them is merged.
+func (c *Client) mergeStatement(staging string, columns []string) (string, error) {
+	byName := make(map[string]string, len(columns))
+	for _, col := range columns {
+		byName[strings.ToUpper(col)] = col
+	}
+	isKey := make(map[string]bool, len(c.Copy.MergeKeys))
+	keys := make([]string, len(c.Copy.MergeKeys))
+	on := make([]string, len(c.Copy.MergeKeys))
+	for i, key := range c.Copy.MergeKeys {
+		col, ok := byName[strings.ToUpper(key)]
+		if !ok {
+			return "", fmt.Errorf("merge key %s is not a column of %s", key, c.TargetTable)
+		}
+		isKey[col] = true
+		keys[i] = c.Quoting.Quote(col)
+		on[i] = fmt.Sprintf("t.%s = s.%s", keys[i], keys[i])
+	}
+
+	var set, insert, values []string
+	for _, col := range columns {
+		q := c.Quoting.Quote(col)
+		if !isKey[col] {
+			set = append(set, fmt.Sprintf("t.%s = s.%s", q, q))
+		}
+		insert = append(insert, q)
+		values = append(values, "s."+q)
+	}
+
+	partition := strings.Join(keys, ", ")
+	query := fmt.Sprintf("MERGE INTO %s t USING (SELECT * FROM %s QUALIFY ROW_NUMBER() OVER (PARTITION BY %s ORDER BY %s) = 1) s ON %s",
+		c.quoteIdentifier(c.TargetTable), c.quoteIdentifier(staging), partition, partition, strings.Join(on, " AND "))
+	if len(set) > 0 {
+		query += " WHEN MATCHED THEN UPDATE SET " + strings.Join(set, ", ")
+	}
+	query += fmt.Sprintf(" WHEN NOT MATCHED THEN INSERT (%s) VALUES (%s)", strings.Join(insert, ", "), strings.Join(values, ", "))
+	return query, nil
+}
@@ -0,0 +1,96 @@
+package snowflake
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-adbc/go/adbc/driver/snowflake"
+	"github.com/snowflakedb/gosnowflake"
+)
+
+// NetworkConfig holds connection settings for restricted enterprise networks.
+type NetworkConfig struct {
+	// Proxy settings. The Snowflake driver has no per-connection proxy option, so
+	// the proxy is installed on the driver's shared HTTP transport and applies to
+	// every Snowflake connection in the process (BigQuery traffic is unaffected).
+	ProxyHost     string
+	ProxyPort     int
+	ProxyUser     string
+	ProxyPassword string
+
+	// OCSPFailOpen lets connections proceed when the OCSP responder can't be
+	// reached. Nil keeps the driver default (fail open).
+	OCSPFailOpen *bool
+
+	// Insecure disables OCSP certificate revocation checks entirely. It is only
+	// honored when AllowInsecure is also set, to avoid enabling it by accident.
+	Insecure      bool
+	AllowInsecure bool
+}
+
+// Validate checks that the network settings are consistent.
+func (n NetworkConfig) Validate() error {
+	if n.Insecure && !n.AllowInsecure {
+		return fmt.Errorf("insecure mode disables OCSP checks and requires AllowInsecure to be set explicitly")
+	}
+	if n.ProxyHost == "" {
+		if n.ProxyPort != 0 || n.ProxyUser != "" || n.ProxyPassword != "" {
+			return fmt.Errorf("proxy port or credentials set without a proxy host")
+		}
+		return nil
+	}
+	if n.ProxyPort <= 0 || n.ProxyPort > 65535 {
+		return fmt.Errorf("invalid proxy port %d", n.ProxyPort)
+	}
+	if n.ProxyPassword != "" && n.ProxyUser == "" {
+		return fmt.Errorf("proxy password set without a proxy user")
+	}
+	if n.Insecure {
+		// The driver switches to an internal transport without OCSP in insecure
+		// mode, which only honors the HTTPS_PROXY environment variable.
+		return fmt.Errorf("proxy settings can't be combined with insecure mode; use the HTTPS_PROXY environment variable instead")
+	}
+	return nil
+}
+
+// proxyURL builds the proxy URL, including credentials if configured.
+func (n NetworkConfig) proxyURL() *url.URL {
+	u := &url.URL{Scheme: "http", Host: net.JoinHostPort(n.ProxyHost, strconv.Itoa(n.ProxyPort))}
+	if n.ProxyUser != "" {
+		u.User = url.UserPassword(n.ProxyUser, n.ProxyPassword)
+	}
+	return u
+}
+
+// databaseOptions returns the ADBC database options for the network settings.
+func (n NetworkConfig) databaseOptions() map[string]string {
+	opts := map[string]string{}
+	if n.OCSPFailOpen != nil {
+		opts[snowflake.OptionOCSPFailOpenMode] = adbc.OptionValueDisabled
+		if *n.OCSPFailOpen {
+			opts[snowflake.OptionOCSPFailOpenMode] = adbc.OptionValueEnabled
+		}
+	}
+	if n.Insecure && n.AllowInsecure {
+		opts[snowflake.OptionSSLSkipVerify] = adbc.OptionValueEnabled
+	}
+	return opts
+}
+
+// proxyMu guards installation of the proxy on the shared driver transport.
+var proxyMu sync.Mutex
+
+// applyProxy routes the Snowflake driver's HTTP transport through the configured proxy.
+func (n NetworkConfig) applyProxy() {
+	if n.ProxyHost == "" {
+		return
+	}
+	proxyMu.Lock()
+	defer proxyMu.Unlock()
+	gosnowflake.SnowflakeTransport.Proxy = http.ProxyURL(n.proxyURL())
+}
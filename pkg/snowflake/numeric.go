@@ -0,0 +1,131 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// maxNumberPrecision is the largest precision Snowflake's NUMBER type supports.
+const maxNumberPrecision = 38
+
+// numberType matches Snowflake's NUMBER(p,s) type as reported by DESCRIBE TABLE.
+var numberType = regexp.MustCompile(`^NUMBER\((\d+),(\d+)\)$`)
+
+// NumericMismatch describes a source decimal column that doesn't fit its target
+// NUMBER column without truncation or overflow.
+type NumericMismatch struct {
+	Column                         string
+	SourcePrecision, SourceScale   int32
+	TargetPrecision, TargetScale   int32
+	WidenedPrecision, WidenedScale int32 // The smallest NUMBER that holds both; zero if none exists.
+}
+
+func (m NumericMismatch) String() string {
+	return fmt.Sprintf("column %s: source NUMBER(%d,%d) does not fit target NUMBER(%d,%d)",
+		m.Column, m.SourcePrecision, m.SourceScale, m.TargetPrecision, m.TargetScale)
+}
+
+// widenable reports whether ALTER TABLE can widen the target to hold the source.
+// Snowflake can only increase a NUMBER column's precision; its scale is fixed.
+func (m NumericMismatch) widenable() bool {
+	return m.WidenedPrecision > 0 && m.WidenedScale == m.TargetScale
+}
+
+// DecimalMismatches compares the decimal columns of a source Arrow schema with
+// the NUMBER columns of an existing target table. Columns are matched by name,
+// case-insensitively, like MATCH_BY_COLUMN_NAME. A target fits when it has at
+// least as many integer digits and at least as many fractional digits.
+func DecimalMismatches(schema *arrow.Schema, target []ColumnInfo) []NumericMismatch {
+	byName := make(map[string]ColumnInfo, len(target))
+	for _, col := range target {
+		byName[strings.ToUpper(col.Name)] = col
+	}
+
+	var out []NumericMismatch
+	for _, f := range schema.Fields() {
+		dec, ok := f.Type.(*arrow.Decimal128Type)
+		if !ok {
+			continue
+		}
+		col, ok := byName[strings.ToUpper(f.Name)]
+		if !ok {
+			continue
+		}
+		m := numberType.FindStringSubmatch(col.Type)
+		if m == nil {
+			continue
+		}
+		tp, _ := strconv.Atoi(m[1])
+		ts, _ := strconv.Atoi(m[2])
+		mismatch := NumericMismatch{
+			Column:          col.Name,
+			SourcePrecision: dec.Precision, SourceScale: dec.Scale,
+			TargetPrecision: int32(tp), TargetScale: int32(ts),
+		}
+		intDigits := max(dec.Precision-dec.Scale, mismatch.TargetPrecision-mismatch.TargetScale)
+		scale := max(dec.Scale, mismatch.TargetScale)
+		if dec.Precision-dec.Scale <= mismatch.TargetPrecision-mismatch.TargetScale && dec.Scale <= mismatch.TargetScale {
+			continue
+		}
+		if intDigits+scale <= maxNumberPrecision {
+			mismatch.WidenedPrecision, mismatch.WidenedScale = intDigits+scale, scale
+		}
+		out = append(out, mismatch)
+	}
+	return out
+}
+
+// CheckNumericFit verifies, before any data is loaded, that every decimal column
+// in the source schema fits its NUMBER column in the target table. With
+// WidenNumeric set, columns that can be widened are altered in place; otherwise
+// (or when widening is impossible) an error lists the offending columns.
+// A target table that doesn't exist yet is skipped, as is the whole check in
+// PrintSQLOnly mode.
+func (c *Client) CheckNumericFit(ctx context.Context, table string, schema *arrow.Schema) error {
+	if c.PrintSQLOnly {
+		return nil
+	}
+	cols, ok, err := c.describeIfExists(ctx, table)
+	if err != nil || !ok {
+		return err
+	}
+	alters, err := c.numericAlters(table, schema, cols)
+	if err != nil {
+		return err
+	}
+	return c.ExecDDL(ctx, alters)
+}
+
+// numericAlters returns the ALTER TABLE statements widening the described
+// NUMBER columns to fit the source decimals, or an error listing the columns
+// that can't (or, without WidenNumeric, may not) be widened.
+func (c *Client) numericAlters(table string, schema *arrow.Schema, cols []ColumnInfo) ([]string, error) {
+	var alters, problems []string
+	for _, m := range DecimalMismatches(schema, cols) {
+		if !c.WidenNumeric || !m.widenable() {
+			problems = append(problems, m.String())
+			continue
+		}
+		alters = append(alters, fmt.Sprintf("%s %s ALTER COLUMN %s SET DATA TYPE NUMBER(%d,%d)",
+			c.alterTable(), c.quoteIdentifier(table), c.Quoting.Quote(m.Column), m.WidenedPrecision, m.WidenedScale))
+	}
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("numeric overflow risk in %s: %s", table, strings.Join(problems, "; "))
+	}
+	return alters, nil
+}
+
+// hasDecimals reports whether the schema has any decimal columns.
+func hasDecimals(schema *arrow.Schema) bool {
+	for _, f := range schema.Fields() {
+		if f.Type.ID() == arrow.DECIMAL128 {
+			return true
+		}
+	}
+	return false
+}
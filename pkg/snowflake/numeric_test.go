@@ -0,0 +1,84 @@
+package snowflake
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"go.uber.org/zap"
+)
+
+// decimalSchema returns a schema with one decimal column named amount.
+func decimalSchema(precision, scale int32) *arrow.Schema {
+	return arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "amount", Type: &arrow.Decimal128Type{Precision: precision, Scale: scale}, Nullable: true},
+	}, nil)
+}
+
+func TestDecimalMismatches(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		source *arrow.Schema
+		target string
+		want   []NumericMismatch
+	}{
+		{"same type", decimalSchema(10, 2), "NUMBER(10,2)", nil},
+		{"wider target", decimalSchema(10, 2), "NUMBER(18,4)", nil},
+		{"too few integer digits", decimalSchema(12, 2), "NUMBER(10,2)", []NumericMismatch{{
+			Column: "AMOUNT", SourcePrecision: 12, SourceScale: 2, TargetPrecision: 10, TargetScale: 2,
+			WidenedPrecision: 12, WidenedScale: 2,
+		}}},
+		{"too few fractional digits", decimalSchema(10, 4), "NUMBER(10,2)", []NumericMismatch{{
+			Column: "AMOUNT", SourcePrecision: 10, SourceScale: 4, TargetPrecision: 10, TargetScale: 2,
+			WidenedPrecision: 12, WidenedScale: 4,
+		}}},
+		{"no NUMBER holds both", decimalSchema(38, 10), "NUMBER(38,30)", []NumericMismatch{{
+			Column: "AMOUNT", SourcePrecision: 38, SourceScale: 10, TargetPrecision: 38, TargetScale: 30,
+		}}},
+		{"target not NUMBER", decimalSchema(38, 9), "VARCHAR(16777216)", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			target := []ColumnInfo{{Name: "ID", Type: "NUMBER(38,0)"}, {Name: "AMOUNT", Type: tc.target, Nullable: true}}
+			if got := DecimalMismatches(tc.source, target); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("DecimalMismatches() = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestNumericAlters(t *testing.T) {
+	target := []ColumnInfo{{Name: "AMOUNT", Type: "NUMBER(10,2)", Nullable: true}}
+	for _, tc := range []struct {
+		name    string
+		source  *arrow.Schema
+		widen   bool
+		want    []string
+		wantErr string
+	}{
+		{"fits", decimalSchema(10, 2), false, nil, ""},
+		{"overflow without widening", decimalSchema(12, 2), false, nil, "column AMOUNT: source NUMBER(12,2) does not fit target NUMBER(10,2)"},
+		{"widened", decimalSchema(12, 2), true, []string{"ALTER TABLE ORDERS ALTER COLUMN AMOUNT SET DATA TYPE NUMBER(12,2)"}, ""},
+		// Snowflake can't change a NUMBER column's scale.
+		{"scale can't widen", decimalSchema(10, 4), true, nil, "source NUMBER(10,4) does not fit"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			c := NewClient("", zap.NewNop())
+			c.WidenNumeric = tc.widen
+			got, err := c.numericAlters("ORDERS", tc.source, target)
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err = %v, want one containing %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("numericAlters() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
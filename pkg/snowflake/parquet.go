@@ -0,0 +1,323 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/parquet"
+	"github.com/apache/arrow-go/v18/parquet/compress"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+	"go.uber.org/zap"
+)
+
+// ParquetCompression selects the codec Parquet files are written with.
+// Snowflake reads all of them; Snappy is fastest to write, while Zstd and Gzip
+// trade CPU for smaller uploads and staged files.
+type ParquetCompression int
+
+const (
+	// CompressionSnappy compresses with Snappy (the default).
+	CompressionSnappy ParquetCompression = iota
+	// CompressionZstd compresses with Zstandard.
+	CompressionZstd
+	// CompressionGzip compresses with Gzip.
+	CompressionGzip
+	// CompressionBrotli compresses with Brotli.
+	CompressionBrotli
+	// CompressionNone writes uncompressed pages.
+	CompressionNone
+)
+
+// ParseParquetCompression parses a codec name: "" or "snappy", "zstd",
+// "gzip", "brotli", or "none".
+func ParseParquetCompression(s string) (ParquetCompression, error) {
+	switch s {
+	case "", "snappy":
+		return CompressionSnappy, nil
+	case "zstd":
+		return CompressionZstd, nil
+	case "gzip":
+		return CompressionGzip, nil
+	case "brotli":
+		return CompressionBrotli, nil
+	case "none":
+		return CompressionNone, nil
+	default:
+		return CompressionSnappy, fmt.Errorf("unknown Parquet compression %q (supported: snappy, zstd, gzip, brotli, none)", s)
+	}
+}
+
+// codec returns the Parquet codec for c.
+func (c ParquetCompression) codec() compress.Compression {
+	switch c {
+	case CompressionZstd:
+		return compress.Codecs.Zstd
+	case CompressionGzip:
+		return compress.Codecs.Gzip
+	case CompressionBrotli:
+		return compress.Codecs.Brotli
+	case CompressionNone:
+		return compress.Codecs.Uncompressed
+	default:
+		return compress.Codecs.Snappy
+	}
+}
+
+// ParquetVersion selects the Parquet format version files are written as.
+type ParquetVersion int
+
+const (
+	// ParquetV2Latest writes the latest format version, 2.6 (the default).
+	ParquetV2Latest ParquetVersion = iota
+	// ParquetV1 writes format version 1.0, for older readers.
+	ParquetV1
+	// ParquetV24 writes format version 2.4.
+	ParquetV24
+)
+
+// ParseParquetVersion parses a format version: "" or "2.6", "2.4", or "1.0".
+func ParseParquetVersion(s string) (ParquetVersion, error) {
+	switch s {
+	case "", "2.6", "latest":
+		return ParquetV2Latest, nil
+	case "2.4":
+		return ParquetV24, nil
+	case "1.0":
+		return ParquetV1, nil
+	default:
+		return ParquetV2Latest, fmt.Errorf("unknown Parquet version %q (supported: 2.6, 2.4, 1.0)", s)
+	}
+}
+
+// version returns the Parquet library's version for v.
+func (v ParquetVersion) version() parquet.Version {
+	switch v {
+	case ParquetV1:
+		return parquet.V1_0
+	case ParquetV24:
+		return parquet.V2_4
+	default:
+		return parquet.V2_LATEST
+	}
+}
+
+// DefaultParquetBatchSize is the writer batch size used when
+// ParquetOptions.BatchSize is zero.
+const DefaultParquetBatchSize = 64 * 1024 * 1024
+
+// ParquetOptions tunes how records are written to Parquet before staging.
+type ParquetOptions struct {
+	// Compression is the page codec. Defaults to Snappy.
+	Compression ParquetCompression
+
+	// Version is the format version. Defaults to the latest, 2.6.
+	Version ParquetVersion
+	// BatchSize is how many values are written to a column at a time.
+	// Zero uses DefaultParquetBatchSize.
+	BatchSize int64
+	// DataPageSize is the target size of a data page in bytes, and
+	// MaxRowGroupLength the most rows in a row group. Larger row groups
+	// make fewer, bigger units for COPY to scan. Zero uses the Parquet
+	// library's defaults of 1 MiB and 64Mi rows.
+	DataPageSize      int64
+	MaxRowGroupLength int64
+	// DisableDictionary turns off dictionary encoding, which is on by
+	// default and shrinks columns with few distinct values.
+	DisableDictionary bool
+
+	// MaxRowsPerFile and MaxBytesPerFile, if positive, make
+	// WriteArrowStreamToParquetFiles start a new file once the current one
+	// holds that many rows or bytes, so COPY can load the parts in
+	// parallel. Records are sliced to honor the row limit; the byte limit is
+	// checked after each record is written, so files may exceed it by up to
+	// one record.
+	MaxRowsPerFile  int64
+	MaxBytesPerFile int64
+}
+
+// writerProperties builds the Parquet writer properties for o.
+func (o ParquetOptions) writerProperties() *parquet.WriterProperties {
+	batchSize := o.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultParquetBatchSize
+	}
+	props := []parquet.WriterProperty{
+		parquet.WithCompression(o.Compression.codec()),
+		parquet.WithBatchSize(batchSize),
+		parquet.WithVersion(o.Version.version()),
+	}
+	if o.DataPageSize > 0 {
+		props = append(props, parquet.WithDataPageSize(o.DataPageSize))
+	}
+	if o.MaxRowGroupLength > 0 {
+		props = append(props, parquet.WithMaxRowGroupLength(o.MaxRowGroupLength))
+	}
+	if o.DisableDictionary {
+		props = append(props, parquet.WithDictionaryDefault(false))
+	}
+	return parquet.NewWriterProperties(props...)
+}
+
+// Validate checks that none of the sizes are negative.
+func (o ParquetOptions) Validate() error {
+	for _, size := range []struct {
+		name  string
+		value int64
+	}{
+		{"batch size", o.BatchSize},
+		{"data page size", o.DataPageSize},
+		{"max row group length", o.MaxRowGroupLength},
+		{"max rows per file", o.MaxRowsPerFile},
+		{"max bytes per file", o.MaxBytesPerFile},
+	} {
+		if size.value < 0 {
+			return fmt.Errorf("parquet %s must be positive, got %d", size.name, size.value)
+		}
+	}
+	return nil
+}
+
+// parquetPart is a Parquet file being written by WriteArrowStreamToParquetFiles.
+type parquetPart struct {
+	file   *os.File
+	writer *pqarrow.FileWriter
+	rows   int64
+}
+
+// full reports whether the part has reached a size limit in opts.
+func (p *parquetPart) full(opts ParquetOptions) (bool, error) {
+	if opts.MaxRowsPerFile > 0 && p.rows >= opts.MaxRowsPerFile {
+		return true, nil
+	}
+	if opts.MaxBytesPerFile <= 0 {
+		return false, nil
+	}
+	info, err := p.file.Stat()
+	if err != nil {
+		return false, err
+	}
+	return info.Size() >= opts.MaxBytesPerFile, nil
+}
+
+// close finishes the part's file.
+func (p *parquetPart) close() error {
+	defer p.file.Close()
+	if err := p.writer.Close(); err != nil {
+		return fmt.Errorf("failed to close Parquet writer: %w", err)
+	}
+	return nil
+}
+
+// WriteArrowStreamToParquetFiles writes every record of src to numbered
+// Parquet files in dir (part-00001.parquet, part-00002.parquet, ...), starting
+// a new file whenever one reaches Parquet.MaxRowsPerFile or MaxBytesPerFile.
+// Every file has the schema of the first record. It returns the paths
+// written, also on error so they can be cleaned up, and the number of rows.
+// Stage them with UploadParquetFiles. If src has no records, no file is
+// created and the error wraps io.EOF.
+func (c *Client) WriteArrowStreamToParquetFiles(ctx context.Context, src RecordReader, dir string) ([]string, int64, error) {
+	var (
+		files  []string
+		rows   int64
+		schema *arrow.Schema
+		part   *parquetPart
+	)
+	fail := func(err error) ([]string, int64, error) {
+		if part != nil {
+			part.writer.Close()
+			part.file.Close()
+		}
+		return files, rows, err
+	}
+
+	for {
+		rec, err := src.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fail(fmt.Errorf("error reading Arrow record: %w", err))
+		}
+		record, err := c.prepareRecord(ctx, rec)
+		rec.Release()
+		if err != nil {
+			return fail(err)
+		}
+		if schema == nil {
+			schema = record.Schema()
+		}
+
+		// Write the record across as many parts as the row limit needs.
+		for record.NumRows() > 0 {
+			if part == nil {
+				path := filepath.Join(dir, fmt.Sprintf("part-%05d.parquet", len(files)+1))
+				file, writer, err := c.createParquetFile(schema, path)
+				if err != nil {
+					record.Release()
+					return fail(err)
+				}
+				part = &parquetPart{file: file, writer: writer}
+				files = append(files, path)
+			}
+			n := record.NumRows()
+			if c.Parquet.MaxRowsPerFile > 0 {
+				n = min(n, c.Parquet.MaxRowsPerFile-part.rows)
+			}
+			chunk := record.NewSlice(0, n)
+			err := part.writer.Write(chunk)
+			chunk.Release()
+			if err == nil {
+				rest := record.NewSlice(n, record.NumRows())
+				record.Release()
+				record = rest
+				part.rows += n
+				rows += n
+			} else {
+				err = fmt.Errorf("failed to write Arrow record to Parquet: %w", err)
+			}
+			var full bool
+			if err == nil {
+				full, err = part.full(c.Parquet)
+			}
+			if err == nil && full {
+				err = part.close()
+				part = nil
+			}
+			if err != nil {
+				record.Release()
+				return fail(err)
+			}
+		}
+		record.Release()
+	}
+
+	if part != nil {
+		if err := part.close(); err != nil {
+			part = nil
+			return fail(err)
+		}
+	}
+	if len(files) == 0 {
+		return nil, 0, fmt.Errorf("no records to write to %s: %w", dir, io.EOF)
+	}
+	c.logger(ctx).Info("Successfully wrote Arrow stream to Parquet files", zap.String("dir", dir),
+		zap.Int("files", len(files)), zap.Int64("rows", rows))
+	return files, rows, nil
+}
+
+// UploadParquetFiles uploads files, as written by
+// WriteArrowStreamToParquetFiles, to stagePath one after another with
+// UploadParquetToStage, stopping at the first failure.
+func (c *Client) UploadParquetFiles(ctx context.Context, files []string, stagePath string) error {
+	for _, f := range files {
+		if err := c.UploadParquetToStage(ctx, f, stagePath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
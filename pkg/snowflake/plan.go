@@ -0,0 +1,75 @@
+package snowflake
+
+import (
+	"context"
+	"slices"
+
+	"github.com/apache/arrow-go/v18/arrow"
+)
+
+// TargetPlan describes the target table and the statements loading a source
+// schema into it would run. Computing it changes nothing in Snowflake.
+type TargetPlan struct {
+	Exists  bool              `json:"exists"`
+	Columns []ColumnInfo      `json:"columns,omitempty"`
+	Missing []string          `json:"missing_from_source,omitempty"` // Target columns the source lacks.
+	Extra   []string          `json:"extra_in_source,omitempty"`     // Source columns the target lacks.
+	Numeric []NumericMismatch `json:"numeric_mismatches,omitempty"`
+	Begin   []string          `json:"begin,omitempty"` // Run once before loading, e.g. TRUNCATE in LoadReplace mode.
+	Put     string            `json:"put"`             // With <file> standing in for each staged file.
+	PreCopy []string          `json:"pre_copy,omitempty"`
+	Copy    string            `json:"copy"`
+	// PostCopy follows each COPY, e.g. the MERGE of LoadMerge mode, which
+	// PreCopy readies the staging table for.
+	PostCopy []string `json:"post_copy,omitempty"`
+}
+
+// Statements returns the plan's statements in the order a load with a single
+// COPY runs them.
+func (p TargetPlan) Statements() []string {
+	stmts := append(slices.Clone(p.Begin), p.Put)
+	stmts = append(stmts, p.PreCopy...)
+	stmts = append(stmts, p.Copy)
+	return append(stmts, p.PostCopy...)
+}
+
+// PlanTarget describes the target table and compares it with schema. The PUT
+// and COPY statements are rendered for stagePath as they would run.
+func (c *Client) PlanTarget(ctx context.Context, schema *arrow.Schema, stagePath string) (TargetPlan, error) {
+	if err := c.checkTargetTable(); err != nil {
+		return TargetPlan{}, err
+	}
+	plan := TargetPlan{
+		Begin: c.beginLoadStatements(),
+		Put:   putStatement("<file>", c.stageRef(stagePath), c.Upload.Parallelism, false),
+		Copy:  c.copyStatement(c.copyTarget(), c.Stage.name(), ""),
+	}
+	cols, exists, err := c.describeIfExists(ctx, c.TargetTable)
+	if err != nil {
+		return plan, err
+	}
+	// A table still to be created gets the source's columns.
+	var names []string
+	for _, f := range schema.Fields() {
+		names = append(names, f.Name)
+	}
+	if exists {
+		plan.Exists = true
+		plan.Columns = cols
+		plan.Missing, plan.Extra = columnDiff(schema, cols)
+		plan.Numeric = DecimalMismatches(schema, cols)
+		names = names[:0]
+		for _, col := range cols {
+			names = append(names, col.Name)
+		}
+	}
+	if c.Copy.Mode == LoadMerge {
+		merge, err := c.mergeStatement(c.copyTarget(), names)
+		if err != nil {
+			return plan, err
+		}
+		plan.PreCopy = c.stagingStatements()
+		plan.PostCopy = []string{merge}
+	}
+	return plan, nil
+}
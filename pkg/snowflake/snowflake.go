@@ -2,109 +2,414 @@ package snowflake
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/apache/arrow-adbc/go/adbc"
 	"github.com/apache/arrow-adbc/go/adbc/driver/snowflake"
 	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/compute"
 	"github.com/apache/arrow-go/v18/arrow/memory"
-	"github.com/apache/arrow-go/v18/parquet"
-	"github.com/apache/arrow-go/v18/parquet/compress"
 	"github.com/apache/arrow-go/v18/parquet/pqarrow"
 	"go.uber.org/zap"
+	"google.golang.org/api/option"
+	"google.golang.org/api/storage/v1"
+
+	"github.com/TFMV/syncronicity/pkg/logctx"
+	"github.com/TFMV/syncronicity/pkg/metrics"
+)
+
+const (
+	// defaultStage is the stage used when StageConfig.Name is unset.
+	defaultStage = "SYNCHRONICITY_STAGE"
 )
 
 // Client encapsulates all interactions with Snowflake.
+//
+// A Client is safe for concurrent use once configured. Operations share one
+// database handle, opened on first use with the client's settings at the
+// time, and each opens its own connection unless Connect was called, so
+// multiple transfers can share one Client. Don't modify its fields while
+// operations are in flight, and Close the client when done.
 type Client struct {
 	DSN    string
 	Logger *zap.Logger
+
+	// TargetTable is the table COPY loads and the target-table operations act
+	// on. It may be qualified as "schema.table" or "database.schema.table"
+	// and is quoted according to Quoting. Required.
+	TargetTable string
+
+	// CreateStageIfMissing makes PUT and COPY create a missing stage and retry
+	// once instead of returning ErrStageNotFound.
+	CreateStageIfMissing bool
+
+	// Network configures proxy and OCSP behavior for locked-down networks.
+	Network NetworkConfig
+
+	// Upload tunes parallelism, retries, and verification of stage uploads.
+	Upload UploadOptions
+
+	// Stage names the stage COPY loads from and sets its file format.
+	Stage StageConfig
+	// StorageOptions configure the Cloud Storage client that writes files to
+	// an external stage's bucket; see StageConfig.External.
+	StorageOptions []option.ClientOption
+
+	// ColumnTransforms rewrites the named columns (e.g. with SHA256Transform)
+	// before records are written to Parquet, so sensitive values never reach
+	// the warehouse in cleartext.
+	ColumnTransforms map[string]ColumnTransform
+
+	// Quoting selects how identifiers are quoted in generated SQL.
+	Quoting QuoteStrategy
+
+	// TableKind selects permanent, transient, temporary, or Iceberg tables in
+	// generated DDL.
+	TableKind TableKind
+	// Iceberg configures the external volume and catalog of TableIceberg tables.
+	Iceberg IcebergOptions
+	// ColumnDefaults adds DEFAULT clauses, keyed by column name, to created
+	// tables. See TranslateDefaults for carrying over BigQuery defaults.
+	ColumnDefaults map[string]string
+
+	// Parquet tunes how records are written to Parquet files.
+	Parquet ParquetOptions
+
+	// Allocator backs Parquet writing and query results. Nil uses the default
+	// allocator.
+	Allocator memory.Allocator
+
+	// TimestampCoercion truncates timestamp columns to a coarser unit before
+	// they are written to Parquet.
+	TimestampCoercion TimestampCoercion
+
+	// MissingColumns and ExtraColumns decide how CheckTargetSchema treats
+	// columns only in the target table or only in the source.
+	MissingColumns MissingColumnPolicy
+	ExtraColumns   ExtraColumnPolicy
+
+	// IntervalFormat selects how INTERVAL columns are written and typed.
+	IntervalFormat IntervalFormat
+
+	// Copy holds optional COPY INTO clauses.
+	Copy CopyOptions
+
+	// DeadLetter, if set, makes COPY skip rows it can't load (ON_ERROR =
+	// CONTINUE, overriding Copy.OnError) instead of failing, and receives those rows with their errors
+	// after each COPY that rejected any.
+	DeadLetter DeadLetterSink
+
+	// WidenNumeric lets CheckNumericFit ALTER target NUMBER columns that are too
+	// narrow for the source decimals instead of failing.
+	WidenNumeric bool
+
+	// Metrics, if set, receives the size of each staged file and the
+	// duration and loaded rows of each COPY.
+	Metrics metrics.Metrics
+
+	// Location is the time zone of the times recorded in dead-letter rows.
+	// Nil uses UTC.
+	Location *time.Location
+
+	// PrintSQLOnly makes every SQL statement (DDL, PUT, COPY, ...) be written to
+	// SQLWriter instead of executed, so it can be reviewed and run manually.
+	PrintSQLOnly bool
+	// SQLWriter receives statements in PrintSQLOnly mode. Defaults to os.Stdout.
+	SQLWriter io.Writer
+
+	// shared is the state ForTable's clients share with this one.
+	*shared
+}
+
+// shared holds the connections and services of a Client.
+type shared struct {
+	pool connPool
+
+	gcsMu sync.Mutex
+	gcs   *storage.Service // Created on first use; see storageService.
 }
 
 // NewClient creates a new Snowflake client with the provided DSN and logger.
+// Set TargetTable before loading.
 func NewClient(dsn string, logger *zap.Logger) *Client {
 	return &Client{
 		DSN:    dsn,
 		Logger: logger,
+		Copy: CopyOptions{
+			WriterConcurrency: DefaultWriterConcurrency,
+			UploadConcurrency: DefaultUploadConcurrency,
+		},
+		shared: &shared{},
+	}
+}
+
+// ForTable returns a client configured like c that loads table instead of
+// TargetTable. It shares c's connection (see Connect) and Cloud Storage
+// client, so close only c, after the clients derived from it are done.
+func (c *Client) ForTable(table string) *Client {
+	t := *c
+	t.TargetTable = table
+	return &t
+}
+
+// allocator returns the client's allocator or the default one.
+func (c *Client) allocator() memory.Allocator {
+	if c.Allocator != nil {
+		return c.Allocator
+	}
+	return memory.DefaultAllocator
+}
+
+// checkTargetTable fails with ErrNoTargetTable if TargetTable is unset.
+func (c *Client) checkTargetTable() error {
+	if strings.TrimSpace(c.TargetTable) == "" {
+		return ErrNoTargetTable
+	}
+	return nil
+}
+
+// location returns Location, defaulting to UTC.
+func (c *Client) location() *time.Location {
+	if c.Location == nil {
+		return time.UTC
+	}
+	return c.Location
+}
+
+// logger returns the client's logger annotated with ctx's correlation ID.
+func (c *Client) logger(ctx context.Context) *zap.Logger {
+	return logctx.Logger(ctx, c.Logger)
+}
+
+// openDatabase initializes the Snowflake ADBC driver with the client's DSN and network settings.
+func (c *Client) openDatabase() (adbc.Database, error) {
+	if err := c.Network.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid Snowflake network configuration: %w", err)
+	}
+	c.Network.applyProxy()
+
+	opts := c.Network.databaseOptions()
+	opts[adbc.OptionKeyURI] = c.DSN
+	db, err := snowflake.NewDriver(c.allocator()).NewDatabase(opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize Snowflake database: %w", err)
 	}
+	return db, nil
 }
 
 // LoadArrowIntoSnowflake connects to Snowflake and executes a COPY command
-// to load data from the configured stage.
-func (c *Client) LoadArrowIntoSnowflake(ctx context.Context) error {
-	// Initialize the Snowflake ADBC driver.
-	db, err := snowflake.NewDriver(memory.DefaultAllocator).NewDatabase(map[string]string{
-		adbc.OptionKeyURI: c.DSN,
-	})
+// to load data from the configured stage, returning the number of rows
+// loaded so it can be reconciled with the rows read. If COPY failed to load
+// any file or rejected any row, the count is returned with an
+// *ErrLoadErrors; use CopyStaged for every file's result. With
+// Copy.ReturnFailedOnly the count isn't known and -1 is returned.
+func (c *Client) LoadArrowIntoSnowflake(ctx context.Context) (int64, error) {
+	if err := c.BeginLoad(ctx); err != nil {
+		return 0, err
+	}
+	results, err := c.CopyStaged(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to initialize Snowflake database: %w", err)
+		return 0, err
+	}
+	var loaded int64
+	var failed []CopyFileResult
+	for _, r := range results {
+		loaded += r.RowsLoaded
+		if r.Failed() || r.ErrorsSeen > 0 {
+			failed = append(failed, r)
+		}
+	}
+	if c.Copy.ReturnFailedOnly {
+		loaded = -1
+	}
+	if len(failed) > 0 {
+		return loaded, &ErrLoadErrors{Files: failed}
+	}
+	return loaded, nil
+}
+
+// CopyStaged executes the COPY command loading the configured stage and returns
+// COPY's per-file results. Files Snowflake already loaded are skipped and not
+// reported. With Copy.ReturnFailedOnly only failed files are returned, so
+// the results don't add up to the rows loaded.
+func (c *Client) CopyStaged(ctx context.Context) ([]CopyFileResult, error) {
+	if err := c.checkTargetTable(); err != nil {
+		return nil, err
+	}
+	if err := c.Stage.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.Copy.Validate(); err != nil {
+		return nil, err
+	}
+	return c.copyStaged(ctx, c.copyStatement(c.copyTarget(), c.Stage.name(), ""))
+}
+
+// CopyFiles is CopyStaged scoped to the given files, named relative to the
+// stage as returned by StagedFile. It is meant for resuming a load that
+// partially failed without having COPY scan every staged file again.
+func (c *Client) CopyFiles(ctx context.Context, files []string) ([]CopyFileResult, error) {
+	if err := c.checkTargetTable(); err != nil {
+		return nil, err
+	}
+	if err := c.Stage.Validate(); err != nil {
+		return nil, err
+	}
+	if err := c.Copy.Validate(); err != nil {
+		return nil, err
+	}
+	if len(files) > MaxCopyFiles {
+		return nil, fmt.Errorf("COPY can list at most %d files, got %d", MaxCopyFiles, len(files))
+	}
+	return c.copyStaged(ctx, c.copyStatement(c.copyTarget(), c.Stage.name(), filesClause(files)))
+}
+
+// copyStaged runs a COPY statement built by copyStatement for copyTarget,
+// followed by a MERGE in LoadMerge mode, within Copy.Timeout.
+func (c *Client) copyStaged(ctx context.Context, query string) ([]CopyFileResult, error) {
+	if c.Copy.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Copy.Timeout)
+		defer cancel()
+	}
+	if c.Copy.Mode == LoadMerge {
+		return c.copyMerged(ctx, query)
+	}
+	return c.copyInto(ctx, query)
+}
+
+// copyInto runs a COPY statement and returns its per-file results.
+func (c *Client) copyInto(ctx context.Context, query string) ([]CopyFileResult, error) {
+	if c.sqlOnly(query) {
+		return nil, nil
 	}
-	defer db.Close()
 
-	conn, err := db.Open(ctx)
+	// Initialize the Snowflake ADBC driver.
+	conn, release, err := c.connection(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to open Snowflake connection: %w", err)
+		return nil, err
 	}
-	defer conn.Close()
+	defer release()
+	// Creating a missing stage and writing rejected rows reuse the connection.
+	ctx = c.holding(ctx)
 
 	stmt, err := conn.NewStatement()
 	if err != nil {
-		return fmt.Errorf("failed to create Snowflake statement: %w", err)
+		return nil, fmt.Errorf("failed to create Snowflake statement: %w", err)
 	}
 	defer stmt.Close()
 
 	// Set tuning options for parallelism.
-	if err = stmt.SetOption("adbc.snowflake.statement.ingest_writer_concurrency", "4"); err != nil {
-		return fmt.Errorf("failed to set writer concurrency: %w", err)
-	}
-	if err = stmt.SetOption("adbc.snowflake.statement.ingest_upload_concurrency", "8"); err != nil {
-		return fmt.Errorf("failed to set upload concurrency: %w", err)
+	if err = c.Copy.setConcurrency(stmt); err != nil {
+		return nil, err
 	}
 
 	// Execute the COPY command to load data from the stage.
-	if err = stmt.SetSqlQuery("COPY INTO foo FROM @SYNCHRONICITY_STAGE FILE_FORMAT = (TYPE = PARQUET) MATCH_BY_COLUMN_NAME=CASE_INSENSITIVE"); err != nil {
-		return fmt.Errorf("failed to set COPY command: %w", err)
+	if err = stmt.SetSqlQuery(query); err != nil {
+		return nil, fmt.Errorf("failed to set COPY command: %w", err)
+	}
+	start := time.Now()
+	results, err := c.executeCopy(ctx, stmt)
+	if isStageNotFound(err) && c.CreateStageIfMissing {
+		if err = c.EnsureStage(ctx, c.Stage.name()); err != nil {
+			return nil, err
+		}
+		start = time.Now()
+		results, err = c.executeCopy(ctx, stmt)
 	}
-	if _, err = stmt.ExecuteUpdate(ctx); err != nil {
-		return fmt.Errorf("failed to execute COPY command: %w", err)
+	if isStageNotFound(err) {
+		return nil, &ErrStageNotFound{Stage: c.Stage.name(), Err: err}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrCopyFailed, err)
+	}
+	m := metrics.Or(c.Metrics)
+	m.RecordCopyDuration(time.Since(start))
+	// Only failed files are listed with ReturnFailedOnly, so their rows
+	// aren't the rows loaded.
+	if !c.Copy.ReturnFailedOnly {
+		var loaded int64
+		for _, r := range results {
+			loaded += r.RowsLoaded
+		}
+		m.RecordRowsLoaded(loaded)
+	}
+	if len(results) > 0 {
+		queryID := c.lastQueryID(ctx, stmt)
+		for i := range results {
+			results[i].QueryID = queryID
+		}
+	}
+	if c.DeadLetter != nil && rejectedAny(results) {
+		if err := c.collectRejected(ctx, stmt); err != nil {
+			return results, err
+		}
 	}
 
-	c.Logger.Info("Arrow record successfully loaded into Snowflake")
-	return nil
+	c.logger(ctx).Info("Arrow record successfully loaded into Snowflake", zap.Int("files", len(results)))
+	return results, nil
 }
 
-// WriteArrowRecordToParquet writes the provided Arrow record to a Parquet file.
-func (c *Client) WriteArrowRecordToParquet(ctx context.Context, record arrow.Record, outputFile string) error {
-	// Ensure the directory exists
-	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
-		return fmt.Errorf("failed to create directory for Parquet file: %w", err)
+// copyStatement builds the COPY command loading Parquet files from the stage into
+// table. extra is appended verbatim for clauses such as PATTERN or
+// VALIDATION_MODE.
+func (c *Client) copyStatement(table, stage, extra string) string {
+	var query string
+	if c.Copy.Select != "" {
+		target := c.quoteIdentifier(table)
+		if len(c.Copy.Columns) > 0 {
+			cols := make([]string, len(c.Copy.Columns))
+			for i, col := range c.Copy.Columns {
+				cols[i] = c.Quoting.Quote(col)
+			}
+			target += " (" + strings.Join(cols, ", ") + ")"
+		}
+		query = fmt.Sprintf("COPY INTO %s FROM (SELECT %s FROM %s) %s", target, c.Copy.Select, c.stageRef(stage), c.Stage.fileFormatClause())
+	} else {
+		query = fmt.Sprintf("COPY INTO %s FROM %s %s", c.quoteIdentifier(table), c.stageRef(stage), c.Stage.fileFormatClause())
+		if match := c.Stage.MatchByColumnName.clause(); match != "" {
+			query += " " + match
+		}
+	}
+	if c.TableKind == TableIceberg {
+		// Rewrite the files into the table's own Parquet files on the
+		// external volume rather than registering them as they are.
+		query += " LOAD_MODE = FULL_INGEST"
+	}
+	if c.DeadLetter != nil {
+		query += " ON_ERROR = CONTINUE"
+	} else if onError := c.Copy.OnError.keyword(); onError != "" {
+		query += " ON_ERROR = " + onError
 	}
+	if opts := c.Copy.clauses(); opts != "" {
+		query += " " + opts
+	}
+	if extra != "" {
+		query += " " + extra
+	}
+	return query
+}
 
-	// Create (or overwrite) the output file.
-	file, err := os.Create(outputFile)
+// WriteArrowRecordToParquet writes the provided Arrow record to a Parquet file.
+func (c *Client) WriteArrowRecordToParquet(ctx context.Context, record arrow.Record, outputFile string) error {
+	record, err := c.prepareRecord(ctx, record)
 	if err != nil {
-		return fmt.Errorf("failed to create Parquet file: %w", err)
+		return err
 	}
-	defer file.Close()
+	defer record.Release()
 
-	// Define Parquet writer properties.
-	writerProps := parquet.NewWriterProperties(
-		parquet.WithCompression(compress.Codecs.Snappy),
-		parquet.WithBatchSize(64*1024*1024), // 64 MB batch size.
-		parquet.WithVersion(parquet.V2_LATEST),
-	)
-
-	arrowWriterProps := pqarrow.NewArrowWriterProperties(
-		pqarrow.WithStoreSchema(),
-		pqarrow.WithAllocator(memory.DefaultAllocator),
-	)
-
-	writer, err := pqarrow.NewFileWriter(record.Schema(), file, writerProps, arrowWriterProps)
+	file, writer, err := c.createParquetFile(record.Schema(), outputFile)
 	if err != nil {
-		return fmt.Errorf("failed to create Parquet writer: %w", err)
+		return err
 	}
+	defer file.Close()
 
 	// Write the Arrow record.
 	if err := writer.Write(record); err != nil {
@@ -117,12 +422,171 @@ func (c *Client) WriteArrowRecordToParquet(ctx context.Context, record arrow.Rec
 		return fmt.Errorf("failed to close Parquet writer: %w", err)
 	}
 
-	c.Logger.Info("Successfully wrote Arrow record to Parquet", zap.String("outputFile", outputFile))
+	c.logger(ctx).Info("Successfully wrote Arrow record to Parquet", zap.String("outputFile", outputFile))
 	return nil
 }
 
+// RecordReader is a source of Arrow records, such as *bigquery.BigQueryReader.
+// Read returns io.EOF after the last record; the caller owns every record
+// returned.
+type RecordReader interface {
+	Read() (arrow.Record, error)
+}
+
+// WriteArrowStreamToParquet writes every record of src to a single Parquet
+// file, one row group per record, and returns the number of rows written.
+// The file's schema is taken from the first record, and each record is
+// released once written. If src has no records, no file is created and the
+// error wraps io.EOF.
+func (c *Client) WriteArrowStreamToParquet(ctx context.Context, src RecordReader, outputFile string) (int64, error) {
+	first, err := src.Read()
+	if err == io.EOF {
+		return 0, fmt.Errorf("no records to write to %s: %w", outputFile, err)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("error reading Arrow record: %w", err)
+	}
+	record, err := c.prepareRecord(ctx, first)
+	first.Release()
+	if err != nil {
+		return 0, err
+	}
+
+	file, writer, err := c.createParquetFile(record.Schema(), outputFile)
+	if err != nil {
+		record.Release()
+		return 0, err
+	}
+	defer file.Close()
+
+	var rows int64
+	for {
+		err := writer.Write(record)
+		rows += record.NumRows()
+		record.Release()
+		if err != nil {
+			writer.Close() // best-effort cleanup
+			return 0, fmt.Errorf("failed to write Arrow record to Parquet: %w", err)
+		}
+
+		next, err := src.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			writer.Close()
+			return 0, fmt.Errorf("error reading Arrow record: %w", err)
+		}
+		record, err = c.prepareRecord(ctx, next)
+		next.Release()
+		if err != nil {
+			writer.Close()
+			return 0, err
+		}
+	}
+
+	if err := writer.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close Parquet writer: %w", err)
+	}
+	c.logger(ctx).Info("Successfully wrote Arrow stream to Parquet", zap.String("outputFile", outputFile), zap.Int64("rows", rows))
+	return rows, nil
+}
+
+// prepareRecord applies the client's column transforms, timestamp coercion,
+// and interval conversion to record. The caller keeps its reference to record
+// and must also release the returned one.
+func (c *Client) prepareRecord(ctx context.Context, record arrow.Record) (arrow.Record, error) {
+	// Mask or tokenize sensitive columns before anything touches disk.
+	masked, err := applyColumnTransforms(record, c.ColumnTransforms)
+	if err != nil {
+		return nil, err
+	}
+	defer masked.Release()
+
+	// Truncate timestamps to the configured unit so precision loss is explicit.
+	coerced, err := coerceTimestamps(compute.WithAllocator(ctx, c.allocator()), masked, c.TimestampCoercion)
+	if err != nil {
+		return nil, err
+	}
+	defer coerced.Release()
+
+	// Snowflake has no interval type, so intervals are written as strings or numbers.
+	return applyColumnTransforms(coerced, intervalTransforms(coerced.Schema(), c.IntervalFormat, c.allocator()))
+}
+
+// createParquetFile creates (or overwrites) outputFile and a Parquet writer for
+// schema on it. The caller closes the writer, then the file.
+func (c *Client) createParquetFile(schema *arrow.Schema, outputFile string) (*os.File, *pqarrow.FileWriter, error) {
+	// Ensure the directory exists
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+		return nil, nil, fmt.Errorf("failed to create directory for Parquet file: %w", err)
+	}
+
+	// Create (or overwrite) the output file.
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create Parquet file: %w", err)
+	}
+
+	writer, err := c.newParquetWriter(schema, file)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return file, writer, nil
+}
+
+// newParquetWriter creates a Parquet writer to w with the client's settings.
+func (c *Client) newParquetWriter(schema *arrow.Schema, w io.Writer) (*pqarrow.FileWriter, error) {
+	arrowWriterProps := pqarrow.NewArrowWriterProperties(
+		pqarrow.WithStoreSchema(),
+		pqarrow.WithAllocator(c.allocator()),
+	)
+	writer, err := pqarrow.NewFileWriter(schema, w, c.Parquet.writerProperties(), arrowWriterProps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Parquet writer: %w", err)
+	}
+	return writer, nil
+}
+
 // UploadParquetToStage uploads the specified Parquet file to a Snowflake stage.
+// An empty stagePath uploads to the stage named by Stage. With Stage.External
+// the file is written to the external stage's bucket instead of PUT. With
+// Upload.CleanupLocal the file is removed afterwards (see cleanupLocal).
 func (c *Client) UploadParquetToStage(ctx context.Context, filePath, stagePath string) error {
+	var err error
+	if c.Stage.external() {
+		err = c.uploadExternal(ctx, filePath, stagePath)
+	} else {
+		err = c.uploadParquet(ctx, filePath, stagePath)
+	}
+	if err == nil && !c.PrintSQLOnly {
+		if info, statErr := os.Stat(filePath); statErr == nil {
+			metrics.Or(c.Metrics).RecordBytesUploaded(info.Size())
+		}
+	}
+	if c.Upload.CleanupLocal && !c.PrintSQLOnly {
+		c.cleanupLocal(ctx, filePath, err)
+	}
+	return err
+}
+
+// cleanupLocal removes a local Parquet file after its upload. A file whose
+// upload failed is kept for debugging, unless the failure was ctx being
+// canceled, since an abandoned run has nothing to debug.
+func (c *Client) cleanupLocal(ctx context.Context, filePath string, uploadErr error) {
+	if uploadErr != nil && ctx.Err() == nil {
+		c.logger(ctx).Warn("Keeping local Parquet file after failed upload", zap.String("file", filePath))
+		return
+	}
+	if err := os.Remove(filePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		c.logger(ctx).Warn("Failed to remove local Parquet file", zap.String("file", filePath), zap.Error(err))
+	}
+}
+
+// uploadParquet PUTs filePath to stagePath, retrying and verifying according
+// to Upload.
+func (c *Client) uploadParquet(ctx context.Context, filePath, stagePath string) error {
 	// Verify file exists before attempting upload
 	if _, err := os.Stat(filePath); err != nil {
 		return fmt.Errorf("parquet file not found at %s: %w", filePath, err)
@@ -134,19 +598,21 @@ func (c *Client) UploadParquetToStage(ctx context.Context, filePath, stagePath s
 		return fmt.Errorf("failed to get absolute path: %w", err)
 	}
 
-	db, err := snowflake.NewDriver(memory.DefaultAllocator).NewDatabase(map[string]string{
-		adbc.OptionKeyURI: c.DSN,
-	})
-	if err != nil {
-		return fmt.Errorf("failed to initialize Snowflake database: %w", err)
+	if stagePath == "" {
+		stagePath = c.Stage.name()
+	}
+	ref := c.stageRef(stagePath)
+	if c.sqlOnly(putStatement(absPath, ref, c.Upload.Parallelism, false)) {
+		return nil
 	}
-	defer db.Close()
 
-	conn, err := db.Open(ctx)
+	conn, release, err := c.connection(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to open Snowflake connection: %w", err)
+		return err
 	}
-	defer conn.Close()
+	defer release()
+	// Creating a missing stage reuses the connection.
+	ctx = c.holding(ctx)
 
 	stmt, err := conn.NewStatement()
 	if err != nil {
@@ -154,22 +620,53 @@ func (c *Client) UploadParquetToStage(ctx context.Context, filePath, stagePath s
 	}
 	defer stmt.Close()
 
-	// Construct and execute the PUT command.
-	if !strings.HasPrefix(stagePath, "@") {
-		stagePath = "@" + stagePath
-	}
-	stagePath = strings.ReplaceAll(stagePath, "@@", "@")
-
-	query := fmt.Sprintf("PUT file://%s %s", absPath, stagePath)
-	if err := stmt.SetSqlQuery(query); err != nil {
-		return fmt.Errorf("failed to set PUT command: %w", err)
-	}
-	if _, err := stmt.ExecuteUpdate(ctx); err != nil {
-		return fmt.Errorf("failed to execute PUT command: %w", err)
+	// Execute the PUT command, re-uploading with OVERWRITE after a transient
+	// failure or when verification can't find the staged file.
+	attempts := max(c.Upload.MaxAttempts, 1)
+	backoff := c.Upload.backoff()
+	for attempt := 1; ; attempt++ {
+		query := putStatement(absPath, ref, c.Upload.Parallelism, attempt > 1)
+		if err := stmt.SetSqlQuery(query); err != nil {
+			return fmt.Errorf("failed to set PUT command: %w", err)
+		}
+		_, err = stmt.ExecuteUpdate(ctx)
+		if isStageNotFound(err) && c.CreateStageIfMissing {
+			if err = c.EnsureStage(ctx, stagePath); err != nil {
+				return err
+			}
+			_, err = stmt.ExecuteUpdate(ctx)
+		}
+		if isStageNotFound(err) {
+			return &ErrStageNotFound{Stage: stageName(stagePath), Err: err}
+		}
+		if isExternalStagePut(err) {
+			return fmt.Errorf("stage %s: %w: %v", stageName(stagePath), ErrExternalStage, err)
+		}
+		if err != nil {
+			err = fmt.Errorf("failed to execute PUT command: %w", err)
+		} else if c.Upload.Verify {
+			err = verifyStagedFile(ctx, conn, ref, filePath)
+		}
+		if err == nil {
+			break
+		}
+		if attempt >= attempts || ctx.Err() != nil || !retryablePut(err) {
+			return err
+		}
+		pause := backoff.Pause()
+		c.logger(ctx).Warn("Retrying Parquet upload", zap.String("file", filePath), zap.Int("attempt", attempt),
+			zap.Duration("backoff", pause), zap.Error(err))
+		t := time.NewTimer(pause)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return err
+		case <-t.C:
+		}
 	}
 
-	c.Logger.Info("Parquet file successfully uploaded to Snowflake stage",
-		zap.String("file", filePath), zap.String("stage", stagePath))
+	c.logger(ctx).Info("Parquet file successfully uploaded to Snowflake stage",
+		zap.String("file", filePath), zap.String("stage", ref))
 	return nil
 }
 
@@ -184,3 +681,72 @@ func (c *Client) ArrowToParquetStage(ctx context.Context, record arrow.Record, o
 	}
 	return nil
 }
+
+// EnsureStage creates the named internal stage if it does not already exist.
+// The external stage of Stage.External is created with its URL and
+// Stage.StorageIntegration, which is then required.
+func (c *Client) EnsureStage(ctx context.Context, stage string) error {
+	name := stageName(stage)
+	query := fmt.Sprintf("CREATE STAGE IF NOT EXISTS %s", c.quoteIdentifier(name))
+	if c.Stage.external() && name == c.Stage.name() {
+		if c.Stage.StorageIntegration == "" {
+			return fmt.Errorf("can't create external stage %s without a storage integration", name)
+		}
+		query += fmt.Sprintf(" URL = '%s' STORAGE_INTEGRATION = %s", escapeLiteral(c.Stage.External), c.Stage.StorageIntegration)
+	}
+	if _, err := c.execUpdate(ctx, query); err != nil {
+		return fmt.Errorf("failed to create stage %s: %w", name, err)
+	}
+
+	c.logger(ctx).Info("Snowflake stage ensured", zap.String("stage", name))
+	return nil
+}
+
+// execUpdate opens a connection and executes a single statement, returning the
+// affected row count. In PrintSQLOnly mode the statement is only emitted.
+func (c *Client) execUpdate(ctx context.Context, query string) (int64, error) {
+	if c.sqlOnly(query) {
+		return 0, nil
+	}
+
+	conn, release, err := c.connection(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	stmt, err := conn.NewStatement()
+	if err != nil {
+		return 0, fmt.Errorf("failed to create Snowflake statement: %w", err)
+	}
+	defer stmt.Close()
+
+	if err := stmt.SetSqlQuery(query); err != nil {
+		return 0, fmt.Errorf("failed to set SQL query: %w", err)
+	}
+	return stmt.ExecuteUpdate(ctx)
+}
+
+// sqlOnly reports whether the client is in PrintSQLOnly mode. If so, the query is
+// written to SQLWriter exactly as it would have been executed, and the caller
+// must skip execution.
+func (c *Client) sqlOnly(query string) bool {
+	if !c.PrintSQLOnly {
+		return false
+	}
+	w := c.SQLWriter
+	if w == nil {
+		w = os.Stdout
+	}
+	fmt.Fprintf(w, "%s;\n", query)
+	return true
+}
+
+// stageName strips the "@" prefix and any path suffix, leaving the bare stage name.
+func stageName(stagePath string) string {
+	name := strings.TrimLeft(stagePath, "@")
+	if i := strings.Index(name, "/"); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"go.uber.org/zap"
+)
+
+// int64Record returns a record of n rows with a single int64 column.
+func int64Record(mem memory.Allocator, n int) arrow.Record {
+	schema := arrow.NewSchema([]arrow.Field{{Name: "ID", Type: arrow.PrimitiveTypes.Int64}}, nil)
+	b := array.NewInt64Builder(mem)
+	defer b.Release()
+	for i := 0; i < n; i++ {
+		b.Append(int64(i))
+	}
+	col := b.NewArray()
+	defer col.Release()
+	return array.NewRecord(schema, []arrow.Array{col}, int64(n))
+}
+
+func TestForTable(t *testing.T) {
+	c := NewClient("dsn", zap.NewNop())
+	c.TargetTable = "orders"
+	c.Copy.Purge = true
+
+	d := c.ForTable("raw.users")
+	if d.TargetTable != "raw.users" {
+		t.Errorf("TargetTable = %q, want raw.users", d.TargetTable)
+	}
+	if c.TargetTable != "orders" {
+		t.Errorf("parent TargetTable changed to %q", c.TargetTable)
+	}
+	if !d.Copy.Purge || d.DSN != "dsn" {
+		t.Error("derived client doesn't keep the parent's configuration")
+	}
+	if d.shared != c.shared {
+		t.Error("derived client doesn't share the parent's connection")
+	}
+}
+
+// TestClientConcurrentParquetWrites shares one Client between goroutines, as
+// concurrent transfers do. Run with -race.
+func TestClientConcurrentParquetWrites(t *testing.T) {
+	mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+	defer mem.AssertSize(t, 0)
+	c := NewClient("", zap.NewNop())
+	c.Allocator = mem
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	const writers = 8
+	var wg sync.WaitGroup
+	errs := make([]error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			rec := int64Record(mem, 100+i)
+			defer rec.Release()
+			errs[i] = c.WriteArrowRecordToParquet(ctx, rec, filepath.Join(dir, fmt.Sprintf("part-%d.parquet", i)))
+		}(i)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("writer %d: %v", i, err)
+		}
+		path := filepath.Join(dir, fmt.Sprintf("part-%d.parquet", i))
+		if err := VerifyParquetFile(ctx, path, int64(100+i), true, mem); err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+// Package snowflaketest provides an in-memory snowflake.Loader for testing code
+// that drives a transfer without a Snowflake account.
+package snowflaketest
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+
+	"github.com/apache/arrow-go/v18/arrow"
+
+	"github.com/TFMV/syncronicity/pkg/snowflake"
+)
+
+// FakeClient is a snowflake.Loader that keeps records in memory instead of
+// writing real Parquet files and uploading them. A COPY moves every staged
+// record, except those in FailFiles, to the loaded set. It is safe for
+// concurrent use. Call Release when done to free the records it retains.
+type FakeClient struct {
+	// StageErr, LoadErr, TableErr, and SQLErr, when set, are returned by the
+	// corresponding methods to simulate failures.
+	StageErr error
+	LoadErr  error
+	TableErr error
+	SQLErr   error
+	// FailFiles lists staged files, as named by snowflake.StagedFile, that
+	// every COPY reports as LOAD_FAILED and leaves staged.
+	FailFiles []string
+
+	mu      sync.Mutex
+	written map[string]arrow.Record
+	staged  []stagedRecord
+	loaded  []arrow.Record
+	files   []string
+	schemas []*arrow.Schema
+	ddl     []string
+	sql     []string
+	copies  int
+	begins  int
+}
+
+var _ snowflake.Loader = (*FakeClient)(nil)
+
+type stagedRecord struct {
+	file string
+	rec  arrow.Record
+}
+
+// NewFakeClient returns an empty fake.
+func NewFakeClient() *FakeClient {
+	return &FakeClient{written: make(map[string]arrow.Record)}
+}
+
+// WriteArrowRecordToParquet retains record and creates an empty placeholder
+// file at outputFile, so code that inspects the file still finds it.
+func (f *FakeClient) WriteArrowRecordToParquet(ctx context.Context, record arrow.Record, outputFile string) error {
+	if f.StageErr != nil {
+		return f.StageErr
+	}
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(outputFile, nil, 0644); err != nil {
+		return err
+	}
+	record.Retain()
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.written[outputFile] = record
+	return nil
+}
+
+// UploadParquetToStage marks the record written to filePath as staged.
+func (f *FakeClient) UploadParquetToStage(ctx context.Context, filePath, stagePath string) error {
+	if f.StageErr != nil {
+		return f.StageErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	rec, ok := f.written[filePath]
+	if !ok {
+		return fmt.Errorf("snowflaketest: %s was not written", filePath)
+	}
+	delete(f.written, filePath)
+	f.staged = append(f.staged, stagedRecord{file: snowflake.StagedFile(stagePath, filePath), rec: rec})
+	f.files = append(f.files, filePath)
+	return nil
+}
+
+// StageName returns snowflake.Client's default stage.
+func (f *FakeClient) StageName() string {
+	return "SYNCHRONICITY_STAGE"
+}
+
+// CopyStaged moves all staged records not in FailFiles to the loaded set and
+// reports one result per file.
+func (f *FakeClient) CopyStaged(ctx context.Context) ([]snowflake.CopyFileResult, error) {
+	if f.LoadErr != nil {
+		return nil, f.LoadErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	results := make([]snowflake.CopyFileResult, 0, len(f.staged))
+	var rest []stagedRecord
+	for _, s := range f.staged {
+		r, loaded := f.copy(s)
+		results = append(results, r)
+		if !loaded {
+			rest = append(rest, s)
+		}
+	}
+	f.staged = rest
+	f.copies++
+	return results, nil
+}
+
+// CopyFiles moves the listed staged records not in FailFiles to the loaded
+// set, leaving the rest staged.
+func (f *FakeClient) CopyFiles(ctx context.Context, files []string) ([]snowflake.CopyFileResult, error) {
+	if f.LoadErr != nil {
+		return nil, f.LoadErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var results []snowflake.CopyFileResult
+	var rest []stagedRecord
+	for _, s := range f.staged {
+		if !slices.Contains(files, s.file) {
+			rest = append(rest, s)
+			continue
+		}
+		r, loaded := f.copy(s)
+		results = append(results, r)
+		if !loaded {
+			rest = append(rest, s)
+		}
+	}
+	f.staged = rest
+	f.copies++
+	return results, nil
+}
+
+// copy loads a staged record, unless it is one of FailFiles, and returns its
+// COPY result. f.mu must be held.
+func (f *FakeClient) copy(s stagedRecord) (snowflake.CopyFileResult, bool) {
+	if slices.Contains(f.FailFiles, s.file) {
+		return snowflake.CopyFileResult{
+			File:       s.file,
+			Status:     "LOAD_FAILED",
+			RowsParsed: s.rec.NumRows(),
+			ErrorsSeen: 1,
+			FirstError: "snowflaketest: file configured to fail",
+		}, false
+	}
+	f.loaded = append(f.loaded, s.rec)
+	return snowflake.CopyFileResult{
+		File:       s.file,
+		Status:     "LOADED",
+		RowsParsed: s.rec.NumRows(),
+		RowsLoaded: s.rec.NumRows(),
+	}, true
+}
+
+// EnsureTargetTable records the schema the table would be created with.
+func (f *FakeClient) EnsureTargetTable(ctx context.Context, schema *arrow.Schema) error {
+	if f.TableErr != nil {
+		return f.TableErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.schemas = append(f.schemas, schema)
+	return nil
+}
+
+// BeginLoad always succeeds; the fake's table starts empty.
+func (f *FakeClient) BeginLoad(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.begins++
+	return nil
+}
+
+// CheckTargetSchema always succeeds; the fake has no existing table.
+func (f *FakeClient) CheckTargetSchema(ctx context.Context, schema *arrow.Schema) error {
+	return nil
+}
+
+// TargetDDL returns a CREATE TABLE statement for schema when create is set.
+func (f *FakeClient) TargetDDL(ctx context.Context, schema *arrow.Schema, create bool) ([]string, error) {
+	if f.TableErr != nil {
+		return nil, f.TableErr
+	}
+	if !create {
+		return nil, nil
+	}
+	query, err := snowflake.CreateTableSQL("target", schema, snowflake.DDLOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return []string{query}, nil
+}
+
+// PlanTarget reports that the table doesn't exist, with placeholder load
+// statements.
+func (f *FakeClient) PlanTarget(ctx context.Context, schema *arrow.Schema, stagePath string) (snowflake.TargetPlan, error) {
+	if f.TableErr != nil {
+		return snowflake.TargetPlan{}, f.TableErr
+	}
+	return snowflake.TargetPlan{
+		Put:  "PUT file://<file> @" + stagePath,
+		Copy: "COPY INTO target FROM @" + stagePath,
+	}, nil
+}
+
+// ExecDDL records the statements.
+func (f *FakeClient) ExecDDL(ctx context.Context, stmts []string) error {
+	if f.TableErr != nil {
+		return f.TableErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.ddl = append(f.ddl, stmts...)
+	return nil
+}
+
+// ExecSQL records the statements and reports each as affecting no rows. With
+// SQLErr set, the first statement fails with it.
+func (f *FakeClient) ExecSQL(ctx context.Context, stmts []string) ([]snowflake.StatementResult, error) {
+	if len(stmts) == 0 {
+		return nil, nil
+	}
+	if f.SQLErr != nil {
+		return []snowflake.StatementResult{{SQL: stmts[0], RowsAffected: -1, Error: f.SQLErr.Error()}}, f.SQLErr
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.sql = append(f.sql, stmts...)
+	results := make([]snowflake.StatementResult, len(stmts))
+	for i, query := range stmts {
+		results[i] = snowflake.StatementResult{SQL: query}
+	}
+	return results, nil
+}
+
+// FitVarcharColumns reports the source lengths as the target widths.
+func (f *FakeClient) FitVarcharColumns(ctx context.Context, lengths map[string]int) (map[string]int, error) {
+	return lengths, nil
+}
+
+// DeduplicateTarget is a no-op that always succeeds.
+func (f *FakeClient) DeduplicateTarget(ctx context.Context, keys []string) error {
+	return nil
+}
+
+// DDL returns the statements passed to ExecDDL.
+func (f *FakeClient) DDL() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.ddl...)
+}
+
+// SQL returns the statements passed to ExecSQL.
+func (f *FakeClient) SQL() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.sql...)
+}
+
+// LoadedRows returns the number of rows loaded by COPYs so far.
+func (f *FakeClient) LoadedRows() int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var n int64
+	for _, rec := range f.loaded {
+		n += rec.NumRows()
+	}
+	return n
+}
+
+// LoadedRecords returns the loaded records in staging order. They remain owned
+// by the fake.
+func (f *FakeClient) LoadedRecords() []arrow.Record {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]arrow.Record(nil), f.loaded...)
+}
+
+// PendingRecords returns records staged but not yet COPYed.
+func (f *FakeClient) PendingRecords() []arrow.Record {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	recs := make([]arrow.Record, 0, len(f.staged))
+	for _, s := range f.staged {
+		recs = append(recs, s.rec)
+	}
+	return recs
+}
+
+// Files returns the local files passed to UploadParquetToStage.
+func (f *FakeClient) Files() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.files...)
+}
+
+// CreatedTables returns the schemas passed to EnsureTargetTable.
+func (f *FakeClient) CreatedTables() []*arrow.Schema {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]*arrow.Schema(nil), f.schemas...)
+}
+
+// Copies returns how many COPYs, by CopyStaged or CopyFiles, succeeded.
+func (f *FakeClient) Copies() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.copies
+}
+
+// BeginLoads returns the number of BeginLoad calls.
+func (f *FakeClient) BeginLoads() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.begins
+}
+
+// Release frees every record the fake retains.
+func (f *FakeClient) Release() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, rec := range f.written {
+		rec.Release()
+	}
+	for _, s := range f.staged {
+		s.rec.Release()
+	}
+	for _, rec := range f.loaded {
+		rec.Release()
+	}
+	f.written = make(map[string]arrow.Record)
+	f.staged, f.loaded = nil, nil
+}
@@ -0,0 +1,108 @@
+package snowflake
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// QuoteStrategy selects how identifiers are quoted in generated SQL.
+type QuoteStrategy int
+
+const (
+	// QuoteWhenNeeded leaves upper-case identifiers that are valid unquoted
+	// (and not reserved) bare, since Snowflake stores unquoted names in upper
+	// case. Every other name is quoted, so lower- and mixed-case names keep
+	// their case: a target table "foo" refers to the case-sensitive "foo",
+	// not FOO. Name objects created unquoted in upper case. This is the
+	// default.
+	QuoteWhenNeeded QuoteStrategy = iota
+	// QuoteAlways double-quotes every identifier, making it case-sensitive.
+	QuoteAlways
+)
+
+// ParseQuoteStrategy converts a config string ("when_needed" or "always") into a
+// QuoteStrategy. An empty string selects QuoteWhenNeeded.
+func ParseQuoteStrategy(s string) (QuoteStrategy, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "when_needed":
+		return QuoteWhenNeeded, nil
+	case "always":
+		return QuoteAlways, nil
+	default:
+		return QuoteWhenNeeded, fmt.Errorf("unknown identifier quoting strategy %q (supported: when_needed, always)", s)
+	}
+}
+
+// unquotedIdentifier matches identifiers that mean the same to Snowflake
+// without quotes: unquoted names are resolved in upper case.
+var unquotedIdentifier = regexp.MustCompile(`^[A-Z_][A-Z0-9_$]*$`)
+
+// validIdentifier matches names that are legal unquoted identifiers in any
+// case. Configured object names are validated against it.
+var validIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+
+// reservedWords are Snowflake keywords that can't be used as unquoted identifiers.
+var reservedWords = map[string]bool{
+	"ACCOUNT": true, "ALL": true, "ALTER": true, "AND": true, "ANY": true, "AS": true,
+	"BETWEEN": true, "BY": true, "CASE": true, "CAST": true, "CHECK": true, "COLUMN": true,
+	"CONNECT": true, "CONNECTION": true, "CONSTRAINT": true, "CREATE": true, "CROSS": true,
+	"CURRENT": true, "CURRENT_DATE": true, "CURRENT_TIME": true, "CURRENT_TIMESTAMP": true,
+	"CURRENT_USER": true, "DATABASE": true, "DELETE": true, "DISTINCT": true, "DROP": true,
+	"ELSE": true, "EXISTS": true, "FALSE": true, "FOLLOWING": true, "FOR": true, "FROM": true,
+	"FULL": true, "GRANT": true, "GROUP": true, "GSCLUSTER": true, "HAVING": true, "ILIKE": true,
+	"IN": true, "INCREMENT": true, "INNER": true, "INSERT": true, "INTERSECT": true, "INTO": true,
+	"IS": true, "ISSUE": true, "JOIN": true, "LATERAL": true, "LEFT": true, "LIKE": true,
+	"LOCALTIME": true, "LOCALTIMESTAMP": true, "MINUS": true, "NATURAL": true, "NOT": true,
+	"NULL": true, "OF": true, "ON": true, "OR": true, "ORDER": true, "ORGANIZATION": true,
+	"QUALIFY": true, "REGEXP": true, "REVOKE": true, "RIGHT": true, "RLIKE": true, "ROW": true,
+	"ROWS": true, "SAMPLE": true, "SCHEMA": true, "SELECT": true, "SET": true, "SOME": true,
+	"START": true, "TABLE": true, "TABLESAMPLE": true, "THEN": true, "TO": true, "TRIGGER": true,
+	"TRUE": true, "TRY_CAST": true, "UNION": true, "UNIQUE": true, "UPDATE": true, "USING": true,
+	"VALUES": true, "VIEW": true, "WHEN": true, "WHENEVER": true, "WHERE": true, "WITH": true,
+}
+
+// Quote renders a single identifier (a column, table, or stage name) according to
+// the strategy. Embedded double quotes are escaped by doubling them.
+func (q QuoteStrategy) Quote(name string) string {
+	if q == QuoteWhenNeeded && unquotedIdentifier.MatchString(name) && !reservedWords[name] {
+		return name
+	}
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// QuoteQualified renders a possibly qualified name such as "db.schema.table",
+// quoting each dot-separated part independently.
+func (q QuoteStrategy) QuoteQualified(name string) string {
+	parts := strings.Split(name, ".")
+	for i, p := range parts {
+		parts[i] = q.Quote(p)
+	}
+	return strings.Join(parts, ".")
+}
+
+// quoteIdentifier renders an identifier using the client's quoting strategy. All
+// generated SQL goes through it so quoting stays consistent across statements.
+func (c *Client) quoteIdentifier(name string) string {
+	return c.Quoting.QuoteQualified(name)
+}
+
+// stageRef renders a stage name or path into the "@stage[/path]" form used by
+// PUT, LIST, REMOVE, and COPY. User (@~) and table (@%t) stages are left as is.
+func (c *Client) stageRef(stagePath string) string {
+	name := strings.TrimLeft(stagePath, "@")
+	if strings.HasPrefix(name, "~") || strings.HasPrefix(name, "%") {
+		return "@" + name
+	}
+	path := ""
+	if i := strings.Index(name, "/"); i >= 0 {
+		name, path = name[:i], name[i:]
+	}
+	return "@" + c.quoteIdentifier(name) + path
+}
+
+// escapeLiteral escapes s for use inside a single-quoted SQL string, where
+// Snowflake treats backslashes as escapes.
+func escapeLiteral(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s)
+}
@@ -0,0 +1,102 @@
+package snowflake
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"go.uber.org/zap"
+)
+
+func TestQuote(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		always bool
+		want   string
+	}{
+		{name: "ORDERS", want: `ORDERS`},
+		{name: "_ID$2", want: `_ID$2`},
+		{name: "orders", want: `"orders"`},
+		{name: "userId", want: `"userId"`},
+		{name: "ORDER", want: `"ORDER"`},
+		{name: "order", want: `"order"`},
+		{name: "first name", want: `"first name"`},
+		{name: "1COL", want: `"1COL"`},
+		{name: `say "hi"`, want: `"say ""hi"""`},
+		{name: "ORDERS", always: true, want: `"ORDERS"`},
+	} {
+		q := QuoteWhenNeeded
+		if tc.always {
+			q = QuoteAlways
+		}
+		if got := q.Quote(tc.name); got != tc.want {
+			t.Errorf("Quote(%q) = %s, want %s", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestQuoteQualified(t *testing.T) {
+	for name, want := range map[string]string{
+		"DB.RAW.ORDERS":  `DB.RAW.ORDERS`,
+		"raw.Orders":     `"raw"."Orders"`,
+		"RAW.table":      `RAW."table"`,
+		"RAW.my table":   `RAW."my table"`,
+		"ANALYTICS.USER": `ANALYTICS.USER`,
+	} {
+		if got := QuoteWhenNeeded.QuoteQualified(name); got != want {
+			t.Errorf("QuoteQualified(%q) = %s, want %s", name, got, want)
+		}
+	}
+}
+
+// quotingSchema has an upper-case column, a reserved word, a name with a
+// space, and a mixed-case name.
+var quotingSchema = arrow.NewSchema([]arrow.Field{
+	{Name: "ID", Type: arrow.PrimitiveTypes.Int64},
+	{Name: "order", Type: arrow.BinaryTypes.String, Nullable: true},
+	{Name: "First Name", Type: arrow.BinaryTypes.String, Nullable: true},
+	{Name: "userId", Type: arrow.BinaryTypes.String, Nullable: true},
+}, nil)
+
+func TestQuotingInStatements(t *testing.T) {
+	c := NewClient("", zap.NewNop())
+	c.TargetTable = "raw.Orders"
+	c.Copy.MergeKeys = []string{"USERID"}
+
+	ddl, err := CreateTableSQL(c.TargetTable, quotingSchema, DDLOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	merge, err := c.mergeStatement("raw.Orders_staging", []string{"ID", "order", "First Name", "userId"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tc := range []struct {
+		statement string
+		query     string
+		want      []string
+	}{
+		{"DDL", ddl, []string{
+			`CREATE TABLE IF NOT EXISTS "raw"."Orders" (`,
+			"  ID NUMBER(38,0) NOT NULL,",
+			`  "order" VARCHAR,`,
+			`  "First Name" VARCHAR,`,
+			`  "userId" VARCHAR`,
+		}},
+		{"COPY", c.copyStatement(c.TargetTable, "@Load Stage/run", ""), []string{
+			`COPY INTO "raw"."Orders" FROM @"Load Stage"/run `,
+		}},
+		{"MERGE", merge, []string{
+			`MERGE INTO "raw"."Orders" t USING (SELECT * FROM "raw"."Orders_staging" QUALIFY ROW_NUMBER() OVER (PARTITION BY "userId" ORDER BY "userId") = 1) s ON t."userId" = s."userId"`,
+			`UPDATE SET t.ID = s.ID, t."order" = s."order", t."First Name" = s."First Name"`,
+			`INSERT (ID, "order", "First Name", "userId") VALUES (s.ID, s."order", s."First Name", s."userId")`,
+		}},
+	} {
+		for _, want := range tc.want {
+			if !strings.Contains(tc.query, want) {
+				t.Errorf("%s statement\n%s\nlacks %s", tc.statement, tc.query, want)
+			}
+		}
+	}
+}
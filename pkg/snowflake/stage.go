@@ -0,0 +1,136 @@
+package snowflake
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ColumnMatch selects how COPY maps Parquet columns to target columns.
+type ColumnMatch int
+
+const (
+	// MatchCaseInsensitive matches columns by name, ignoring case. This is
+	// the default.
+	MatchCaseInsensitive ColumnMatch = iota
+	// MatchCaseSensitive matches columns by exact name.
+	MatchCaseSensitive
+	// MatchByPosition leaves MATCH_BY_COLUMN_NAME off, so COPY loads each
+	// file's columns into the target's columns in order.
+	MatchByPosition
+)
+
+// ParseColumnMatch converts a config string ("case_insensitive",
+// "case_sensitive", or "none") into a ColumnMatch. An empty string selects
+// MatchCaseInsensitive.
+func ParseColumnMatch(s string) (ColumnMatch, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "case_insensitive":
+		return MatchCaseInsensitive, nil
+	case "case_sensitive":
+		return MatchCaseSensitive, nil
+	case "none":
+		return MatchByPosition, nil
+	default:
+		return MatchCaseInsensitive, fmt.Errorf("unknown column match mode %q (supported: case_insensitive, case_sensitive, none)", s)
+	}
+}
+
+// clause renders the MATCH_BY_COLUMN_NAME clause, or "" for MatchByPosition.
+func (m ColumnMatch) clause() string {
+	switch m {
+	case MatchCaseSensitive:
+		return "MATCH_BY_COLUMN_NAME=CASE_SENSITIVE"
+	case MatchByPosition:
+		return ""
+	default:
+		return "MATCH_BY_COLUMN_NAME=CASE_INSENSITIVE"
+	}
+}
+
+// StageConfig describes the stage files are PUT to and COPY loads from, and how
+// COPY reads them.
+type StageConfig struct {
+	// Name is the internal stage, optionally qualified as "schema.stage", or
+	// "~" for the user stage or "%table" for a table stage. Defaults to
+	// SYNCHRONICITY_STAGE.
+	Name string
+	// FileFormat names an existing Snowflake file format for COPY to use
+	// instead of the inline TYPE = PARQUET. It must describe Parquet files.
+	FileFormat string
+	// FormatOptions are added to the inline file format, e.g.
+	// "BINARY_AS_TEXT = FALSE". Ignored with FileFormat.
+	FormatOptions string
+	// MatchByColumnName selects how staged columns map to target columns. It
+	// doesn't apply to COPY with a select list.
+	MatchByColumnName ColumnMatch
+
+	// External, if set, is the gs:// URL of the external stage Name, e.g.
+	// "gs://bucket/prefix". Files are then written to that bucket with the
+	// Cloud Storage API instead of PUT, which only works for internal
+	// stages, and COPY loads them from the external stage as usual.
+	External string
+	// StorageIntegration is the storage integration EnsureStage creates an
+	// external stage with.
+	StorageIntegration string
+}
+
+// name returns the stage name, or the default stage.
+func (s StageConfig) name() string {
+	if s.Name == "" {
+		return defaultStage
+	}
+	return s.Name
+}
+
+// StageName returns the configured stage, or the default stage, which
+// UploadParquetToStage uses for an empty stage path.
+func (c *Client) StageName() string {
+	return c.Stage.name()
+}
+
+// Validate checks that the stage and file format names are legal unquoted
+// Snowflake identifiers, optionally qualified, or that the stage is the user
+// stage or a table stage, and that an external stage has a gs:// URL.
+func (s StageConfig) Validate() error {
+	name := s.name()
+	table, isTableStage := strings.CutPrefix(name, "%")
+	switch {
+	case name == "~": // The user stage.
+	case isTableStage:
+		if !isQualifiedIdentifier(table) {
+			return fmt.Errorf("table stage %q doesn't name a valid Snowflake table", s.Name)
+		}
+	case !isQualifiedIdentifier(name):
+		return fmt.Errorf("stage name %q is not a valid Snowflake identifier", s.Name)
+	}
+	if s.FileFormat != "" && !isQualifiedIdentifier(s.FileFormat) {
+		return fmt.Errorf("file format name %q is not a valid Snowflake identifier", s.FileFormat)
+	}
+	return s.validateExternal()
+}
+
+// fileFormatClause renders COPY's FILE_FORMAT clause.
+func (s StageConfig) fileFormatClause() string {
+	if s.FileFormat != "" {
+		return fmt.Sprintf("FILE_FORMAT = (FORMAT_NAME = '%s')", escapeLiteral(s.FileFormat))
+	}
+	if opts := strings.TrimSpace(s.FormatOptions); opts != "" {
+		return "FILE_FORMAT = (TYPE = PARQUET " + opts + ")"
+	}
+	return "FILE_FORMAT = (TYPE = PARQUET)"
+}
+
+// isQualifiedIdentifier reports whether name is one to three dot-separated
+// identifiers that are valid unquoted, in any case.
+func isQualifiedIdentifier(name string) bool {
+	parts := strings.Split(name, ".")
+	if len(parts) > 3 {
+		return false
+	}
+	for _, p := range parts {
+		if !validIdentifier.MatchString(p) {
+			return false
+		}
+	}
+	return true
+}
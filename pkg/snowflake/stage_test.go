@@ -0,0 +1,35 @@
+package snowflake
+
+import "testing"
+
+func TestStageConfigValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name  string
+		stage StageConfig
+		ok    bool
+	}{
+		{"default", StageConfig{}, true},
+		{"upper case", StageConfig{Name: "LOAD_STAGE"}, true},
+		{"lower case", StageConfig{Name: "my_stage"}, true},
+		{"qualified lower case", StageConfig{Name: "raw.my_stage"}, true},
+		{"mixed case", StageConfig{Name: "Db.Raw.MyStage"}, true},
+		{"user stage", StageConfig{Name: "~"}, true},
+		{"lower-case table stage", StageConfig{Name: "%orders"}, true},
+		{"lower-case file format", StageConfig{Name: "my_stage", FileFormat: "my_parquet"}, true},
+		{"lower-case storage integration", StageConfig{Name: "ext", External: "gs://bucket/prefix", StorageIntegration: "gcs_int"}, true},
+		{"space", StageConfig{Name: "my stage"}, false},
+		{"leading digit", StageConfig{Name: "1stage"}, false},
+		{"too qualified", StageConfig{Name: "a.b.c.d"}, false},
+		{"quote", StageConfig{Name: `my"stage`}, false},
+		{"bad table stage", StageConfig{Name: "%bad table"}, false},
+		{"bad file format", StageConfig{FileFormat: "my-format"}, false},
+		{"bad storage integration", StageConfig{Name: "ext", External: "gs://bucket", StorageIntegration: "gcs-int"}, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.stage.Validate()
+			if (err == nil) != tc.ok {
+				t.Errorf("Validate() = %v, want ok %t", err, tc.ok)
+			}
+		})
+	}
+}
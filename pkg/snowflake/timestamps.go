@@ -0,0 +1,88 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/compute"
+)
+
+// TimestampCoercion selects the unit timestamp columns are truncated to before
+// they are written to Parquet. Nanosecond timestamps otherwise reach Snowflake
+// as-is and are truncated inconsistently along the way; coercing explicitly
+// makes the precision loss deterministic.
+type TimestampCoercion int
+
+const (
+	// TimestampAsIs writes timestamps in their source unit (the default).
+	TimestampAsIs TimestampCoercion = iota
+	// TimestampMillis truncates finer timestamps to milliseconds.
+	TimestampMillis
+	// TimestampMicros truncates finer timestamps to microseconds.
+	TimestampMicros
+)
+
+// ParseTimestampCoercion parses a coercion name: "" or "none", "millis", or "micros".
+func ParseTimestampCoercion(s string) (TimestampCoercion, error) {
+	switch s {
+	case "", "none":
+		return TimestampAsIs, nil
+	case "millis":
+		return TimestampMillis, nil
+	case "micros":
+		return TimestampMicros, nil
+	default:
+		return TimestampAsIs, fmt.Errorf("unknown timestamp coercion %q (want none, millis, or micros)", s)
+	}
+}
+
+// unit returns the target time unit, and false for TimestampAsIs.
+func (t TimestampCoercion) unit() (arrow.TimeUnit, bool) {
+	switch t {
+	case TimestampMillis:
+		return arrow.Millisecond, true
+	case TimestampMicros:
+		return arrow.Microsecond, true
+	default:
+		return 0, false
+	}
+}
+
+// coerceTimestamps returns a record whose top-level timestamp columns finer than
+// the target unit are cast down to it with Arrow compute, truncating the extra
+// digits toward zero. Columns already at or coarser than the unit are kept, as
+// is each column's time zone. The caller must release the returned record; the
+// input record is not released.
+func coerceTimestamps(ctx context.Context, rec arrow.Record, coercion TimestampCoercion) (arrow.Record, error) {
+	unit, ok := coercion.unit()
+	if !ok {
+		rec.Retain()
+		return rec, nil
+	}
+
+	schema := rec.Schema()
+	fields := append([]arrow.Field{}, schema.Fields()...)
+	cols := make([]arrow.Array, len(fields))
+	for i, f := range fields {
+		ts, ok := f.Type.(*arrow.TimestampType)
+		if !ok || ts.Unit <= unit {
+			cols[i] = rec.Column(i)
+			cols[i].Retain()
+			continue
+		}
+		target := &arrow.TimestampType{Unit: unit, TimeZone: ts.TimeZone}
+		out, err := compute.CastArray(ctx, rec.Column(i), compute.UnsafeCastOptions(target))
+		if err != nil {
+			releaseArrays(cols[:i])
+			return nil, fmt.Errorf("failed to coerce timestamp column %q to %s: %w", f.Name, unit, err)
+		}
+		cols[i] = out
+		fields[i].Type = target
+	}
+	defer releaseArrays(cols)
+
+	md := schema.Metadata()
+	return array.NewRecord(arrow.NewSchema(fields, &md), cols, rec.NumRows()), nil
+}
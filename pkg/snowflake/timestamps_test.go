@@ -0,0 +1,83 @@
+package snowflake
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+func TestParseTimestampCoercion(t *testing.T) {
+	for s, want := range map[string]TimestampCoercion{"": TimestampAsIs, "none": TimestampAsIs, "millis": TimestampMillis, "micros": TimestampMicros} {
+		if got, err := ParseTimestampCoercion(s); err != nil || got != want {
+			t.Errorf("ParseTimestampCoercion(%q) = %v, %v, want %v", s, got, err, want)
+		}
+	}
+	if _, err := ParseTimestampCoercion("seconds"); err == nil {
+		t.Error("ParseTimestampCoercion(seconds) succeeded, want an error")
+	}
+}
+
+// timestampRecord returns a record with a nanosecond UTC timestamp column, a
+// second-precision timestamp column, and an int64 column.
+func timestampRecord(mem memory.Allocator) arrow.Record {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "ns", Type: &arrow.TimestampType{Unit: arrow.Nanosecond, TimeZone: "UTC"}, Nullable: true},
+		{Name: "s", Type: &arrow.TimestampType{Unit: arrow.Second}},
+		{Name: "n", Type: arrow.PrimitiveTypes.Int64},
+	}, nil)
+	b := array.NewRecordBuilder(mem, schema)
+	defer b.Release()
+	b.Field(0).(*array.TimestampBuilder).AppendValues([]arrow.Timestamp{1_234_567_891, -1_500_000}, nil)
+	b.Field(0).(*array.TimestampBuilder).AppendNull()
+	b.Field(1).(*array.TimestampBuilder).AppendValues([]arrow.Timestamp{1, 2, 3}, nil)
+	b.Field(2).(*array.Int64Builder).AppendValues([]int64{1_234_567_891, 2, 3}, nil)
+	return b.NewRecord()
+}
+
+func TestCoerceTimestamps(t *testing.T) {
+	for _, tc := range []struct {
+		coercion TimestampCoercion
+		unit     arrow.TimeUnit
+		want     []arrow.Timestamp
+	}{
+		{TimestampAsIs, arrow.Nanosecond, []arrow.Timestamp{1_234_567_891, -1_500_000, 0}},
+		{TimestampMicros, arrow.Microsecond, []arrow.Timestamp{1_234_567, -1_500, 0}},
+		{TimestampMillis, arrow.Millisecond, []arrow.Timestamp{1_234, -1, 0}},
+	} {
+		t.Run(tc.unit.String(), func(t *testing.T) {
+			mem := memory.NewCheckedAllocator(memory.NewGoAllocator())
+			defer mem.AssertSize(t, 0)
+			rec := timestampRecord(mem)
+			defer rec.Release()
+
+			out, err := coerceTimestamps(context.Background(), rec, tc.coercion)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer out.Release()
+
+			ns := out.Schema().Field(0).Type.(*arrow.TimestampType)
+			if ns.Unit != tc.unit || ns.TimeZone != "UTC" {
+				t.Errorf("ns column is %s, want unit %s in UTC", ns, tc.unit)
+			}
+			col := out.Column(0).(*array.Timestamp)
+			if got := col.TimestampValues(); !slices.Equal(got[:2], tc.want[:2]) {
+				t.Errorf("ns values = %v, want %v", got[:2], tc.want[:2])
+			}
+			if !col.IsNull(2) {
+				t.Error("null timestamp lost its null")
+			}
+			// Coarser timestamps and other types are left alone.
+			if s := out.Schema().Field(1).Type.(*arrow.TimestampType); s.Unit != arrow.Second {
+				t.Errorf("s column coerced to %s", s.Unit)
+			}
+			if !arrow.TypeEqual(out.Schema().Field(2).Type, arrow.PrimitiveTypes.Int64) {
+				t.Errorf("n column changed to %s", out.Schema().Field(2).Type)
+			}
+		})
+	}
+}
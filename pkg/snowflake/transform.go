@@ -0,0 +1,92 @@
+package snowflake
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// ColumnTransform rewrites a column before it is written to Parquet, e.g. to hash
+// or tokenize sensitive values. It must return an array with the same length; the
+// returned array is owned (and released) by the caller.
+type ColumnTransform func(arrow.Array) (arrow.Array, error)
+
+// SHA256Transform replaces every value with the hex-encoded SHA-256 digest of its
+// string form (raw bytes for binary columns). Nulls stay null.
+func SHA256Transform(arr arrow.Array) (arrow.Array, error) {
+	b := array.NewStringBuilder(memory.DefaultAllocator)
+	defer b.Release()
+	b.Reserve(arr.Len())
+
+	for i := 0; i < arr.Len(); i++ {
+		if arr.IsNull(i) {
+			b.AppendNull()
+			continue
+		}
+		var sum [sha256.Size]byte
+		switch a := arr.(type) {
+		case *array.String:
+			sum = sha256.Sum256([]byte(a.Value(i)))
+		case *array.Binary:
+			sum = sha256.Sum256(a.Value(i))
+		default:
+			sum = sha256.Sum256([]byte(arr.ValueStr(i)))
+		}
+		b.Append(hex.EncodeToString(sum[:]))
+	}
+	return b.NewArray(), nil
+}
+
+// applyColumnTransforms returns a record with the named columns rewritten. The
+// caller must release the returned record; the input record is not released.
+func applyColumnTransforms(rec arrow.Record, transforms map[string]ColumnTransform) (arrow.Record, error) {
+	if len(transforms) == 0 {
+		rec.Retain()
+		return rec, nil
+	}
+
+	schema := rec.Schema()
+	for name := range transforms {
+		if len(schema.FieldIndices(name)) == 0 {
+			return nil, fmt.Errorf("column transform references unknown column %q", name)
+		}
+	}
+
+	fields := append([]arrow.Field{}, schema.Fields()...)
+	cols := make([]arrow.Array, len(fields))
+	for i, f := range fields {
+		fn, ok := transforms[f.Name]
+		if !ok {
+			cols[i] = rec.Column(i)
+			cols[i].Retain()
+			continue
+		}
+		out, err := fn(rec.Column(i))
+		if err != nil {
+			releaseArrays(cols[:i])
+			return nil, fmt.Errorf("column transform for %q failed: %w", f.Name, err)
+		}
+		if out.Len() != int(rec.NumRows()) {
+			out.Release()
+			releaseArrays(cols[:i])
+			return nil, fmt.Errorf("column transform for %q changed the row count from %d to %d", f.Name, rec.NumRows(), out.Len())
+		}
+		cols[i] = out
+		fields[i].Type = out.DataType()
+	}
+	defer releaseArrays(cols)
+
+	md := schema.Metadata()
+	return array.NewRecord(arrow.NewSchema(fields, &md), cols, rec.NumRows()), nil
+}
+
+// releaseArrays releases every array in the slice.
+func releaseArrays(arrs []arrow.Array) {
+	for _, a := range arrs {
+		a.Release()
+	}
+}
@@ -0,0 +1,173 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-adbc/go/adbc"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/googleapis/gax-go/v2"
+)
+
+// UploadOptions tunes how Parquet files are PUT to an internal stage.
+//
+// PUT can't resume a partially uploaded file, but it splits large files into
+// chunks uploaded on Parallelism threads and the driver retries failed chunks
+// internally. MaxAttempts adds whole-file retries on top of that.
+type UploadOptions struct {
+	// Parallelism is PUT's PARALLEL option. Zero uses the Snowflake default (4).
+	Parallelism int
+	// MaxAttempts is the total number of PUT attempts. Retries re-upload the file
+	// with OVERWRITE = TRUE. Zero or one disables retries. Only transient
+	// failures are retried; see retryablePut, and retryableObjectWrite for
+	// external stages.
+	MaxAttempts int
+	// InitialBackoff and MaxBackoff pace retries: the pause before each one
+	// starts at InitialBackoff and doubles, with jitter, up to MaxBackoff.
+	// Zero uses DefaultUploadInitialBackoff and DefaultUploadMaxBackoff.
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	// Verify lists the stage after each PUT and re-uploads if the file is missing
+	// or empty, so a bad upload is caught before COPY.
+	Verify bool
+	// CleanupLocal removes each local Parquet file once its PUT succeeds. Files
+	// that fail to upload are kept and their paths logged.
+	CleanupLocal bool
+}
+
+// Default PUT retry backoff, used when UploadOptions leaves it zero.
+const (
+	DefaultUploadInitialBackoff = time.Second
+	DefaultUploadMaxBackoff     = 30 * time.Second
+)
+
+// Validate checks that the retry settings aren't negative.
+func (o UploadOptions) Validate() error {
+	if o.MaxAttempts < 0 {
+		return fmt.Errorf("upload attempts must be positive, got %d", o.MaxAttempts)
+	}
+	if o.InitialBackoff < 0 || o.MaxBackoff < 0 {
+		return fmt.Errorf("upload backoff must be positive, got %s and %s", o.InitialBackoff, o.MaxBackoff)
+	}
+	return nil
+}
+
+// backoff returns the pacing of one file's PUT retries.
+func (o UploadOptions) backoff() gax.Backoff {
+	b := gax.Backoff{
+		Initial:    o.InitialBackoff,
+		Max:        o.MaxBackoff,
+		Multiplier: 2,
+	}
+	if b.Initial == 0 {
+		b.Initial = DefaultUploadInitialBackoff
+	}
+	if b.Max == 0 {
+		b.Max = DefaultUploadMaxBackoff
+	}
+	return b
+}
+
+// retryablePut reports whether a failed PUT may succeed if repeated: it
+// failed for a transient reason such as a dropped connection, not because
+// the credentials or privileges are bad or the local file is missing.
+func retryablePut(err error) bool {
+	var adbcErr adbc.Error
+	if errors.As(err, &adbcErr) {
+		switch adbcErr.Code {
+		case adbc.StatusUnauthenticated, adbc.StatusUnauthorized, adbc.StatusNotFound,
+			adbc.StatusInvalidArgument, adbc.StatusNotImplemented:
+			return false
+		}
+		// SQLSTATE classes 28 (invalid authorization) and 42 (syntax error
+		// or access rule violation) won't change on a retry.
+		switch string(adbcErr.SqlState[:2]) {
+		case "28", "42":
+			return false
+		}
+	}
+	msg := strings.ToLower(err.Error())
+	return !strings.Contains(msg, "no such file") && !strings.Contains(msg, "file does not exist")
+}
+
+// putStatement builds the PUT command for a local file. stage is a rendered
+// stage reference (see Client.stageRef).
+func putStatement(absPath, stage string, parallelism int, overwrite bool) string {
+	query := fmt.Sprintf("PUT file://%s %s", absPath, stage)
+	if parallelism > 0 {
+		query += fmt.Sprintf(" PARALLEL = %d", parallelism)
+	}
+	if overwrite {
+		query += " OVERWRITE = TRUE"
+	}
+	return query
+}
+
+// verifyStagedFile checks that LIST finds the uploaded file on the stage with a
+// non-zero size. stage is a rendered stage reference (see Client.stageRef).
+func verifyStagedFile(ctx context.Context, conn adbc.Connection, stage, fileName string) error {
+	stmt, err := conn.NewStatement()
+	if err != nil {
+		return fmt.Errorf("failed to create statement for LIST: %w", err)
+	}
+	defer stmt.Close()
+
+	if err := stmt.SetSqlQuery(fmt.Sprintf("LIST %s %s", stage, stagedFilePattern(fileName))); err != nil {
+		return fmt.Errorf("failed to set LIST command: %w", err)
+	}
+	rdr, _, err := stmt.ExecuteQuery(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list stage: %w", err)
+	}
+	defer rdr.Release()
+
+	found := false
+	for rdr.Next() {
+		rec := rdr.Record()
+		idx := rec.Schema().FieldIndices("size")
+		if len(idx) == 0 {
+			return fmt.Errorf("unexpected LIST result schema: %s", rec.Schema())
+		}
+		for i := 0; i < int(rec.NumRows()); i++ {
+			found = true
+			if size, ok := int64At(rec.Column(idx[0]), i); !ok || size <= 0 {
+				return fmt.Errorf("staged file %s is empty or has an unreadable size", fileName)
+			}
+		}
+	}
+	if err := rdr.Err(); err != nil {
+		return fmt.Errorf("failed to read LIST results: %w", err)
+	}
+	if !found {
+		return fmt.Errorf("staged file %s not found after upload", fileName)
+	}
+	return nil
+}
+
+// int64At reads an integer value from a result column, which Snowflake may
+// return as an integer, a decimal, or a string depending on the result format.
+func int64At(col arrow.Array, i int) (int64, bool) {
+	if col.IsNull(i) {
+		return 0, false
+	}
+	switch c := col.(type) {
+	case *array.Int64:
+		return c.Value(i), true
+	case *array.Int32:
+		return int64(c.Value(i)), true
+	case *array.Float64:
+		return int64(c.Value(i)), true
+	case *array.Decimal128:
+		return int64(c.Value(i).LowBits()), true
+	case *array.String:
+		n, err := strconv.ParseInt(c.Value(i), 10, 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,92 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"go.uber.org/zap"
+)
+
+// stagedFilePattern returns a COPY/REMOVE PATTERN matching a file PUT to a stage,
+// including the ".gz" suffix PUT may add when auto-compressing. Backslashes are
+// doubled because Snowflake string literals treat them as escapes.
+func stagedFilePattern(fileName string) string {
+	re := regexp.QuoteMeta(filepath.Base(fileName)) + `(\.gz)?`
+	return fmt.Sprintf("PATTERN = '.*%s'", strings.ReplaceAll(re, `\`, `\\`))
+}
+
+// ValidateStagedFile runs COPY in VALIDATION_MODE = RETURN_ERRORS against a single
+// staged file. No data is loaded; the returned slice holds one message per
+// rejected row and is empty when the file would load cleanly.
+func (c *Client) ValidateStagedFile(ctx context.Context, stagePath, fileName string) ([]string, error) {
+	if err := c.checkTargetTable(); err != nil {
+		return nil, err
+	}
+	if c.Copy.Select != "" {
+		return nil, fmt.Errorf("VALIDATION_MODE doesn't support COPY with a select list")
+	}
+	query := c.copyStatement(c.TargetTable, stagePath, stagedFilePattern(fileName)+" VALIDATION_MODE = RETURN_ERRORS")
+	if c.sqlOnly(query) {
+		return nil, nil
+	}
+
+	conn, release, err := c.connection(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	stmt, err := conn.NewStatement()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create statement for validation: %w", err)
+	}
+	defer stmt.Close()
+
+	if err := stmt.SetSqlQuery(query); err != nil {
+		return nil, fmt.Errorf("failed to set validation COPY command: %w", err)
+	}
+	rdr, _, err := stmt.ExecuteQuery(ctx)
+	if isStageNotFound(err) {
+		return nil, &ErrStageNotFound{Stage: stageName(stagePath), Err: err}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute validation COPY command: %w", err)
+	}
+	defer rdr.Release()
+
+	var problems []string
+	for rdr.Next() {
+		rec := rdr.Record()
+		idx := rec.Schema().FieldIndices("ERROR")
+		if len(idx) == 0 {
+			continue
+		}
+		col, ok := rec.Column(idx[0]).(*array.String)
+		if !ok {
+			continue
+		}
+		for i := 0; i < col.Len(); i++ {
+			if col.IsValid(i) {
+				problems = append(problems, col.Value(i))
+			}
+		}
+	}
+	if err := rdr.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read validation results: %w", err)
+	}
+
+	c.logger(ctx).Info("Validated staged file", zap.String("file", fileName), zap.Int("errors", len(problems)))
+	return problems, nil
+}
+
+// RemoveStagedFile deletes a file previously PUT to the stage.
+func (c *Client) RemoveStagedFile(ctx context.Context, stagePath, fileName string) error {
+	if _, err := c.execUpdate(ctx, fmt.Sprintf("REMOVE %s %s", c.stageRef(stagePath), stagedFilePattern(fileName))); err != nil {
+		return fmt.Errorf("failed to remove staged file: %w", err)
+	}
+	return nil
+}
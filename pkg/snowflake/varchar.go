@@ -0,0 +1,97 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"go.uber.org/zap"
+)
+
+// maxVarcharLength is the default maximum length of a Snowflake VARCHAR, which
+// is also what a VARCHAR without a length (as generated by SnowflakeType) gets.
+const maxVarcharLength = 16777216
+
+// varcharType matches Snowflake's VARCHAR(n) type as reported by DESCRIBE TABLE.
+var varcharType = regexp.MustCompile(`^VARCHAR\((\d+)\)$`)
+
+// MaxStringLengths returns, for each string column of rec, the length in
+// characters of its longest value. Snowflake VARCHAR lengths count characters,
+// not bytes.
+func MaxStringLengths(rec arrow.Record) map[string]int {
+	lengths := make(map[string]int)
+	for i, f := range rec.Schema().Fields() {
+		col, ok := rec.Column(i).(*array.String)
+		if !ok {
+			continue
+		}
+		longest := 0
+		for j := 0; j < col.Len(); j++ {
+			if col.IsValid(j) {
+				longest = max(longest, utf8.RuneCountInString(col.Value(j)))
+			}
+		}
+		lengths[f.Name] = longest
+	}
+	return lengths
+}
+
+// FitVarcharColumns widens the target table's VARCHAR(n) columns that are too
+// narrow for the given source lengths (see MaxStringLengths) with ALTER TABLE,
+// so COPY doesn't fail on strings that are too long. Columns match by name,
+// case-insensitively. It returns the target's VARCHAR widths afterwards, keyed
+// by source column name, so callers can skip the check until a longer string
+// shows up. A table that doesn't exist yet is skipped, as is the whole check in
+// PrintSQLOnly mode; both report the source lengths as the widths.
+func (c *Client) FitVarcharColumns(ctx context.Context, lengths map[string]int) (map[string]int, error) {
+	if err := c.checkTargetTable(); err != nil {
+		return nil, err
+	}
+	if c.PrintSQLOnly {
+		return lengths, nil
+	}
+	cols, ok, err := c.describeIfExists(ctx, c.TargetTable)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return lengths, nil
+	}
+
+	byName := make(map[string]ColumnInfo, len(cols))
+	for _, col := range cols {
+		byName[strings.ToUpper(col.Name)] = col
+	}
+	widths := make(map[string]int, len(lengths))
+	var alters []string
+	for name, need := range lengths {
+		col, ok := byName[strings.ToUpper(name)]
+		if !ok {
+			continue
+		}
+		m := varcharType.FindStringSubmatch(col.Type)
+		if m == nil {
+			continue
+		}
+		width, _ := strconv.Atoi(m[1])
+		if need > width {
+			if need > maxVarcharLength {
+				return nil, fmt.Errorf("column %s: string of %d characters exceeds Snowflake's maximum VARCHAR length of %d", name, need, maxVarcharLength)
+			}
+			alters = append(alters, fmt.Sprintf("%s %s ALTER COLUMN %s SET DATA TYPE VARCHAR(%d)",
+				c.alterTable(), c.quoteIdentifier(c.TargetTable), c.Quoting.Quote(col.Name), need))
+			c.logger(ctx).Info("Widening VARCHAR column", zap.String("column", col.Name), zap.Int("from", width), zap.Int("to", need))
+			width = need
+		}
+		widths[name] = width
+	}
+	if err := c.ExecDDL(ctx, alters); err != nil {
+		return nil, err
+	}
+	return widths, nil
+}
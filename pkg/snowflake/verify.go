@@ -0,0 +1,46 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/arrow-go/v18/parquet/file"
+	"github.com/apache/arrow-go/v18/parquet/pqarrow"
+)
+
+// VerifyParquetFile re-opens a written Parquet file and checks that its footer
+// and Arrow schema can be read and that it holds wantRows rows. With
+// readRowGroup set, the first row group is also decoded, which catches
+// corrupt column data at the cost of reading it back. A nil allocator uses
+// the default one.
+func VerifyParquetFile(ctx context.Context, path string, wantRows int64, readRowGroup bool, mem memory.Allocator) error {
+	if mem == nil {
+		mem = memory.DefaultAllocator
+	}
+	rdr, err := file.OpenParquetFile(path, false)
+	if err != nil {
+		return fmt.Errorf("failed to open Parquet file %s: %w", path, err)
+	}
+	defer rdr.Close()
+
+	if n := rdr.NumRows(); n != wantRows {
+		return fmt.Errorf("Parquet file %s holds %d rows, want %d", path, n, wantRows)
+	}
+	fr, err := pqarrow.NewFileReader(rdr, pqarrow.ArrowReadProperties{}, mem)
+	if err != nil {
+		return fmt.Errorf("failed to read Parquet metadata of %s: %w", path, err)
+	}
+	if _, err := fr.Schema(); err != nil {
+		return fmt.Errorf("failed to read Arrow schema of %s: %w", path, err)
+	}
+	if !readRowGroup || rdr.NumRowGroups() == 0 {
+		return nil
+	}
+	tbl, err := fr.RowGroup(0).ReadTable(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to read the first row group of %s: %w", path, err)
+	}
+	tbl.Release()
+	return nil
+}